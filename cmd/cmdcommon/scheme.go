@@ -0,0 +1,32 @@
+package cmdcommon
+
+import (
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	networkaddons "github.com/kubevirt/cluster-network-addons-operator/pkg/apis"
+	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	openshiftconfigv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
+	csvv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis"
+)
+
+// ResourcesSchemeFuncs registers the types of all the resources that HCO manages
+var ResourcesSchemeFuncs = []func(*apiruntime.Scheme) error{
+	apis.AddToScheme,
+	cdiv1beta1.AddToScheme,
+	networkaddons.AddToScheme,
+	sspv1beta1.AddToScheme,
+	csvv1alpha1.AddToScheme,
+	vmimportv1beta1.AddToScheme,
+	admissionregistrationv1.AddToScheme,
+	consolev1.AddToScheme,
+	openshiftconfigv1.AddToScheme,
+	monitoringv1.AddToScheme,
+	apiextensionsv1.AddToScheme,
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/diagnostics"
+)
+
+const defaultArchiveName = "hco-diagnostics.tar"
+
+// runCollect collects the state of HCO from the cluster of the current kubeconfig context, into a tar archive
+func runCollect(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("collect", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	outFile := flags.String("o", defaultArchiveName, "the archive file; '-' for the standard output")
+	namespace := flags.String("namespace", defaultNamespace, "the namespace of HCO")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "can't access the cluster: %v\n", err)
+		return exitError
+	}
+
+	collectScheme, err := getScheme()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to add to scheme: %v\n", err)
+		return exitError
+	}
+
+	cl, err := client.New(cfg, client.Options{Scheme: collectScheme})
+	if err != nil {
+		fmt.Fprintf(stderr, "can't create the client: %v\n", err)
+		return exitError
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "can't create the client: %v\n", err)
+		return exitError
+	}
+
+	out := stdout
+	if *outFile != "-" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			fmt.Fprintf(stderr, "can't create the archive file: %v\n", err)
+			return exitError
+		}
+		defer file.Close()
+		out = file
+	}
+
+	collector := diagnostics.NewCollector(cl, diagnostics.NewPodLogsReader(clientset), *namespace)
+	if err = collector.Collect(context.Background(), out); err != nil {
+		fmt.Fprintf(stderr, "can't write the archive: %v\n", err)
+		return exitError
+	}
+
+	if *outFile != "-" {
+		fmt.Fprintf(stderr, "wrote the HCO state to %s\n", *outFile)
+	}
+
+	return exitOK
+}
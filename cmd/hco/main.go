@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/cmd/cmdcommon"
+)
+
+const usage = `hco is a command line tool for the HyperConverged Cluster Operator.
+
+Usage:
+  hco <command> [flags]
+
+Commands:
+  render    render the operand resources of a HyperConverged manifest, without accessing the cluster
+  collect   collect the state of HCO, the resources it manages and its logs, into a tar archive
+
+Use "hco <command> -h" for the flags of a command.
+`
+
+// exit codes
+const (
+	exitOK = iota
+	// the rendered resources are different from the exported ones
+	exitDiff
+	exitError
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitError)
+	}
+
+	var exitCode int
+	switch os.Args[1] {
+	case "render":
+		exitCode = runRender(os.Args[2:], os.Stdout, os.Stderr)
+	case "collect":
+		exitCode = runCollect(os.Args[2:], os.Stdout, os.Stderr)
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		exitCode = exitError
+	}
+
+	os.Exit(exitCode)
+}
+
+// getScheme returns a scheme with the types of all the resources that HCO manages, as in the operator
+func getScheme() (*apiruntime.Scheme, error) {
+	s := scheme.Scheme
+	for _, f := range cmdcommon.ResourcesSchemeFuncs {
+		if err := f(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+const defaultNamespace = "kubevirt-hyperconverged"
+
+// runRender renders the operand resources of a HyperConverged manifest. If a directory of exported resources is
+// given, the differences from the exported resources are printed instead.
+func runRender(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	hcFile := flags.String("f", "", "HyperConverged manifest file; '-' for the standard input")
+	envFile := flags.String("env-file", "", "file of the HCO environment variables, in the KEY=VALUE format. The variables of the current environment are used as well")
+	namespace := flags.String("namespace", defaultNamespace, "the namespace of HCO, if it is not set in the HyperConverged manifest")
+	capabilitiesList := flags.String("capabilities", joinCapabilities(hcoutil.Capabilities), "comma separated list of the cluster capabilities; the resources of the operands that require other capabilities are not rendered")
+	diffDir := flags.String("diff", "", "directory of resources exported from a cluster; print the differences from them, instead of the rendered resources")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *hcFile == "" {
+		fmt.Fprintln(stderr, "missing the HyperConverged manifest file (-f)")
+		flags.Usage()
+		return exitError
+	}
+
+	capabilities, err := parseCapabilities(*capabilitiesList)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if *envFile != "" {
+		if err := loadEnvFile(*envFile); err != nil {
+			fmt.Fprintf(stderr, "can't read the environment file %s: %v\n", *envFile, err)
+			return exitError
+		}
+	}
+
+	hc, err := readHyperConverged(*hcFile, *namespace)
+	if err != nil {
+		fmt.Fprintf(stderr, "can't read the HyperConverged manifest %s: %v\n", *hcFile, err)
+		return exitError
+	}
+
+	renderScheme, err := getScheme()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to add to scheme: %v\n", err)
+		return exitError
+	}
+
+	rendered, err := operands.RenderOperands(hc, renderScheme, capabilities)
+	if err != nil {
+		fmt.Fprintf(stderr, "can't render the operands: %v\n", err)
+		return exitError
+	}
+
+	if *diffDir == "" {
+		if err = writeManifests(stdout, rendered); err != nil {
+			fmt.Fprintf(stderr, "can't write the rendered resources: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	exported, err := readManifestsDir(*diffDir)
+	if err != nil {
+		fmt.Fprintf(stderr, "can't read the exported resources from %s: %v\n", *diffDir, err)
+		return exitError
+	}
+
+	diffs, err := operands.DiffRenderedOperands(rendered, exported)
+	if err != nil {
+		fmt.Fprintf(stderr, "can't compare the resources: %v\n", err)
+		return exitError
+	}
+
+	if len(diffs) == 0 {
+		return exitOK
+	}
+
+	out, err := yaml.Marshal(diffs)
+	if err != nil {
+		fmt.Fprintf(stderr, "can't write the differences: %v\n", err)
+		return exitError
+	}
+	_, _ = stdout.Write(out)
+
+	return exitDiff
+}
+
+func joinCapabilities(capabilities []hcoutil.Capability) string {
+	names := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		names = append(names, string(capability))
+	}
+	return strings.Join(names, ",")
+}
+
+// parseCapabilities parses a comma separated list of capabilities. An empty list means that the cluster has none of
+// the capabilities.
+func parseCapabilities(list string) ([]hcoutil.Capability, error) {
+	var capabilities []hcoutil.Capability
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for _, capability := range hcoutil.Capabilities {
+			if string(capability) == name {
+				capabilities = append(capabilities, capability)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown capability %s; the known capabilities are %s", name, joinCapabilities(hcoutil.Capabilities))
+		}
+	}
+
+	return capabilities, nil
+}
+
+// loadEnvFile sets the environment variables of a KEY=VALUE file. Empty lines and lines that start with '#' are
+// skipped.
+func loadEnvFile(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("wrong line %d; expected KEY=VALUE", lineNum)
+		}
+
+		if err = os.Setenv(strings.TrimSpace(kv[0]), kv[1]); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func readHyperConverged(fileName, namespace string) (*hcov1beta1.HyperConverged, error) {
+	var content []byte
+	var err error
+	if fileName == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hc := &hcov1beta1.HyperConverged{}
+	if err = yaml.UnmarshalStrict(content, hc); err != nil {
+		return nil, err
+	}
+
+	if hc.Kind != "" && hc.Kind != "HyperConverged" {
+		return nil, fmt.Errorf("expected a HyperConverged manifest, but found %s", hc.Kind)
+	}
+
+	if hc.Name == "" {
+		hc.Name = hcov1beta1.HyperConvergedName
+	}
+	if hc.Namespace == "" {
+		hc.Namespace = namespace
+	}
+
+	return hc, nil
+}
+
+func writeManifests(out io.Writer, manifests []*unstructured.Unstructured) error {
+	for _, manifest := range manifests {
+		content, err := yaml.Marshal(manifest.Object)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(out, "---\n%s", content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readManifestsDir reads the resources of all the YAML and JSON files in the directory and in its sub-directories.
+// Lists, such as the output of "kubectl get -o yaml", are read as their items.
+func readManifestsDir(dir string) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, errors.New("not a directory")
+	}
+
+	var manifests []*unstructured.Unstructured
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ext := filepath.Ext(info.Name())
+		if info.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		fileManifests, err := hcoutil.ReadManifests(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, manifest := range fileManifests {
+			if !manifest.IsList() {
+				manifests = append(manifests, manifest)
+				continue
+			}
+
+			list, err := manifest.ToList()
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			for i := range list.Items {
+				manifests = append(manifests, &list.Items[i])
+			}
+		}
+
+		return nil
+	})
+
+	return manifests, err
+}
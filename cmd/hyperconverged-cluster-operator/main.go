@@ -6,7 +6,6 @@ import (
 	"os"
 
 	"github.com/kubevirt/hyperconverged-cluster-operator/cmd/cmdcommon"
-	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/hyperconverged"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,43 +14,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
-	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
-	networkaddons "github.com/kubevirt/cluster-network-addons-operator/pkg/apis"
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
-	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
-	openshiftconfigv1 "github.com/openshift/api/config/v1"
-	consolev1 "github.com/openshift/api/console/v1"
-	csvv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
-	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
-	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
-	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // Change below variables to serve metrics on different host or port.
 var (
-	logger               = logf.Log.WithName("hyperconverged-operator-cmd")
-	cmdHelper            = cmdcommon.NewHelper(logger, "operator")
-	resourcesSchemeFuncs = []func(*apiruntime.Scheme) error{
-		apis.AddToScheme,
-		cdiv1beta1.AddToScheme,
-		networkaddons.AddToScheme,
-		sspv1beta1.AddToScheme,
-		csvv1alpha1.AddToScheme,
-		vmimportv1beta1.AddToScheme,
-		admissionregistrationv1.AddToScheme,
-		consolev1.AddToScheme,
-		openshiftconfigv1.AddToScheme,
-		monitoringv1.AddToScheme,
-		consolev1.AddToScheme,
-		apiextensionsv1.AddToScheme,
-	}
+	logger    = logf.Log.WithName("hyperconverged-operator-cmd")
+	cmdHelper = cmdcommon.NewHelper(logger, "operator")
 )
 
 func main() {
@@ -76,12 +50,12 @@ func main() {
 	logger.Info("Registering Components.")
 
 	// Setup Scheme for all resources
-	cmdHelper.AddToScheme(mgr, resourcesSchemeFuncs)
+	cmdHelper.AddToScheme(mgr, cmdcommon.ResourcesSchemeFuncs)
 
-	// Detect OpenShift version
+	// Detect the cluster capabilities
 	ctx := context.TODO()
 	ci := hcoutil.GetClusterInfo()
-	err = ci.CheckRunningInOpenshift(mgr.GetAPIReader(), ctx, logger, cmdHelper.IsRunInLocal())
+	err = ci.Init(ctx, mgr.GetAPIReader(), mgr.GetRESTMapper(), logger, cmdHelper.IsRunInLocal())
 	cmdHelper.ExitOnError(err, "Cannot detect cluster type")
 
 	eventEmitter := hcoutil.GetEventEmitter()
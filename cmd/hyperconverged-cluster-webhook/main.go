@@ -7,6 +7,7 @@ import (
 
 	"github.com/kubevirt/hyperconverged-cluster-operator/cmd/cmdcommon"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/webhookconfig"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/webhooks"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -76,10 +77,10 @@ func main() {
 	// Setup Scheme for all resources
 	cmdHelper.AddToScheme(mgr, resourcesSchemeFuncs)
 
-	// Detect OpenShift version
+	// Detect the cluster capabilities
 	ci := hcoutil.GetClusterInfo()
 	ctx := context.TODO()
-	err = ci.CheckRunningInOpenshift(mgr.GetAPIReader(), ctx, logger, cmdHelper.IsRunInLocal())
+	err = ci.Init(ctx, mgr.GetAPIReader(), mgr.GetRESTMapper(), logger, cmdHelper.IsRunInLocal())
 	cmdHelper.ExitOnError(err, "Cannot detect cluster type")
 
 	eventEmitter := hcoutil.GetEventEmitter()
@@ -101,6 +102,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// OLM may regenerate the webhook configurations at any time; restore the settings of the HCO webhooks
+	operatorNamespace, err := hcoutil.GetOperatorNamespaceFromEnv()
+	cmdHelper.ExitOnError(err, "can't get the operator namespace")
+
+	err = webhookconfig.RegisterReconciler(mgr, operatorNamespace)
+	cmdHelper.ExitOnError(err, "unable to create the webhook configuration controller")
+
 	logger.Info("Starting the Cmd.")
 	eventEmitter.EmitEvent(nil, corev1.EventTypeNormal, "Init", "Starting the HyperConverged webhook Pod")
 	// Start the Cmd
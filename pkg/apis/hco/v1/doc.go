@@ -0,0 +1,4 @@
+// package v1 contains API Schema definitions for the hco v1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=hco.kubevirt.io
+package v1
@@ -0,0 +1,293 @@
+package v1
+
+import (
+	"strconv"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// specVersionAnnotation preserves the v1beta1 spec.version field, that was dropped from the v1 API, so a v1beta1
+// object is not changed by a round trip through v1.
+const specVersionAnnotation = "hco.kubevirt.io/v1beta1-spec-version"
+
+var _ conversion.Convertible = &HyperConverged{}
+
+// ConvertTo converts this HyperConverged to the Hub version (v1beta1)
+func (src *HyperConverged) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.HyperConverged)
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	dst.Spec.LocalStorageClassName = src.Spec.LocalStorageClassName
+	dst.Spec.Infra.NodePlacement = src.Spec.Infra.NodePlacement.DeepCopy()
+	dst.Spec.Workloads.NodePlacement = src.Spec.Workloads.NodePlacement.DeepCopy()
+	dst.Spec.FeatureGates = convertFeatureGatesToV1beta1(src.Spec.FeatureGates)
+	dst.Spec.HostPathProvisioner = convertHostPathProvisionerToV1beta1(src.Spec.HostPathProvisioner)
+	dst.Spec.Components = convertComponentsToV1beta1(src.Spec.Components)
+
+	dst.Spec.Version = ""
+	if version := dst.Annotations[specVersionAnnotation]; version != "" {
+		dst.Spec.Version = version
+		delete(dst.Annotations, specVersionAnnotation)
+	}
+
+	if src.Spec.DeployOVS != nil {
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string)
+		}
+		dst.Annotations[v1beta1.DeployOVSAnnotation] = strconv.FormatBool(*src.Spec.DeployOVS)
+	}
+	removeEmptyAnnotations(&dst.ObjectMeta.Annotations)
+
+	src.Status.convertTo(&dst.Status)
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version
+func (dst *HyperConverged) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.HyperConverged)
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	dst.Spec.LocalStorageClassName = src.Spec.LocalStorageClassName
+	dst.Spec.Infra.NodePlacement = src.Spec.Infra.NodePlacement.DeepCopy()
+	dst.Spec.Workloads.NodePlacement = src.Spec.Workloads.NodePlacement.DeepCopy()
+	dst.Spec.FeatureGates = convertFeatureGatesFromV1beta1(src.Spec.FeatureGates)
+	dst.Spec.HostPathProvisioner = convertHostPathProvisionerFromV1beta1(src.Spec.HostPathProvisioner)
+	dst.Spec.Components = convertComponentsFromV1beta1(src.Spec.Components)
+
+	if src.Spec.Version != "" {
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string)
+		}
+		dst.Annotations[specVersionAnnotation] = src.Spec.Version
+	}
+
+	// only the values that are written back by ConvertTo are converted to the typed field; any other value is kept
+	// as is, so it won't be lost
+	dst.Spec.DeployOVS = nil
+	if val, ok := dst.Annotations[v1beta1.DeployOVSAnnotation]; ok && (val == "true" || val == "false") {
+		deployOVS := val == "true"
+		dst.Spec.DeployOVS = &deployOVS
+		delete(dst.Annotations, v1beta1.DeployOVSAnnotation)
+	}
+	removeEmptyAnnotations(&dst.ObjectMeta.Annotations)
+
+	dst.Status.convertFrom(&src.Status)
+
+	return nil
+}
+
+func convertFeatureGatesToV1beta1(fgs *HyperConvergedFeatureGates) *v1beta1.HyperConvergedFeatureGates {
+	if fgs == nil {
+		return nil
+	}
+
+	return &v1beta1.HyperConvergedFeatureGates{
+		SRIOVLiveMigration:     copyBool(fgs.SRIOVLiveMigration),
+		HotplugVolumes:         copyBool(fgs.HotplugVolumes),
+		GPU:                    copyBool(fgs.GPU),
+		HostDevices:            copyBool(fgs.HostDevices),
+		WithHostPassthroughCPU: copyBool(fgs.WithHostPassthroughCPU),
+		WithHostModelCPU:       copyBool(fgs.WithHostModelCPU),
+		HypervStrictCheck:      copyBool(fgs.HypervStrictCheck),
+	}
+}
+
+func convertFeatureGatesFromV1beta1(fgs *v1beta1.HyperConvergedFeatureGates) *HyperConvergedFeatureGates {
+	if fgs == nil {
+		return nil
+	}
+
+	return &HyperConvergedFeatureGates{
+		SRIOVLiveMigration:     copyBool(fgs.SRIOVLiveMigration),
+		HotplugVolumes:         copyBool(fgs.HotplugVolumes),
+		GPU:                    copyBool(fgs.GPU),
+		HostDevices:            copyBool(fgs.HostDevices),
+		WithHostPassthroughCPU: copyBool(fgs.WithHostPassthroughCPU),
+		WithHostModelCPU:       copyBool(fgs.WithHostModelCPU),
+		HypervStrictCheck:      copyBool(fgs.HypervStrictCheck),
+	}
+}
+
+func convertComponentsToV1beta1(c *HyperConvergedComponents) *v1beta1.HyperConvergedComponents {
+	if c == nil {
+		return nil
+	}
+
+	return &v1beta1.HyperConvergedComponents{
+		VMImport:      copyBool(c.VMImport),
+		SSP:           copyBool(c.SSP),
+		NetworkAddons: copyBool(c.NetworkAddons),
+		QuickStarts:   copyBool(c.QuickStarts),
+		CLIDownloads:  copyBool(c.CLIDownloads),
+		Monitoring:    copyBool(c.Monitoring),
+	}
+}
+
+func convertComponentsFromV1beta1(c *v1beta1.HyperConvergedComponents) *HyperConvergedComponents {
+	if c == nil {
+		return nil
+	}
+
+	return &HyperConvergedComponents{
+		VMImport:      copyBool(c.VMImport),
+		SSP:           copyBool(c.SSP),
+		NetworkAddons: copyBool(c.NetworkAddons),
+		QuickStarts:   copyBool(c.QuickStarts),
+		CLIDownloads:  copyBool(c.CLIDownloads),
+		Monitoring:    copyBool(c.Monitoring),
+	}
+}
+
+func convertHostPathProvisionerToV1beta1(hpp *HostPathProvisionerConfig) *v1beta1.HostPathProvisionerConfig {
+	if hpp == nil {
+		return nil
+	}
+
+	return &v1beta1.HostPathProvisionerConfig{
+		Path:        hpp.Path,
+		StoragePool: hpp.StoragePool,
+		Workload:    hpp.Workload.DeepCopy(),
+	}
+}
+
+func convertHostPathProvisionerFromV1beta1(hpp *v1beta1.HostPathProvisionerConfig) *HostPathProvisionerConfig {
+	if hpp == nil {
+		return nil
+	}
+
+	return &HostPathProvisionerConfig{
+		Path:        hpp.Path,
+		StoragePool: hpp.StoragePool,
+		Workload:    hpp.Workload.DeepCopy(),
+	}
+}
+
+func copyBool(fg *bool) *bool {
+	if fg == nil {
+		return nil
+	}
+	val := *fg
+	return &val
+}
+
+func (src *HyperConvergedStatus) convertTo(dst *v1beta1.HyperConvergedStatus) {
+	dst.Conditions = nil
+	if src.Conditions != nil {
+		dst.Conditions = make([]conditionsv1.Condition, len(src.Conditions))
+		for i, cond := range src.Conditions {
+			cond.DeepCopyInto(&dst.Conditions[i])
+		}
+	}
+
+	dst.RelatedObjects = nil
+	if src.RelatedObjects != nil {
+		dst.RelatedObjects = make([]corev1.ObjectReference, len(src.RelatedObjects))
+		copy(dst.RelatedObjects, src.RelatedObjects)
+	}
+
+	dst.Versions = nil
+	if src.Versions != nil {
+		dst.Versions = make(v1beta1.Versions, 0, len(src.Versions))
+		for _, v := range src.Versions {
+			dst.Versions = append(dst.Versions, v1beta1.Version{Name: v.Name, Version: v.Version})
+		}
+	}
+
+	dst.NodesUnderMaintenance = nil
+	if src.NodesUnderMaintenance != nil {
+		dst.NodesUnderMaintenance = make([]v1beta1.NodeMaintenanceStatus, 0, len(src.NodesUnderMaintenance))
+		for _, nm := range src.NodesUnderMaintenance {
+			dst.NodesUnderMaintenance = append(dst.NodesUnderMaintenance, v1beta1.NodeMaintenanceStatus(nm))
+		}
+	}
+
+	dst.CompletedMigrations = nil
+	if src.CompletedMigrations != nil {
+		dst.CompletedMigrations = make([]string, len(src.CompletedMigrations))
+		copy(dst.CompletedMigrations, src.CompletedMigrations)
+	}
+
+	dst.UpgradePreflightChecks = nil
+	if src.UpgradePreflightChecks != nil {
+		dst.UpgradePreflightChecks = make([]v1beta1.UpgradePreflightCheckStatus, 0, len(src.UpgradePreflightChecks))
+		for _, check := range src.UpgradePreflightChecks {
+			dst.UpgradePreflightChecks = append(dst.UpgradePreflightChecks, v1beta1.UpgradePreflightCheckStatus(check))
+		}
+	}
+
+	dst.AppliedPatches = nil
+	if src.AppliedPatches != nil {
+		dst.AppliedPatches = make([]v1beta1.AppliedPatchStatus, 0, len(src.AppliedPatches))
+		for _, patch := range src.AppliedPatches {
+			dst.AppliedPatches = append(dst.AppliedPatches, v1beta1.AppliedPatchStatus(patch))
+		}
+	}
+}
+
+func (dst *HyperConvergedStatus) convertFrom(src *v1beta1.HyperConvergedStatus) {
+	dst.Conditions = nil
+	if src.Conditions != nil {
+		dst.Conditions = make([]conditionsv1.Condition, len(src.Conditions))
+		for i, cond := range src.Conditions {
+			cond.DeepCopyInto(&dst.Conditions[i])
+		}
+	}
+
+	dst.RelatedObjects = nil
+	if src.RelatedObjects != nil {
+		dst.RelatedObjects = make([]corev1.ObjectReference, len(src.RelatedObjects))
+		copy(dst.RelatedObjects, src.RelatedObjects)
+	}
+
+	dst.Versions = nil
+	if src.Versions != nil {
+		dst.Versions = make(Versions, 0, len(src.Versions))
+		for _, v := range src.Versions {
+			dst.Versions = append(dst.Versions, Version{Name: v.Name, Version: v.Version})
+		}
+	}
+
+	dst.NodesUnderMaintenance = nil
+	if src.NodesUnderMaintenance != nil {
+		dst.NodesUnderMaintenance = make([]NodeMaintenanceStatus, 0, len(src.NodesUnderMaintenance))
+		for _, nm := range src.NodesUnderMaintenance {
+			dst.NodesUnderMaintenance = append(dst.NodesUnderMaintenance, NodeMaintenanceStatus(nm))
+		}
+	}
+
+	dst.CompletedMigrations = nil
+	if src.CompletedMigrations != nil {
+		dst.CompletedMigrations = make([]string, len(src.CompletedMigrations))
+		copy(dst.CompletedMigrations, src.CompletedMigrations)
+	}
+
+	dst.UpgradePreflightChecks = nil
+	if src.UpgradePreflightChecks != nil {
+		dst.UpgradePreflightChecks = make([]UpgradePreflightCheckStatus, 0, len(src.UpgradePreflightChecks))
+		for _, check := range src.UpgradePreflightChecks {
+			dst.UpgradePreflightChecks = append(dst.UpgradePreflightChecks, UpgradePreflightCheckStatus(check))
+		}
+	}
+
+	dst.AppliedPatches = nil
+	if src.AppliedPatches != nil {
+		dst.AppliedPatches = make([]AppliedPatchStatus, 0, len(src.AppliedPatches))
+		for _, patch := range src.AppliedPatches {
+			dst.AppliedPatches = append(dst.AppliedPatches, AppliedPatchStatus(patch))
+		}
+	}
+}
+
+// removeEmptyAnnotations drops an annotation map that was emptied by the conversion, as an empty map and a missing map
+// are serialized the same way
+func removeEmptyAnnotations(annotations *map[string]string) {
+	if *annotations != nil && len(*annotations) == 0 {
+		*annotations = nil
+	}
+}
@@ -0,0 +1,266 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apixv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+const fuzzIterations = 1000
+
+func TestHyperConvergedConversion(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "v1.HyperConverged Conversion Suite")
+}
+
+var _ = Describe("HyperConverged conversion", func() {
+	enabled := true
+
+	Context("v1beta1 to v1", func() {
+		It("should move spec.version to an annotation", func() {
+			hcBeta := newV1beta1Hco()
+			hcBeta.Spec.Version = "1.4.0"
+
+			hc := &HyperConverged{}
+			Expect(hc.ConvertFrom(hcBeta)).To(Succeed())
+			Expect(hc.Annotations).To(HaveKeyWithValue(specVersionAnnotation, "1.4.0"))
+		})
+
+		It("should convert the deployOVS annotation to the deployOVS field", func() {
+			for _, val := range []bool{true, false} {
+				hcBeta := newV1beta1Hco()
+				hcBeta.Annotations = map[string]string{v1beta1.DeployOVSAnnotation: strconv.FormatBool(val), "other": "annotation"}
+
+				hc := &HyperConverged{}
+				Expect(hc.ConvertFrom(hcBeta)).To(Succeed())
+				Expect(hc.Spec.DeployOVS).ToNot(BeNil())
+				Expect(*hc.Spec.DeployOVS).To(Equal(val))
+				Expect(hc.Annotations).To(Equal(map[string]string{"other": "annotation"}))
+			}
+		})
+
+		It("should keep a deployOVS annotation that is not a valid boolean", func() {
+			hcBeta := newV1beta1Hco()
+			hcBeta.Annotations = map[string]string{v1beta1.DeployOVSAnnotation: "yes"}
+
+			hc := &HyperConverged{}
+			Expect(hc.ConvertFrom(hcBeta)).To(Succeed())
+			Expect(hc.Spec.DeployOVS).To(BeNil())
+			Expect(hc.Annotations).To(HaveKeyWithValue(v1beta1.DeployOVSAnnotation, "yes"))
+		})
+
+		It("should copy the rest of the spec and the status", func() {
+			hcBeta := newV1beta1Hco()
+			hcBeta.Spec.LocalStorageClassName = "local"
+			hcBeta.Spec.FeatureGates = &v1beta1.HyperConvergedFeatureGates{GPU: &enabled}
+			hcBeta.Spec.HostPathProvisioner = &v1beta1.HostPathProvisionerConfig{Path: "/var/hpvolumes", StoragePool: "local"}
+			hcBeta.Status.UpdateVersion("operator", "1.4.0")
+			hcBeta.Status.CompletedMigrations = []string{"a-migration"}
+			hcBeta.Status.UpgradePreflightChecks = []v1beta1.UpgradePreflightCheckStatus{{Name: "a-check", Blocking: true, Message: "failed"}}
+			hcBeta.Status.AppliedPatches = []v1beta1.AppliedPatchStatus{{Annotation: "kubevirt.kubevirt.io/mergepatch", Type: "merge", Kind: "KubeVirt", Name: "kubevirt-kubevirt-hyperconverged"}}
+
+			hc := &HyperConverged{}
+			Expect(hc.ConvertFrom(hcBeta)).To(Succeed())
+			Expect(hc.Name).To(Equal(HyperConvergedName))
+			Expect(hc.Spec.LocalStorageClassName).To(Equal("local"))
+			Expect(hc.Spec.FeatureGates.GPU).To(Equal(FeatureGate(&enabled)))
+			Expect(hc.Spec.HostPathProvisioner).To(Equal(&HostPathProvisionerConfig{Path: "/var/hpvolumes", StoragePool: "local"}))
+			Expect(hc.Status.Versions).To(Equal(Versions{{Name: "operator", Version: "1.4.0"}}))
+			Expect(hc.Status.CompletedMigrations).To(Equal([]string{"a-migration"}))
+			Expect(hc.Status.UpgradePreflightChecks).To(Equal([]UpgradePreflightCheckStatus{{Name: "a-check", Blocking: true, Message: "failed"}}))
+			Expect(hc.Status.AppliedPatches).To(Equal([]AppliedPatchStatus{{Annotation: "kubevirt.kubevirt.io/mergepatch", Type: "merge", Kind: "KubeVirt", Name: "kubevirt-kubevirt-hyperconverged"}}))
+		})
+	})
+
+	Context("v1 to v1beta1", func() {
+		It("should restore spec.version from the annotation", func() {
+			hc := newV1Hco()
+			hc.Annotations = map[string]string{specVersionAnnotation: "1.4.0"}
+
+			hcBeta := &v1beta1.HyperConverged{}
+			Expect(hc.ConvertTo(hcBeta)).To(Succeed())
+			Expect(hcBeta.Spec.Version).To(Equal("1.4.0"))
+			Expect(hcBeta.Annotations).To(BeNil())
+		})
+
+		It("should convert the deployOVS field to the deployOVS annotation", func() {
+			hc := newV1Hco()
+			hc.Spec.DeployOVS = &enabled
+
+			hcBeta := &v1beta1.HyperConverged{}
+			Expect(hc.ConvertTo(hcBeta)).To(Succeed())
+			Expect(hcBeta.Annotations).To(HaveKeyWithValue(v1beta1.DeployOVSAnnotation, "true"))
+		})
+
+		It("should not add the deployOVS annotation if the deployOVS field is not set", func() {
+			hc := newV1Hco()
+
+			hcBeta := &v1beta1.HyperConverged{}
+			Expect(hc.ConvertTo(hcBeta)).To(Succeed())
+			Expect(hcBeta.Annotations).ToNot(HaveKey(v1beta1.DeployOVSAnnotation))
+		})
+	})
+
+	Context("round trip", func() {
+		var f *fuzz.Fuzzer
+
+		BeforeEach(func() {
+			f = fuzz.NewWithSeed(GinkgoRandomSeed()).NilChance(0.2).NumElements(0, 3)
+		})
+
+		It("should not lose data when converting v1beta1 to v1 and back", func() {
+			for i := 0; i < fuzzIterations; i++ {
+				orig := &v1beta1.HyperConverged{}
+				f.Fuzz(orig)
+				normalizeV1beta1(orig)
+				// make sure the annotations that are converted to typed fields are covered
+				if i%2 == 0 {
+					if orig.Annotations == nil {
+						orig.Annotations = make(map[string]string)
+					}
+					orig.Annotations[v1beta1.DeployOVSAnnotation] = strconv.FormatBool(i%4 == 0)
+				}
+
+				hc := &HyperConverged{}
+				Expect(hc.ConvertFrom(orig.DeepCopy())).To(Succeed())
+				converted := &v1beta1.HyperConverged{}
+				Expect(hc.ConvertTo(converted)).To(Succeed())
+
+				Expect(converted).To(Equal(orig))
+			}
+		})
+
+		It("should not lose data when converting v1 to v1beta1 and back", func() {
+			for i := 0; i < fuzzIterations; i++ {
+				orig := &HyperConverged{}
+				f.Fuzz(orig)
+				normalizeV1(orig)
+
+				hcBeta := &v1beta1.HyperConverged{}
+				Expect(orig.DeepCopy().ConvertTo(hcBeta)).To(Succeed())
+				converted := &HyperConverged{}
+				Expect(converted.ConvertFrom(hcBeta)).To(Succeed())
+
+				Expect(converted).To(Equal(orig))
+			}
+		})
+	})
+
+	Context("conversion webhook", func() {
+		var wh *conversion.Webhook
+
+		BeforeEach(func() {
+			s := runtime.NewScheme()
+			Expect(v1beta1.AddToScheme(s)).To(Succeed())
+			Expect(AddToScheme(s)).To(Succeed())
+
+			wh = &conversion.Webhook{}
+			Expect(wh.InjectScheme(s)).To(Succeed())
+		})
+
+		It("should convert v1beta1 to v1", func() {
+			hcBeta := newV1beta1Hco()
+			hcBeta.Spec.Version = "1.4.0"
+			hcBeta.Annotations = map[string]string{v1beta1.DeployOVSAnnotation: "true"}
+
+			hc := &HyperConverged{}
+			convertWithWebhook(wh, hcBeta, SchemeGroupVersion.String(), hc)
+
+			Expect(hc.APIVersion).To(Equal(SchemeGroupVersion.String()))
+			Expect(hc.Spec.DeployOVS).To(Equal(&enabled))
+			Expect(hc.Annotations).To(Equal(map[string]string{specVersionAnnotation: "1.4.0"}))
+		})
+
+		It("should convert v1 to v1beta1", func() {
+			hc := newV1Hco()
+			hc.Spec.DeployOVS = &enabled
+
+			hcBeta := &v1beta1.HyperConverged{}
+			convertWithWebhook(wh, hc, v1beta1.SchemeGroupVersion.String(), hcBeta)
+
+			Expect(hcBeta.APIVersion).To(Equal(v1beta1.SchemeGroupVersion.String()))
+			Expect(hcBeta.Annotations).To(Equal(map[string]string{v1beta1.DeployOVSAnnotation: "true"}))
+		})
+	})
+})
+
+func newV1beta1Hco() *v1beta1.HyperConverged {
+	return &v1beta1.HyperConverged{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "HyperConverged",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HyperConvergedName,
+			Namespace: "kubevirt-hyperconverged",
+		},
+	}
+}
+
+func newV1Hco() *HyperConverged {
+	return &HyperConverged{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "HyperConverged",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HyperConvergedName,
+			Namespace: "kubevirt-hyperconverged",
+		},
+	}
+}
+
+// normalizeV1beta1 removes the values that can't be part of a valid v1beta1 object: the type meta is set by the
+// conversion webhook, an empty annotation map is not serialized, and the spec version annotation is reserved for
+// the conversion.
+func normalizeV1beta1(hc *v1beta1.HyperConverged) {
+	hc.TypeMeta = metav1.TypeMeta{}
+	delete(hc.Annotations, specVersionAnnotation)
+	removeEmptyAnnotations(&hc.Annotations)
+}
+
+// normalizeV1 removes the values that can't be part of a valid v1 object: the type meta is set by the conversion
+// webhook, an empty annotation map is not serialized, and the deployOVS annotation is replaced by the deployOVS
+// field.
+func normalizeV1(hc *HyperConverged) {
+	hc.TypeMeta = metav1.TypeMeta{}
+	delete(hc.Annotations, v1beta1.DeployOVSAnnotation)
+	removeEmptyAnnotations(&hc.Annotations)
+}
+
+func convertWithWebhook(wh *conversion.Webhook, src runtime.Object, desiredAPIVersion string, dst runtime.Object) {
+	raw, err := json.Marshal(src)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	review := &apixv1beta1.ConversionReview{
+		Request: &apixv1beta1.ConversionRequest{
+			UID:               "uid",
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           []runtime.RawExtension{{Raw: raw}},
+		},
+	}
+	body, err := json.Marshal(review)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	rec := httptest.NewRecorder()
+	wh.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body)))
+	ExpectWithOffset(1, rec.Code).To(Equal(http.StatusOK))
+
+	resp := &apixv1beta1.ConversionReview{}
+	ExpectWithOffset(1, json.Unmarshal(rec.Body.Bytes(), resp)).To(Succeed())
+	ExpectWithOffset(1, resp.Response.Result.Status).To(Equal(metav1.StatusSuccess), resp.Response.Result.Message)
+	ExpectWithOffset(1, resp.Response.ConvertedObjects).To(HaveLen(1))
+	ExpectWithOffset(1, json.Unmarshal(resp.Response.ConvertedObjects[0].Raw, dst)).To(Succeed())
+}
@@ -0,0 +1,275 @@
+package v1
+
+import (
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+// Any change in this file must be reflected in the conversion from and to v1beta1, in hyperconverged_conversion.go
+
+// HyperConvergedName is the name of the HyperConverged resource that will be reconciled
+const HyperConvergedName = "kubevirt-hyperconverged"
+
+// HyperConvergedSpec defines the desired state of HyperConverged
+type HyperConvergedSpec struct {
+	// LocalStorageClassName the name of the local storage class.
+	LocalStorageClassName string `json:"localStorageClassName,omitempty"`
+
+	// infra HyperConvergedConfig influences the pod configuration (currently only placement)
+	// for all the infra components needed on the virtualization enabled cluster
+	// but not necessarely directly on each node running VMs/VMIs.
+	// +optional
+	Infra HyperConvergedConfig `json:"infra,omitempty"`
+
+	// workloads HyperConvergedConfig influences the pod configuration (currently only placement) of components
+	// which need to be running on a node where virtualization workloads should be able to run.
+	// Changes to Workloads HyperConvergedConfig can be applied only without existing workload.
+	// +optional
+	Workloads HyperConvergedConfig `json:"workloads,omitempty"`
+
+	// featureGates is a map of feature gate flags. Setting a flag to `true` will enable
+	// the feature. Setting `false` or removing the feature gate, disables the feature.
+	// +optional
+	FeatureGates *HyperConvergedFeatureGates `json:"featureGates,omitempty"`
+
+	// hostPathProvisioner deploys the HostPath Provisioner, that provisions persistent volumes from a directory on the
+	// nodes' file system. The provisioner is not deployed if this field is not set.
+	// +optional
+	HostPathProvisioner *HostPathProvisionerConfig `json:"hostPathProvisioner,omitempty"`
+
+	// components enables or disables the optional components. All the components are enabled by default.
+	// Disabling a component removes its resources from the cluster.
+	// +optional
+	Components *HyperConvergedComponents `json:"components,omitempty"`
+
+	// deployOVS controls the deployment of the Open vSwitch CNI plugin. It replaces the deployOVS annotation of the
+	// v1beta1 API.
+	// +optional
+	DeployOVS *bool `json:"deployOVS,omitempty"`
+}
+
+// HyperConvergedConfig defines a set of configurations to pass to components
+type HyperConvergedConfig struct {
+	// NodePlacement describes node scheduling configuration.
+	// +optional
+	NodePlacement *sdkapi.NodePlacement `json:"nodePlacement,omitempty"`
+}
+
+// HyperConvergedComponents enables or disables the optional components. A component that is not set is enabled.
+type HyperConvergedComponents struct {
+	// Deploy the VM import operator configuration, used to import virtual machines from other virtualization
+	// platforms
+	// +optional
+	VMImport *bool `json:"vmImport,omitempty"`
+
+	// Deploy the Scheduling, Scale and Performance operator configuration, including the common templates, the
+	// template validator and the node labeller
+	// +optional
+	SSP *bool `json:"ssp,omitempty"`
+
+	// Deploy the cluster network addons
+	// +optional
+	NetworkAddons *bool `json:"networkAddons,omitempty"`
+
+	// Deploy the console quick starts
+	// +optional
+	QuickStarts *bool `json:"quickStarts,omitempty"`
+
+	// Deploy the console download links of the virtctl command line interface
+	// +optional
+	CLIDownloads *bool `json:"cliDownloads,omitempty"`
+
+	// Deploy the metrics service, the service monitor and the alerting rules
+	// +optional
+	Monitoring *bool `json:"monitoring,omitempty"`
+}
+
+// HostPathProvisionerConfig defines the configuration of the HostPath Provisioner
+type HostPathProvisionerConfig struct {
+	// path is the directory on the nodes, where the persistent volumes are created
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// storagePool is the name of the storage pool backed by path. It's used as the name of the StorageClass that
+	// consumes the provisioner. Defaults to "hostpath-provisioner".
+	// +optional
+	StoragePool string `json:"storagePool,omitempty"`
+
+	// workload describes the node scheduling configuration of the provisioner pods. Defaults to the workloads node
+	// placement.
+	// +optional
+	Workload *sdkapi.NodePlacement `json:"workload,omitempty"`
+}
+
+type FeatureGate *bool
+
+// HyperConvergedFeatureGates is a set of optional feature gates to enable or disable new features that are not enabled
+// by default yet.
+// +optional
+type HyperConvergedFeatureGates struct {
+	// Allow migrating a virtual machine with SRIOV interfaces.
+	// When enabled virt-launcher pods of virtual machines with SRIOV
+	// interfaces run with CAP_SYS_RESOURCE capability.
+	// This may degrade virt-launcher security.
+	// +optional
+	SRIOVLiveMigration FeatureGate `json:"sriovLiveMigration,omitempty"`
+
+	// Allow attaching a data volume to a running VMI
+	// +optional
+	HotplugVolumes FeatureGate `json:"hotplugVolumes,omitempty"`
+
+	// Allow assigning GPU and vGPU devices to virtual machines
+	// +optional
+	GPU FeatureGate `json:"gpu,omitempty"`
+
+	// Allow assigning host devices to virtual machines
+	// +optional
+	HostDevices FeatureGate `json:"hostDevices,omitempty"`
+
+	// Allow migrating a virtual machine with CPU host-passthrough mode. This should be
+	// enabled only when the Cluster is homogeneous from CPU HW perspective doc here
+	// +optional
+	WithHostPassthroughCPU FeatureGate `json:"withHostPassthroughCPU,omitempty"`
+
+	// Support migration for VMs with host-model CPU mode. Defaults to true
+	// +optional
+	WithHostModelCPU FeatureGate `json:"withHostModelCPU,omitempty"`
+
+	// Enable HyperV strict host checking for HyperV enlightenments
+	// Defaults to true, even when HyperConvergedFeatureGates is empty
+	// +optional
+	HypervStrictCheck FeatureGate `json:"hypervStrictCheck,omitempty"`
+}
+
+// HyperConvergedStatus defines the observed state of HyperConverged
+type HyperConvergedStatus struct {
+	// Conditions describes the state of the HyperConverged resource.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +optional
+	Conditions []conditionsv1.Condition `json:"conditions,omitempty"  patchStrategy:"merge" patchMergeKey:"type"`
+
+	// RelatedObjects is a list of objects created and maintained by this
+	// operator. Object references will be added to this list after they have
+	// been created AND found in the cluster.
+	// +optional
+	RelatedObjects []corev1.ObjectReference `json:"relatedObjects,omitempty"`
+
+	// Versions is a list of HCO component versions, as name/version pairs. The version with a name of "operator"
+	// is the HCO version itself, as described here:
+	// https://github.com/openshift/cluster-version-operator/blob/master/docs/dev/clusteroperator.md#version
+	// +optional
+	Versions Versions `json:"versions,omitempty"`
+
+	// NodesUnderMaintenance is a list of the nodes that are under maintenance by the node maintenance operator,
+	// sorted by node name.
+	// +optional
+	NodesUnderMaintenance []NodeMaintenanceStatus `json:"nodesUnderMaintenance,omitempty"`
+
+	// CompletedMigrations is a list of the names of the migrations that were completed during the current or the last
+	// upgrade, so an interrupted upgrade does not run them again.
+	// +listType=set
+	// +optional
+	CompletedMigrations []string `json:"completedMigrations,omitempty"`
+
+	// UpgradePreflightChecks is a list of the results of the pre-flight checks that ran before the last upgrade, or
+	// before the pending upgrade.
+	// +optional
+	UpgradePreflightChecks []UpgradePreflightCheckStatus `json:"upgradePreflightChecks,omitempty"`
+
+	// AppliedPatches is a list of the patch annotations of the HyperConverged CR that were applied to the operand
+	// resources in the last reconciliation.
+	// +optional
+	AppliedPatches []AppliedPatchStatus `json:"appliedPatches,omitempty"`
+}
+
+// AppliedPatchStatus describes a patch annotation that was applied to an operand resource
+type AppliedPatchStatus struct {
+	// Annotation is the name of the patch annotation of the HyperConverged CR
+	Annotation string `json:"annotation"`
+
+	// Type is the type of the patch; one of json, merge or strategic
+	Type string `json:"type"`
+
+	// Kind is the kind of the patched resource
+	Kind string `json:"kind"`
+
+	// Name is the name of the patched resource
+	Name string `json:"name"`
+}
+
+// UpgradePreflightCheckStatus is the result of an upgrade pre-flight check
+type UpgradePreflightCheckStatus struct {
+	// Name is the name of the check
+	Name string `json:"name"`
+
+	// Blocking is true if a failure of the check prevents the upgrade
+	Blocking bool `json:"blocking"`
+
+	// Passed is true if the check did not find any issue
+	Passed bool `json:"passed"`
+
+	// Message describes the issues that the check found
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NodeMaintenanceStatus describes the maintenance of a node
+type NodeMaintenanceStatus struct {
+	// NodeName is the name of the node under maintenance
+	NodeName string `json:"nodeName"`
+
+	// Reason is the reason for the maintenance
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Phase is the progress of the maintenance; "Running" while the node is being drained, "Succeeded" once the node
+	// is drained, or "Failed"
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// PendingPods is the number of pods that were not evicted from the node yet
+	// +optional
+	PendingPods int `json:"pendingPods,omitempty"`
+
+	// PendingVMs is the number of virtual machines that were not migrated or shut down yet
+	// +optional
+	PendingVMs int `json:"pendingVMs,omitempty"`
+}
+
+type Version struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type Versions []Version
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HyperConverged is the Schema for the hyperconvergeds API
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:scope=Namespaced,categories={all},shortName={hco,hcos}
+// +kubebuilder:subresource:status
+type HyperConverged struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HyperConvergedSpec   `json:"spec,omitempty"`
+	Status HyperConvergedStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HyperConvergedList contains a list of HyperConverged
+type HyperConvergedList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HyperConverged `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HyperConverged{}, &HyperConvergedList{})
+}
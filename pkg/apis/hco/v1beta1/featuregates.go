@@ -0,0 +1,165 @@
+package v1beta1
+
+// FeatureGateMaturity describes how mature a feature behind a feature gate is
+type FeatureGateMaturity string
+
+const (
+	FeatureGateAlpha FeatureGateMaturity = "Alpha"
+	FeatureGateBeta  FeatureGateMaturity = "Beta"
+	FeatureGateGA    FeatureGateMaturity = "GA"
+)
+
+// FeatureGateOperand is the operand whose feature gate list is fed by an HCO feature gate
+type FeatureGateOperand string
+
+const (
+	FeatureGateOperandKubeVirt FeatureGateOperand = "KubeVirt"
+)
+
+// HCO feature gate names, as they appear in the HyperConverged CR
+const (
+	SRIOVLiveMigrationGateName     = "sriovLiveMigration"
+	HotplugVolumesGateName         = "hotplugVolumes"
+	GPUGateName                    = "gpu"
+	HostDevicesGateName            = "hostDevices"
+	WithHostPassthroughCPUGateName = "withHostPassthroughCPU"
+	WithHostModelCPUGateName       = "withHostModelCPU"
+	HypervStrictCheckGateName      = "hypervStrictCheck"
+)
+
+// FeatureGateDescriptor is the single source of truth for an HCO feature gate
+// +k8s:deepcopy-gen=false
+type FeatureGateDescriptor struct {
+	// Name is the name of the feature gate in the HyperConverged CR
+	Name string
+	// Default is the value of the feature gate when it is not set. It is set by HyperConverged.SetDefaults.
+	Default bool
+	// Maturity of the feature
+	Maturity FeatureGateMaturity
+	// Operand is the operand whose feature gate list is fed by this feature gate
+	Operand FeatureGateOperand
+	// OperandGate is the name of the feature gate in the operand feature gate list
+	OperandGate string
+
+	// field returns the address of the feature gate field, to read or to set it
+	field func(*HyperConvergedFeatureGates) *FeatureGate
+}
+
+// featureGateRegistry lists all the HCO feature gates. The operand feature gate lists are generated in this order.
+var featureGateRegistry = []FeatureGateDescriptor{
+	{
+		Name:        HotplugVolumesGateName,
+		Default:     false,
+		Maturity:    FeatureGateAlpha,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "HotplugVolumes",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.HotplugVolumes },
+	},
+	{
+		Name:        WithHostPassthroughCPUGateName,
+		Default:     false,
+		Maturity:    FeatureGateAlpha,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "WithHostPassthroughCPU",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.WithHostPassthroughCPU },
+	},
+	{
+		Name:        WithHostModelCPUGateName,
+		Default:     true,
+		Maturity:    FeatureGateBeta,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "WithHostModelCPU",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.WithHostModelCPU },
+	},
+	{
+		Name:        SRIOVLiveMigrationGateName,
+		Default:     false,
+		Maturity:    FeatureGateAlpha,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "SRIOVLiveMigration",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.SRIOVLiveMigration },
+	},
+	{
+		Name:        HypervStrictCheckGateName,
+		Default:     true,
+		Maturity:    FeatureGateBeta,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "HypervStrictCheck",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.HypervStrictCheck },
+	},
+	{
+		Name:        GPUGateName,
+		Default:     false,
+		Maturity:    FeatureGateAlpha,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "GPU",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.GPU },
+	},
+	{
+		Name:        HostDevicesGateName,
+		Default:     false,
+		Maturity:    FeatureGateAlpha,
+		Operand:     FeatureGateOperandKubeVirt,
+		OperandGate: "HostDevices",
+		field:       func(fgs *HyperConvergedFeatureGates) *FeatureGate { return &fgs.HostDevices },
+	},
+}
+
+// GetFeatureGateDescriptors returns the descriptors of all the HCO feature gates
+func GetFeatureGateDescriptors() []FeatureGateDescriptor {
+	descriptors := make([]FeatureGateDescriptor, len(featureGateRegistry))
+	copy(descriptors, featureGateRegistry)
+	return descriptors
+}
+
+// GetFeatureGateDescriptor returns the descriptor of the named HCO feature gate
+func GetFeatureGateDescriptor(name string) (FeatureGateDescriptor, bool) {
+	for _, fg := range featureGateRegistry {
+		if fg.Name == name {
+			return fg, true
+		}
+	}
+	return FeatureGateDescriptor{}, false
+}
+
+// IsEnabled returns true if the named feature gate is explicitly set to true. Unset feature gates are defaulted by
+// the mutating webhook, by HyperConverged.SetDefaults.
+func (fgs *HyperConvergedFeatureGates) IsEnabled(name string) bool {
+	fg, found := GetFeatureGateDescriptor(name)
+	return found && fgs.isEnabled(fg)
+}
+
+func (fgs *HyperConvergedFeatureGates) isEnabled(fg FeatureGateDescriptor) bool {
+	if fgs == nil {
+		return false
+	}
+	val := *fg.field(fgs)
+	return (val != nil) && (*val)
+}
+
+// GetEnabledOperandGates returns the operand feature gate names of the enabled HCO feature gates that feed the
+// required operand
+func (fgs *HyperConvergedFeatureGates) GetEnabledOperandGates(operand FeatureGateOperand) []string {
+	var gates []string
+	for _, fg := range featureGateRegistry {
+		if fg.Operand == operand && fgs.isEnabled(fg) {
+			gates = append(gates, fg.OperandGate)
+		}
+	}
+	return gates
+}
+
+// SetDefaults sets the feature gates that are not set to their default values. Returns true if any feature gate was
+// set.
+func (fgs *HyperConvergedFeatureGates) SetDefaults() bool {
+	changed := false
+	for _, fg := range featureGateRegistry {
+		field := fg.field(fgs)
+		if *field == nil {
+			value := fg.Default
+			*field = &value
+			changed = true
+		}
+	}
+	return changed
+}
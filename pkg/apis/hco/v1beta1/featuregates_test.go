@@ -0,0 +1,78 @@
+package v1beta1
+
+import (
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Feature Gate Registry", func() {
+	enabled := true
+	disabled := false
+
+	Context("registry consistency", func() {
+		It("should register each HyperConvergedFeatureGates field exactly once", func() {
+			fgType := reflect.TypeOf(HyperConvergedFeatureGates{})
+			Expect(featureGateRegistry).To(HaveLen(fgType.NumField()))
+
+			names := make(map[string]bool)
+			for _, fg := range featureGateRegistry {
+				Expect(names).ToNot(HaveKey(fg.Name))
+				names[fg.Name] = true
+			}
+
+			for i := 0; i < fgType.NumField(); i++ {
+				jsonName := strings.Split(fgType.Field(i).Tag.Get("json"), ",")[0]
+				Expect(names).To(HaveKey(jsonName))
+			}
+		})
+
+		It("should read the right field for each feature gate", func() {
+			for _, fg := range featureGateRegistry {
+				fgs := &HyperConvergedFeatureGates{}
+				fgType := reflect.TypeOf(fgs).Elem()
+				for i := 0; i < fgType.NumField(); i++ {
+					if strings.Split(fgType.Field(i).Tag.Get("json"), ",")[0] == fg.Name {
+						reflect.ValueOf(fgs).Elem().Field(i).Set(reflect.ValueOf(FeatureGate(&enabled)))
+					}
+				}
+				Expect(fgs.IsEnabled(fg.Name)).To(BeTrue(), fg.Name)
+				Expect(fgs.GetEnabledOperandGates(fg.Operand)).To(Equal([]string{fg.OperandGate}), fg.Name)
+			}
+		})
+	})
+
+	Context("IsEnabled", func() {
+		It("should return false for nil HyperConvergedFeatureGates", func() {
+			var fgs *HyperConvergedFeatureGates = nil
+			Expect(fgs.IsEnabled(HotplugVolumesGateName)).To(BeFalse())
+		})
+
+		It("should return false for unset, disabled or unknown feature gates", func() {
+			fgs := &HyperConvergedFeatureGates{GPU: &disabled}
+			Expect(fgs.IsEnabled(HotplugVolumesGateName)).To(BeFalse())
+			Expect(fgs.IsEnabled(GPUGateName)).To(BeFalse())
+			Expect(fgs.IsEnabled("notAFeatureGate")).To(BeFalse())
+		})
+
+		It("should return true for enabled feature gates", func() {
+			fgs := &HyperConvergedFeatureGates{GPU: &enabled}
+			Expect(fgs.IsEnabled(GPUGateName)).To(BeTrue())
+			Expect(fgs.IsGPUAssignmentEnabled()).To(BeTrue())
+		})
+	})
+
+	Context("GetEnabledOperandGates", func() {
+		It("should return the operand gates in the registry order", func() {
+			fgs := &HyperConvergedFeatureGates{
+				HostDevices:    &enabled,
+				HotplugVolumes: &enabled,
+				GPU:            &disabled,
+			}
+			Expect(fgs.GetEnabledOperandGates(FeatureGateOperandKubeVirt)).To(Equal([]string{"HotplugVolumes", "HostDevices"}))
+			Expect(fgs.GetEnabledOperandGates("notAnOperand")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,9 @@
+package v1beta1
+
+// DeployOVSAnnotation controls the deployment of the Open vSwitch CNI plugin. In the v1 API, it is replaced by the
+// spec.deployOVS field.
+const DeployOVSAnnotation = "deployOVS"
+
+// Hub marks v1beta1 as the conversion hub; it is the storage version of the HyperConverged API. All the other
+// versions are converted from and to v1beta1.
+func (*HyperConverged) Hub() {}
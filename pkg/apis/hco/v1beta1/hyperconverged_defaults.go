@@ -0,0 +1,40 @@
+package v1beta1
+
+// SetDefaults sets the default values of the HyperConverged fields that are not set. It is the single source of truth
+// for the defaults, and is used by the mutating webhook on create and update, and by the reconciler during upgrades,
+// for the CRs that were created before a default was added. The CRD has no +kubebuilder:default markers, so the
+// defaults are not duplicated. Returns true if any field was set.
+func (r *HyperConverged) SetDefaults() bool {
+	changed := false
+
+	if r.Spec.FeatureGates == nil {
+		r.Spec.FeatureGates = &HyperConvergedFeatureGates{}
+		changed = true
+	}
+	if r.Spec.FeatureGates.SetDefaults() {
+		changed = true
+	}
+
+	if r.Spec.Components == nil {
+		r.Spec.Components = &HyperConvergedComponents{}
+		changed = true
+	}
+	if r.Spec.Components.SetDefaults() {
+		changed = true
+	}
+
+	return changed
+}
+
+// SetDefaults enables the components that are not set. Returns true if any component was set.
+func (c *HyperConvergedComponents) SetDefaults() bool {
+	changed := false
+	for _, component := range []**bool{&c.VMImport, &c.SSP, &c.NetworkAddons, &c.QuickStarts, &c.CLIDownloads, &c.Monitoring} {
+		if *component == nil {
+			enabled := true
+			*component = &enabled
+			changed = true
+		}
+	}
+	return changed
+}
@@ -39,10 +39,18 @@ type HyperConvergedSpec struct {
 	// featureGates is a map of feature gate flags. Setting a flag to `true` will enable
 	// the feature. Setting `false` or removing the feature gate, disables the feature.
 	// +optional
-	// +TODO: Always keep the default FeatureGates in sync with the default field values in HyperConvergedFeatureGates //NOSONAR
-	// +kubebuilder:default={sriovLiveMigration: false, hotplugVolumes: false, gpu: false, hostDevices: false, withHostPassthroughCPU: false, withHostModelCPU: true, hypervStrictCheck: true}
 	FeatureGates *HyperConvergedFeatureGates `json:"featureGates,omitempty"`
 
+	// hostPathProvisioner deploys the HostPath Provisioner, that provisions persistent volumes from a directory on the
+	// nodes' file system. The provisioner is not deployed if this field is not set.
+	// +optional
+	HostPathProvisioner *HostPathProvisionerConfig `json:"hostPathProvisioner,omitempty"`
+
+	// components enables or disables the optional components. All the components are enabled by default.
+	// Disabling a component removes its resources from the cluster.
+	// +optional
+	Components *HyperConvergedComponents `json:"components,omitempty"`
+
 	// operator version
 	Version string `json:"version,omitempty"`
 }
@@ -54,81 +62,147 @@ type HyperConvergedConfig struct {
 	NodePlacement *sdkapi.NodePlacement `json:"nodePlacement,omitempty"`
 }
 
+// HyperConvergedComponents enables or disables the optional components. A component that is not set is enabled.
+type HyperConvergedComponents struct {
+	// Deploy the VM import operator configuration, used to import virtual machines from other virtualization
+	// platforms
+	// +optional
+	VMImport *bool `json:"vmImport,omitempty"`
+
+	// Deploy the Scheduling, Scale and Performance operator configuration, including the common templates, the
+	// template validator and the node labeller
+	// +optional
+	SSP *bool `json:"ssp,omitempty"`
+
+	// Deploy the cluster network addons
+	// +optional
+	NetworkAddons *bool `json:"networkAddons,omitempty"`
+
+	// Deploy the console quick starts
+	// +optional
+	QuickStarts *bool `json:"quickStarts,omitempty"`
+
+	// Deploy the console download links of the virtctl command line interface
+	// +optional
+	CLIDownloads *bool `json:"cliDownloads,omitempty"`
+
+	// Deploy the metrics service, the service monitor and the alerting rules
+	// +optional
+	Monitoring *bool `json:"monitoring,omitempty"`
+}
+
+// HostPathProvisionerConfig defines the configuration of the HostPath Provisioner
+type HostPathProvisionerConfig struct {
+	// path is the directory on the nodes, where the persistent volumes are created
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// storagePool is the name of the storage pool backed by path. It's used as the name of the StorageClass that
+	// consumes the provisioner. Defaults to "hostpath-provisioner".
+	// +optional
+	StoragePool string `json:"storagePool,omitempty"`
+
+	// workload describes the node scheduling configuration of the provisioner pods. Defaults to the workloads node
+	// placement.
+	// +optional
+	Workload *sdkapi.NodePlacement `json:"workload,omitempty"`
+}
+
 type FeatureGate *bool
 
 // HyperConvergedFeatureGates is a set of optional feature gates to enable or disable new features that are not enabled
 // by default yet.
 // +optional
 // +k8s:openapi-gen=true
-// +kubebuilder:default={}
 type HyperConvergedFeatureGates struct {
 	// Allow migrating a virtual machine with SRIOV interfaces.
 	// When enabled virt-launcher pods of virtual machines with SRIOV
 	// interfaces run with CAP_SYS_RESOURCE capability.
 	// This may degrade virt-launcher security.
 	// +optional
-	// +kubebuilder:default=false
 	SRIOVLiveMigration FeatureGate `json:"sriovLiveMigration,omitempty"`
 
 	// Allow attaching a data volume to a running VMI
 	// +optional
-	// +kubebuilder:default=false
 	HotplugVolumes FeatureGate `json:"hotplugVolumes,omitempty"`
 
 	// Allow assigning GPU and vGPU devices to virtual machines
 	// +optional
-	// +kubebuilder:default=false
 	GPU FeatureGate `json:"gpu,omitempty"`
 
 	// Allow assigning host devices to virtual machines
 	// +optional
-	// +kubebuilder:default=false
 	HostDevices FeatureGate `json:"hostDevices,omitempty"`
 
 	// Allow migrating a virtual machine with CPU host-passthrough mode. This should be
 	// enabled only when the Cluster is homogeneous from CPU HW perspective doc here
 	// +optional
-	// +kubebuilder:default=false
 	WithHostPassthroughCPU FeatureGate `json:"withHostPassthroughCPU,omitempty"`
 
-	// Support migration for VMs with host-model CPU mode
+	// Support migration for VMs with host-model CPU mode. Defaults to true
 	// +optional
-	// +kubebuilder:default=true
 	WithHostModelCPU FeatureGate `json:"withHostModelCPU,omitempty"`
 
 	// Enable HyperV strict host checking for HyperV enlightenments
 	// Defaults to true, even when HyperConvergedFeatureGates is empty
 	// +optional
-	// +kubebuilder:default=true
 	HypervStrictCheck FeatureGate `json:"hypervStrictCheck,omitempty"`
 }
 
 func (fgs *HyperConvergedFeatureGates) IsHotplugVolumesEnabled() bool {
-	return (fgs != nil) && (fgs.HotplugVolumes != nil) && (*fgs.HotplugVolumes)
+	return fgs.IsEnabled(HotplugVolumesGateName)
 }
 
 func (fgs *HyperConvergedFeatureGates) IsGPUAssignmentEnabled() bool {
-	return (fgs != nil) && (fgs.GPU != nil) && (*fgs.GPU)
+	return fgs.IsEnabled(GPUGateName)
 }
 
 func (fgs *HyperConvergedFeatureGates) IsHostDevicesAssignmentEnabled() bool {
-	return (fgs != nil) && (fgs.HostDevices != nil) && (*fgs.HostDevices)
+	return fgs.IsEnabled(HostDevicesGateName)
 }
 
 func (fgs *HyperConvergedFeatureGates) IsSRIOVLiveMigrationEnabled() bool {
-	return (fgs != nil) && (fgs.SRIOVLiveMigration != nil) && (*fgs.SRIOVLiveMigration)
+	return fgs.IsEnabled(SRIOVLiveMigrationGateName)
 }
 
 func (fgs *HyperConvergedFeatureGates) IsWithHostPassthroughCPUEnabled() bool {
-	return (fgs != nil) && (fgs.WithHostPassthroughCPU != nil) && (*fgs.WithHostPassthroughCPU)
+	return fgs.IsEnabled(WithHostPassthroughCPUGateName)
 }
 
 func (fgs *HyperConvergedFeatureGates) IsWithHostModelCPUEnabled() bool {
-	return (fgs != nil) && (fgs.WithHostModelCPU != nil) && (*fgs.WithHostModelCPU)
+	return fgs.IsEnabled(WithHostModelCPUGateName)
 }
 
 func (fgs *HyperConvergedFeatureGates) IsHypervStrictCheckEnabled() bool {
-	return (fgs != nil) && (fgs.HypervStrictCheck != nil) && (*fgs.HypervStrictCheck)
+	return fgs.IsEnabled(HypervStrictCheckGateName)
+}
+
+func isComponentEnabled(component *bool) bool {
+	return component == nil || *component
+}
+
+func (c *HyperConvergedComponents) IsVMImportEnabled() bool {
+	return c == nil || isComponentEnabled(c.VMImport)
+}
+
+func (c *HyperConvergedComponents) IsSSPEnabled() bool {
+	return c == nil || isComponentEnabled(c.SSP)
+}
+
+func (c *HyperConvergedComponents) IsNetworkAddonsEnabled() bool {
+	return c == nil || isComponentEnabled(c.NetworkAddons)
+}
+
+func (c *HyperConvergedComponents) IsQuickStartsEnabled() bool {
+	return c == nil || isComponentEnabled(c.QuickStarts)
+}
+
+func (c *HyperConvergedComponents) IsCLIDownloadsEnabled() bool {
+	return c == nil || isComponentEnabled(c.CLIDownloads)
+}
+
+func (c *HyperConvergedComponents) IsMonitoringEnabled() bool {
+	return c == nil || isComponentEnabled(c.Monitoring)
 }
 
 // HyperConvergedStatus defines the observed state of HyperConverged
@@ -151,6 +225,81 @@ type HyperConvergedStatus struct {
 	// https://github.com/openshift/cluster-version-operator/blob/master/docs/dev/clusteroperator.md#version
 	// +optional
 	Versions Versions `json:"versions,omitempty"`
+
+	// NodesUnderMaintenance is a list of the nodes that are under maintenance by the node maintenance operator,
+	// sorted by node name.
+	// +optional
+	NodesUnderMaintenance []NodeMaintenanceStatus `json:"nodesUnderMaintenance,omitempty"`
+
+	// CompletedMigrations is a list of the names of the migrations that were completed during the current or the last
+	// upgrade, so an interrupted upgrade does not run them again.
+	// +listType=set
+	// +optional
+	CompletedMigrations []string `json:"completedMigrations,omitempty"`
+
+	// UpgradePreflightChecks is a list of the results of the pre-flight checks that ran before the last upgrade, or
+	// before the pending upgrade.
+	// +optional
+	UpgradePreflightChecks []UpgradePreflightCheckStatus `json:"upgradePreflightChecks,omitempty"`
+
+	// AppliedPatches is a list of the patch annotations of the HyperConverged CR that were applied to the operand
+	// resources in the last reconciliation.
+	// +optional
+	AppliedPatches []AppliedPatchStatus `json:"appliedPatches,omitempty"`
+}
+
+// AppliedPatchStatus describes a patch annotation that was applied to an operand resource
+type AppliedPatchStatus struct {
+	// Annotation is the name of the patch annotation of the HyperConverged CR
+	Annotation string `json:"annotation"`
+
+	// Type is the type of the patch; one of json, merge or strategic
+	Type string `json:"type"`
+
+	// Kind is the kind of the patched resource
+	Kind string `json:"kind"`
+
+	// Name is the name of the patched resource
+	Name string `json:"name"`
+}
+
+// UpgradePreflightCheckStatus is the result of an upgrade pre-flight check
+type UpgradePreflightCheckStatus struct {
+	// Name is the name of the check
+	Name string `json:"name"`
+
+	// Blocking is true if a failure of the check prevents the upgrade
+	Blocking bool `json:"blocking"`
+
+	// Passed is true if the check did not find any issue
+	Passed bool `json:"passed"`
+
+	// Message describes the issues that the check found
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NodeMaintenanceStatus describes the maintenance of a node
+type NodeMaintenanceStatus struct {
+	// NodeName is the name of the node under maintenance
+	NodeName string `json:"nodeName"`
+
+	// Reason is the reason for the maintenance
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Phase is the progress of the maintenance; "Running" while the node is being drained, "Succeeded" once the node
+	// is drained, or "Failed"
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// PendingPods is the number of pods that were not evicted from the node yet
+	// +optional
+	PendingPods int `json:"pendingPods,omitempty"`
+
+	// PendingVMs is the number of virtual machines that were not migrated or shut down yet
+	// +optional
+	PendingVMs int `json:"pendingVMs,omitempty"`
 }
 
 func (hcs *HyperConvergedStatus) UpdateVersion(name, version string) {
@@ -204,6 +353,18 @@ const (
 	// has been applied to the HyperConverged resource via a specialized annotation.
 	// This condition is exposed only when its value is True, and is otherwise hidden.
 	ConditionTaintedConfiguration conditionsv1.ConditionType = "TaintedConfiguration"
+
+	// ConditionHeterogeneousCPUModels indicates that the withHostPassthroughCPU feature gate is enabled, but the
+	// workload nodes support different CPU models, so the live migration of virtual machines with the
+	// host-passthrough CPU mode may fail. The message lists the distinct CPU models of the workload nodes.
+	// This condition is exposed only when its value is True, and is otherwise hidden.
+	ConditionHeterogeneousCPUModels conditionsv1.ConditionType = "HeterogeneousCPUModels"
+
+	// ConditionPlacementUnsatisfiable indicates that the infra or the workloads node placement does not match any
+	// eligible node, so the operand pods that use it can't be scheduled. A node is eligible if it matches the node
+	// selector, the required node affinity and the tolerations of the placement, and is not under maintenance. The
+	// message reports the number of the eligible nodes of each placement.
+	ConditionPlacementUnsatisfiable conditionsv1.ConditionType = "PlacementUnsatisfiable"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1,6 +1,7 @@
 package v1beta1
 
 import (
+	"io/ioutil"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
@@ -546,4 +547,91 @@ var _ = Describe("HyperconvergedTypes", func() {
 			Expect(aCopy.Spec.Infra.NodePlacement).ShouldNot(Equal(hco.Spec.Infra.NodePlacement))
 		})
 	})
+
+	Describe("HyperConvergedComponents", func() {
+		It("Should enable all the components if the components are not set", func() {
+			var components *HyperConvergedComponents
+			Expect(components.IsVMImportEnabled()).To(BeTrue())
+			Expect(components.IsSSPEnabled()).To(BeTrue())
+			Expect(components.IsNetworkAddonsEnabled()).To(BeTrue())
+			Expect(components.IsQuickStartsEnabled()).To(BeTrue())
+			Expect(components.IsCLIDownloadsEnabled()).To(BeTrue())
+			Expect(components.IsMonitoringEnabled()).To(BeTrue())
+		})
+
+		It("Should enable the components that are not set", func() {
+			components := &HyperConvergedComponents{}
+			Expect(components.IsVMImportEnabled()).To(BeTrue())
+			Expect(components.IsSSPEnabled()).To(BeTrue())
+			Expect(components.IsNetworkAddonsEnabled()).To(BeTrue())
+			Expect(components.IsQuickStartsEnabled()).To(BeTrue())
+			Expect(components.IsCLIDownloadsEnabled()).To(BeTrue())
+			Expect(components.IsMonitoringEnabled()).To(BeTrue())
+		})
+
+		It("Should disable only the disabled components", func() {
+			enabled := true
+			disabled := false
+			components := &HyperConvergedComponents{
+				VMImport:   &disabled,
+				SSP:        &enabled,
+				Monitoring: &disabled,
+			}
+			Expect(components.IsVMImportEnabled()).To(BeFalse())
+			Expect(components.IsSSPEnabled()).To(BeTrue())
+			Expect(components.IsNetworkAddonsEnabled()).To(BeTrue())
+			Expect(components.IsQuickStartsEnabled()).To(BeTrue())
+			Expect(components.IsCLIDownloadsEnabled()).To(BeTrue())
+			Expect(components.IsMonitoringEnabled()).To(BeFalse())
+		})
+	})
+
+	Describe("HyperConverged.SetDefaults", func() {
+		It("should set the defaults of an empty spec", func() {
+			hc := &HyperConverged{}
+			Expect(hc.SetDefaults()).To(BeTrue())
+
+			Expect(hc.Spec.FeatureGates).ToNot(BeNil())
+			for _, fg := range GetFeatureGateDescriptors() {
+				value := fg.field(hc.Spec.FeatureGates)
+				Expect(*value).ToNot(BeNil(), "feature gate %s was not set", fg.Name)
+				Expect(**value).To(Equal(fg.Default), "wrong default for %s", fg.Name)
+			}
+			Expect(hc.Spec.Components).ToNot(BeNil())
+			for _, enabled := range []*bool{hc.Spec.Components.VMImport, hc.Spec.Components.SSP, hc.Spec.Components.NetworkAddons,
+				hc.Spec.Components.QuickStarts, hc.Spec.Components.CLIDownloads, hc.Spec.Components.Monitoring} {
+				Expect(enabled).ToNot(BeNil())
+				Expect(*enabled).To(BeTrue())
+			}
+		})
+
+		It("should not override the values that are already set", func() {
+			disabled := false
+			hc := &HyperConverged{
+				Spec: HyperConvergedSpec{
+					FeatureGates: &HyperConvergedFeatureGates{WithHostModelCPU: &disabled},
+					Components:   &HyperConvergedComponents{SSP: &disabled},
+				},
+			}
+			Expect(hc.SetDefaults()).To(BeTrue())
+
+			Expect(*hc.Spec.FeatureGates.WithHostModelCPU).To(BeFalse())
+			Expect(*hc.Spec.FeatureGates.HypervStrictCheck).To(BeTrue())
+			Expect(*hc.Spec.Components.SSP).To(BeFalse())
+			Expect(*hc.Spec.Components.VMImport).To(BeTrue())
+			Expect(*hc.Spec.Components.Monitoring).To(BeTrue())
+		})
+
+		It("should return false if all the defaults are already set", func() {
+			hc := &HyperConverged{}
+			Expect(hc.SetDefaults()).To(BeTrue())
+			Expect(hc.SetDefaults()).To(BeFalse())
+		})
+
+		It("should be the only source of the defaults", func() {
+			types, err := ioutil.ReadFile("hyperconverged_types.go")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(types)).ToNot(ContainSubstring("+kubebuilder:default"))
+		})
+	})
 })
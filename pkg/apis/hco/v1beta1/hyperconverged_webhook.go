@@ -2,6 +2,8 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,15 +12,20 @@ import (
 	"github.com/go-logr/logr"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	admissionv1 "k8s.io/api/admission/v1"
-	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 )
 
 const (
@@ -44,10 +51,15 @@ func GetWebhookCertDir() string {
 
 type WebhookHandlerIfs interface {
 	Init(logger logr.Logger, cli client.Client, namespace string, isOpenshift bool)
-	ValidateCreate(hc *HyperConverged) error
-	ValidateUpdate(requested *HyperConverged, exists *HyperConverged) error
+	// ValidateCreate and ValidateUpdate return the admission warnings of an accepted CR
+	ValidateCreate(hc *HyperConverged) ([]string, error)
+	ValidateUpdate(requested *HyperConverged, exists *HyperConverged) ([]string, error)
+	// AuditUpdate records an admitted update of the HyperConverged CR, and the user that made it
+	AuditUpdate(requested *HyperConverged, exists *HyperConverged, userInfo authenticationv1.UserInfo)
 	ValidateDelete(hc *HyperConverged) error
 	HandleMutatingNsDelete(ns *corev1.Namespace, dryRun bool) (bool, error)
+	// ValidateOperandUpdate validates a spec change of an operand CR, e.g. KubeVirt or CDI
+	ValidateOperandUpdate(requested, exists *unstructured.Unstructured, username string) error
 }
 
 var whHandler WebhookHandlerIfs
@@ -72,26 +84,29 @@ func (r *HyperConverged) SetupWebhookWithManager(ctx context.Context, mgr ctrl.M
 		}
 	}
 
-	if err := allowWatchAllNamespaces(ctx, mgr); err != nil {
-		return err
-	}
-
-	bldr := ctrl.NewWebhookManagedBy(mgr).For(r)
-
 	srv := mgr.GetWebhookServer()
 	srv.CertDir = GetWebhookCertDir()
 	srv.CertName = WebhookCertName
 	srv.KeyName = WebhookKeyName
 	srv.Port = hcoutil.WebhookPort
+	srv.Register(hcoutil.HCOWebhookPath, &webhook.Admission{Handler: &hcValidator{}})
+	srv.Register(hcoutil.HCOMutatingWebhookPath, &webhook.Admission{Handler: &hcMutator{operatorUser: getServiceAccountUser(operatorNsEnv, hcoutil.HCOServiceAccountName)}})
 	srv.Register(hcoutil.HCONSWebhookPath, &webhook.Admission{Handler: &nsMutator{}})
+	srv.Register(hcoutil.HCOOperandWebhookPath, &webhook.Admission{Handler: &operandValidator{}})
+	// converts HyperConverged between v1beta1 (the hub) and the other served versions. The manager scheme must
+	// contain all the HyperConverged versions.
+	srv.Register(hcoutil.HCOConvertWebhookPath, &conversion.Webhook{})
 
-	return bldr.Complete()
+	return nil
 }
 
 var _ webhook.Validator = &HyperConverged{}
 
+// ValidateCreate implements webhook.Validator. The admission warnings are returned only by the HyperConverged
+// validating handler.
 func (r *HyperConverged) ValidateCreate() error {
-	return whHandler.ValidateCreate(r)
+	_, err := whHandler.ValidateCreate(r)
+	return err
 }
 
 func (r *HyperConverged) ValidateUpdate(old runtime.Object) error {
@@ -100,13 +115,195 @@ func (r *HyperConverged) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("expect old object to be a %T instead of %T", oldR, old)
 	}
 
-	return whHandler.ValidateUpdate(r, oldR)
+	_, err := whHandler.ValidateUpdate(r, oldR)
+	return err
 }
 
 func (r *HyperConverged) ValidateDelete() error {
 	return whHandler.ValidateDelete(r)
 }
 
+// hcValidator validates HyperConverged requests. Unlike the generic controller-runtime validating handler, it also
+// returns the admission warnings of the webhook handler, e.g. for deprecated feature gates.
+type hcValidator struct {
+	decoder *admission.Decoder
+}
+
+func (v *hcValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	hc := &HyperConverged{}
+
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := v.decoder.Decode(req, hc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		warnings, err := whHandler.ValidateCreate(hc)
+		if err != nil {
+			return validationDenied(err)
+		}
+
+		return admission.Allowed("").WithWarnings(warnings...)
+
+	case admissionv1.Update:
+		oldHc := &HyperConverged{}
+		if err := v.decoder.Decode(req, hc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldHc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		warnings, err := whHandler.ValidateUpdate(hc, oldHc)
+		if err != nil {
+			return validationDenied(err)
+		}
+
+		// a dry-run request does not change the CR
+		if req.DryRun == nil || !*req.DryRun {
+			whHandler.AuditUpdate(hc, oldHc, req.UserInfo)
+		}
+
+		return admission.Allowed("").WithWarnings(warnings...)
+
+	case admissionv1.Delete:
+		// In reference to PR: https://github.com/kubernetes/kubernetes/pull/76346
+		// OldObject contains the object being deleted
+		if err := v.decoder.DecodeRaw(req.OldObject, hc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		if err := hc.ValidateDelete(); err != nil {
+			return validationDenied(err)
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+// hcValidator implements admission.DecoderInjector.
+// A decoder will be automatically injected.
+
+// InjectDecoder injects the decoder.
+func (v *hcValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func validationDenied(err error) admission.Response {
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		status := apiStatus.Status()
+		return admission.Response{
+			AdmissionResponse: admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result:  &status,
+			},
+		}
+	}
+	return admission.Denied(err.Error())
+}
+
+// hcMutator sets the defaults of the HyperConverged CR on create and update, and reverts the changes of the fields
+// that are managed by HCO, unless they were made by HCO itself.
+type hcMutator struct {
+	decoder *admission.Decoder
+	// the scheme of the other versions of the HyperConverged API, that are converted to v1beta1
+	scheme *runtime.Scheme
+	// the user name of the HCO operator service account
+	operatorUser string
+}
+
+func (m *hcMutator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("ignoring other operations")
+	}
+
+	hc, versioned, err := m.decode(req.Kind, req.Object)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	hc.SetDefaults()
+
+	if req.UserInfo.Username != m.operatorUser {
+		// spec.version is set by HCO when an upgrade is completed
+		if req.Operation == admissionv1.Create {
+			hc.Spec.Version = ""
+		} else {
+			oldHc, _, err := m.decode(req.Kind, req.OldObject)
+			if err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			hc.Spec.Version = oldHc.Spec.Version
+		}
+	}
+
+	// the patch must apply to the requested version
+	var mutated runtime.Object = hc
+	if versioned != nil {
+		if err = versioned.ConvertFrom(hc); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		mutated = versioned
+	}
+
+	marshaled, err := json.Marshal(mutated)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// decode decodes a HyperConverged CR of any version of the API, and converts it to v1beta1. If the CR is of another
+// version, decode also returns the decoded CR, to convert the mutated v1beta1 CR back to its version.
+func (m *hcMutator) decode(kind metav1.GroupVersionKind, raw runtime.RawExtension) (*HyperConverged, ctrlconversion.Convertible, error) {
+	hc := &HyperConverged{}
+	if kind.Version == "" || kind.Version == SchemeGroupVersion.Version {
+		return hc, nil, m.decoder.DecodeRaw(raw, hc)
+	}
+
+	obj, err := m.scheme.New(schema.GroupVersionKind(kind))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	versioned, ok := obj.(ctrlconversion.Convertible)
+	if !ok {
+		return nil, nil, fmt.Errorf("can't convert the %s version of the HyperConverged API", kind.Version)
+	}
+
+	if err = m.decoder.DecodeRaw(raw, versioned); err != nil {
+		return nil, nil, err
+	}
+
+	if err = versioned.ConvertTo(hc); err != nil {
+		return nil, nil, err
+	}
+
+	return hc, versioned, nil
+}
+
+// hcMutator implements admission.DecoderInjector.
+// A decoder will be automatically injected.
+
+// InjectDecoder injects the decoder.
+func (m *hcMutator) InjectDecoder(d *admission.Decoder) error {
+	m.decoder = d
+	return nil
+}
+
+// InjectScheme injects the scheme.
+func (m *hcMutator) InjectScheme(s *runtime.Scheme) error {
+	m.scheme = s
+	return nil
+}
+
+func getServiceAccountUser(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
 // nsMutator mutates Ns requests
 type nsMutator struct {
 	decoder *admission.Decoder
@@ -148,39 +345,30 @@ func (a *nsMutator) handleNsDelete(req admission.Request) admission.Response {
 	return admission.Denied("HyperConverged CR is still present, please remove it before deleting the containing namespace")
 }
 
-// The OLM limits the webhook scope to the namespaces that are defined in the OperatorGroup
-// by setting namespaceSelector in the ValidatingWebhookConfiguration. We would like our webhook to intercept
-// requests from all namespaces, and fail them if they're not in the correct namespace for HCO (for CREATE).
-// Luckily the OLM does not watch and reconcile the ValidatingWebhookConfiguration so we can simply reset the
-// namespaceSelector
-func allowWatchAllNamespaces(ctx context.Context, mgr ctrl.Manager) error {
-	vwcList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
-	err := mgr.GetAPIReader().List(ctx, vwcList, client.MatchingLabels{"olm.webhook-description-generate-name": hcoutil.HcoValidatingWebhook})
-	if err != nil {
-		hcolog.Error(err, "A validating webhook for the HCO was not found")
-		return err
+// operandValidator guards the operand CRs, e.g. KubeVirt or CDI, against spec changes that were not made by HCO.
+// The operand types are not decoded, so the validator does not depend on their API versions.
+type operandValidator struct{}
+
+func (v *operandValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update {
+		return admission.Allowed("ignoring other operations")
 	}
 
-	for _, vwc := range vwcList.Items {
-		update := false
+	requested := &unstructured.Unstructured{}
+	if err := requested.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
 
-		for i, wh := range vwc.Webhooks {
-			if wh.Name == hcoutil.HcoValidatingWebhook {
-				vwc.Webhooks[i].NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{}}
-				update = true
-			}
-		}
+	exists := &unstructured.Unstructured{}
+	if err := exists.UnmarshalJSON(req.OldObject.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
 
-		if update {
-			hcolog.Info("Removing namespace scope from webhook", "webhook", vwc.Name)
-			err = mgr.GetClient().Update(ctx, &vwc)
-			if err != nil {
-				hcolog.Error(err, "Failed updating webhook", "webhook", vwc.Name)
-				return err
-			}
-		}
+	if err := whHandler.ValidateOperandUpdate(requested, exists, req.UserInfo.Username); err != nil {
+		return validationDenied(err)
 	}
-	return nil
+
+	return admission.Allowed("")
 }
 
 // nsMutator implements admission.DecoderInjector.
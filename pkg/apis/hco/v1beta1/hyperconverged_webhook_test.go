@@ -0,0 +1,390 @@
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	jsonpatch "gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type fakeWebhookHandler struct {
+	err      error
+	warnings []string
+	// the users of the audited updates
+	audited *[]string
+}
+
+func (f fakeWebhookHandler) Init(_ logr.Logger, _ client.Client, _ string, _ bool) {}
+func (f fakeWebhookHandler) ValidateCreate(_ *HyperConverged) ([]string, error) {
+	return f.warnings, f.err
+}
+func (f fakeWebhookHandler) ValidateUpdate(_ *HyperConverged, _ *HyperConverged) ([]string, error) {
+	return f.warnings, f.err
+}
+func (f fakeWebhookHandler) AuditUpdate(_ *HyperConverged, _ *HyperConverged, userInfo authenticationv1.UserInfo) {
+	if f.audited != nil {
+		*f.audited = append(*f.audited, userInfo.Username)
+	}
+}
+func (f fakeWebhookHandler) ValidateDelete(_ *HyperConverged) error { return f.err }
+func (f fakeWebhookHandler) HandleMutatingNsDelete(_ *corev1.Namespace, _ bool) (bool, error) {
+	return true, nil
+}
+func (f fakeWebhookHandler) ValidateOperandUpdate(_, _ *unstructured.Unstructured, _ string) error {
+	return f.err
+}
+
+var _ = Describe("HyperConverged validating handler", func() {
+	enabled := true
+
+	var validator *hcValidator
+
+	newRequest := func(operation admissionv1.Operation, hc *HyperConverged) admission.Request {
+		raw, err := json.Marshal(hc)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+			},
+		}
+
+		switch operation {
+		case admissionv1.Create:
+			req.Object = runtime.RawExtension{Raw: raw}
+		case admissionv1.Update:
+			req.Object = runtime.RawExtension{Raw: raw}
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		case admissionv1.Delete:
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+
+		return req
+	}
+
+	newHc := func() *HyperConverged {
+		return &HyperConverged{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: SchemeGroupVersion.String(),
+				Kind:       "HyperConverged",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      HyperConvergedName,
+				Namespace: "kubevirt-hyperconverged",
+			},
+			Spec: HyperConvergedSpec{
+				FeatureGates: &HyperConvergedFeatureGates{
+					HotplugVolumes: &enabled,
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(AddToScheme(s)).To(Succeed())
+		decoder, err := admission.NewDecoder(s)
+		Expect(err).ToNot(HaveOccurred())
+
+		validator = &hcValidator{}
+		Expect(validator.InjectDecoder(decoder)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		whHandler = nil
+	})
+
+	for _, op := range []admissionv1.Operation{admissionv1.Create, admissionv1.Update} {
+		operation := op
+
+		It("should allow "+string(operation)+" with the warnings of the webhook handler", func() {
+			whHandler = fakeWebhookHandler{warnings: []string{"fake warning"}}
+			resp := validator.Handle(context.TODO(), newRequest(operation, newHc()))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ConsistOf("fake warning"))
+		})
+
+		It("should deny "+string(operation)+" if the validation failed", func() {
+			whHandler = fakeWebhookHandler{err: errors.New("fake error"), warnings: []string{"fake warning"}}
+			resp := validator.Handle(context.TODO(), newRequest(operation, newHc()))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(string(resp.Result.Reason)).To(Equal("fake error"))
+			Expect(resp.Warnings).To(BeEmpty())
+		})
+	}
+
+	It("should audit an accepted update", func() {
+		var audited []string
+		whHandler = fakeWebhookHandler{audited: &audited}
+
+		req := newRequest(admissionv1.Update, newHc())
+		req.UserInfo = authenticationv1.UserInfo{Username: "kube:admin"}
+		Expect(validator.Handle(context.TODO(), req).Allowed).To(BeTrue())
+		Expect(audited).To(Equal([]string{"kube:admin"}))
+	})
+
+	It("should not audit a dry-run update", func() {
+		var audited []string
+		whHandler = fakeWebhookHandler{audited: &audited}
+
+		dryRun := true
+		req := newRequest(admissionv1.Update, newHc())
+		req.DryRun = &dryRun
+		Expect(validator.Handle(context.TODO(), req).Allowed).To(BeTrue())
+		Expect(audited).To(BeEmpty())
+	})
+
+	It("should not audit a denied update", func() {
+		var audited []string
+		whHandler = fakeWebhookHandler{err: errors.New("fake error"), audited: &audited}
+
+		Expect(validator.Handle(context.TODO(), newRequest(admissionv1.Update, newHc())).Allowed).To(BeFalse())
+		Expect(audited).To(BeEmpty())
+	})
+
+	It("should allow delete", func() {
+		whHandler = fakeWebhookHandler{}
+		resp := validator.Handle(context.TODO(), newRequest(admissionv1.Delete, newHc()))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(BeEmpty())
+	})
+
+	It("should keep the status of API errors", func() {
+		whHandler = fakeWebhookHandler{err: apierrors.NewConflict(schema.GroupResource{Group: "kubevirt.io", Resource: "kubevirts"}, "kubevirt", errors.New("fake conflict"))}
+		resp := validator.Handle(context.TODO(), newRequest(admissionv1.Delete, newHc()))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Reason).To(Equal(metav1.StatusReasonConflict))
+	})
+})
+
+// fakeV1HyperConverged is a minimal version of the HyperConverged API, other than the v1beta1 hub
+type fakeV1HyperConverged struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		FeatureGates *HyperConvergedFeatureGates `json:"featureGates,omitempty"`
+	} `json:"spec,omitempty"`
+}
+
+func (in *fakeV1HyperConverged) DeepCopyObject() runtime.Object {
+	out := *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.FeatureGates = in.Spec.FeatureGates.DeepCopy()
+	return &out
+}
+
+func (in *fakeV1HyperConverged) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*HyperConverged)
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec.FeatureGates = in.Spec.FeatureGates.DeepCopy()
+	return nil
+}
+
+func (in *fakeV1HyperConverged) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*HyperConverged)
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	in.Spec.FeatureGates = src.Spec.FeatureGates.DeepCopy()
+	return nil
+}
+
+var _ = Describe("HyperConverged mutating handler", func() {
+	const operatorUser = "system:serviceaccount:kubevirt-hyperconverged:hyperconverged-cluster-operator"
+
+	var mutator *hcMutator
+
+	newRequest := func(operation admissionv1.Operation, user string, hc, oldHc *HyperConverged) admission.Request {
+		raw, err := json.Marshal(hc)
+		Expect(err).ToNot(HaveOccurred())
+
+		req := admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		req.UserInfo.Username = user
+
+		if oldHc != nil {
+			oldRaw, err := json.Marshal(oldHc)
+			Expect(err).ToNot(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: oldRaw}
+		}
+
+		return req
+	}
+
+	newHc := func(version string) *HyperConverged {
+		return &HyperConverged{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: SchemeGroupVersion.String(),
+				Kind:       "HyperConverged",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      HyperConvergedName,
+				Namespace: "kubevirt-hyperconverged",
+			},
+			Spec: HyperConvergedSpec{
+				FeatureGates: &HyperConvergedFeatureGates{},
+				Version:      version,
+			},
+		}
+	}
+
+	hasPatch := func(resp admission.Response, op, path string) bool {
+		for _, patch := range resp.Patches {
+			if patch.Operation == op && patch.Path == path {
+				return true
+			}
+		}
+		return false
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(AddToScheme(s)).To(Succeed())
+		decoder, err := admission.NewDecoder(s)
+		Expect(err).ToNot(HaveOccurred())
+
+		mutator = &hcMutator{operatorUser: operatorUser}
+		Expect(mutator.InjectDecoder(decoder)).To(Succeed())
+		Expect(mutator.InjectScheme(s)).To(Succeed())
+	})
+
+	It("should set the defaults on create", func() {
+		resp := mutator.Handle(context.TODO(), newRequest(admissionv1.Create, "user", newHc(""), nil))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(hasPatch(resp, "add", "/spec/featureGates/withHostModelCPU")).To(BeTrue())
+		Expect(hasPatch(resp, "add", "/spec/featureGates/hypervStrictCheck")).To(BeTrue())
+	})
+
+	It("should not patch a CR with all the defaults", func() {
+		hc := newHc("")
+		hc.SetDefaults()
+		resp := mutator.Handle(context.TODO(), newRequest(admissionv1.Update, "user", hc, hc))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should remove spec.version on create by a user", func() {
+		resp := mutator.Handle(context.TODO(), newRequest(admissionv1.Create, "user", newHc("1.4.0"), nil))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(hasPatch(resp, "remove", "/spec/version")).To(BeTrue())
+	})
+
+	It("should restore spec.version on update by a user", func() {
+		resp := mutator.Handle(context.TODO(), newRequest(admissionv1.Update, "user", newHc("9.9.9"), newHc("1.4.0")))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(ContainElement(jsonpatch.NewOperation("replace", "/spec/version", "1.4.0")))
+	})
+
+	It("should allow the operator to set spec.version", func() {
+		resp := mutator.Handle(context.TODO(), newRequest(admissionv1.Update, operatorUser, newHc("1.4.0"), newHc("1.3.0")))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(hasPatch(resp, "replace", "/spec/version")).To(BeFalse())
+	})
+
+	Context("other versions of the API", func() {
+		v1Kind := metav1.GroupVersionKind{Group: SchemeGroupVersion.Group, Version: "v1", Kind: "HyperConverged"}
+
+		newV1Request := func(raw string) admission.Request {
+			return admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Kind:      v1Kind,
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: []byte(raw)},
+				},
+			}
+		}
+
+		It("should set the defaults, and patch the requested version", func() {
+			s := runtime.NewScheme()
+			Expect(AddToScheme(s)).To(Succeed())
+			s.AddKnownTypeWithName(schema.GroupVersionKind(v1Kind), &fakeV1HyperConverged{})
+			Expect(mutator.InjectScheme(s)).To(Succeed())
+
+			resp := mutator.Handle(context.TODO(), newV1Request(
+				`{"apiVersion":"hco.kubevirt.io/v1","kind":"HyperConverged","metadata":{"name":"kubevirt-hyperconverged"},"spec":{}}`,
+			))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(hasPatch(resp, "add", "/spec/featureGates")).To(BeTrue())
+			for _, patch := range resp.Patches {
+				Expect(patch.Path).ToNot(Equal("/apiVersion"))
+				Expect(patch.Path).ToNot(HavePrefix("/spec/version"))
+			}
+		})
+
+		It("should reject a version that is not registered", func() {
+			resp := mutator.Handle(context.TODO(), newV1Request(`{"apiVersion":"hco.kubevirt.io/v1","kind":"HyperConverged"}`))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+	})
+
+	It("should ignore delete", func() {
+		resp := mutator.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Operand validating handler", func() {
+	const kv = `{"apiVersion":"kubevirt.io/v1","kind":"KubeVirt","metadata":{"name":"kubevirt-kubevirt-hyperconverged"},"spec":{}}`
+
+	newRequest := func(operation admissionv1.Operation) admission.Request {
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: []byte(kv)},
+				OldObject: runtime.RawExtension{Raw: []byte(kv)},
+			},
+		}
+	}
+
+	AfterEach(func() {
+		whHandler = nil
+	})
+
+	It("should allow the update if the validation passed", func() {
+		whHandler = fakeWebhookHandler{}
+		resp := (&operandValidator{}).Handle(context.TODO(), newRequest(admissionv1.Update))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should deny the update if the validation failed", func() {
+		whHandler = fakeWebhookHandler{err: errors.New("fake error")}
+		resp := (&operandValidator{}).Handle(context.TODO(), newRequest(admissionv1.Update))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(string(resp.Result.Reason)).To(Equal("fake error"))
+	})
+
+	It("should ignore the other operations", func() {
+		whHandler = fakeWebhookHandler{err: errors.New("fake error")}
+		resp := (&operandValidator{}).Handle(context.TODO(), newRequest(admissionv1.Delete))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should reject a malformed object", func() {
+		whHandler = fakeWebhookHandler{}
+		req := newRequest(admissionv1.Update)
+		req.Object = runtime.RawExtension{Raw: []byte("not a json")}
+		resp := (&operandValidator{}).Handle(context.TODO(), req)
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(BeEquivalentTo(http.StatusBadRequest))
+	})
+})
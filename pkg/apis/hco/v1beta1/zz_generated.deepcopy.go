@@ -26,6 +26,42 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedPatchStatus) DeepCopyInto(out *AppliedPatchStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedPatchStatus.
+func (in *AppliedPatchStatus) DeepCopy() *AppliedPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostPathProvisionerConfig) DeepCopyInto(out *HostPathProvisionerConfig) {
+	*out = *in
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPathProvisionerConfig.
+func (in *HostPathProvisionerConfig) DeepCopy() *HostPathProvisionerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HostPathProvisionerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HyperConverged) DeepCopyInto(out *HyperConverged) {
 	*out = *in
@@ -54,6 +90,52 @@ func (in *HyperConverged) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HyperConvergedComponents) DeepCopyInto(out *HyperConvergedComponents) {
+	*out = *in
+	if in.VMImport != nil {
+		in, out := &in.VMImport, &out.VMImport
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SSP != nil {
+		in, out := &in.SSP, &out.SSP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NetworkAddons != nil {
+		in, out := &in.NetworkAddons, &out.NetworkAddons
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QuickStarts != nil {
+		in, out := &in.QuickStarts, &out.QuickStarts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CLIDownloads != nil {
+		in, out := &in.CLIDownloads, &out.CLIDownloads
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HyperConvergedComponents.
+func (in *HyperConvergedComponents) DeepCopy() *HyperConvergedComponents {
+	if in == nil {
+		return nil
+	}
+	out := new(HyperConvergedComponents)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HyperConvergedConfig) DeepCopyInto(out *HyperConvergedConfig) {
 	*out = *in
@@ -77,11 +159,26 @@ func (in *HyperConvergedConfig) DeepCopy() *HyperConvergedConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HyperConvergedFeatureGates) DeepCopyInto(out *HyperConvergedFeatureGates) {
 	*out = *in
+	if in.SRIOVLiveMigration != nil {
+		in, out := &in.SRIOVLiveMigration, &out.SRIOVLiveMigration
+		*out = new(bool)
+		**out = **in
+	}
 	if in.HotplugVolumes != nil {
 		in, out := &in.HotplugVolumes, &out.HotplugVolumes
 		*out = new(bool)
 		**out = **in
 	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostDevices != nil {
+		in, out := &in.HostDevices, &out.HostDevices
+		*out = new(bool)
+		**out = **in
+	}
 	if in.WithHostPassthroughCPU != nil {
 		in, out := &in.WithHostPassthroughCPU, &out.WithHostPassthroughCPU
 		*out = new(bool)
@@ -92,6 +189,11 @@ func (in *HyperConvergedFeatureGates) DeepCopyInto(out *HyperConvergedFeatureGat
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HypervStrictCheck != nil {
+		in, out := &in.HypervStrictCheck, &out.HypervStrictCheck
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -148,6 +250,16 @@ func (in *HyperConvergedSpec) DeepCopyInto(out *HyperConvergedSpec) {
 		*out = new(HyperConvergedFeatureGates)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostPathProvisioner != nil {
+		in, out := &in.HostPathProvisioner, &out.HostPathProvisioner
+		*out = new(HostPathProvisionerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(HyperConvergedComponents)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -181,6 +293,26 @@ func (in *HyperConvergedStatus) DeepCopyInto(out *HyperConvergedStatus) {
 		*out = make(Versions, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodesUnderMaintenance != nil {
+		in, out := &in.NodesUnderMaintenance, &out.NodesUnderMaintenance
+		*out = make([]NodeMaintenanceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletedMigrations != nil {
+		in, out := &in.CompletedMigrations, &out.CompletedMigrations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradePreflightChecks != nil {
+		in, out := &in.UpgradePreflightChecks, &out.UpgradePreflightChecks
+		*out = make([]UpgradePreflightCheckStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedPatches != nil {
+		in, out := &in.AppliedPatches, &out.AppliedPatches
+		*out = make([]AppliedPatchStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -194,6 +326,38 @@ func (in *HyperConvergedStatus) DeepCopy() *HyperConvergedStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMaintenanceStatus) DeepCopyInto(out *NodeMaintenanceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMaintenanceStatus.
+func (in *NodeMaintenanceStatus) DeepCopy() *NodeMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePreflightCheckStatus) DeepCopyInto(out *UpgradePreflightCheckStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePreflightCheckStatus.
+func (in *UpgradePreflightCheckStatus) DeepCopy() *UpgradePreflightCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePreflightCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Version) DeepCopyInto(out *Version) {
 	*out = *in
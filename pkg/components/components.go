@@ -37,7 +37,7 @@ import (
 const (
 	crName              = util.HyperConvergedName
 	packageName         = util.HyperConvergedName
-	hcoName             = "hyperconverged-cluster-operator"
+	hcoName             = util.HCOServiceAccountName
 	hcoNameWebhook      = "hyperconverged-cluster-webhook"
 	hcoDeploymentName   = "hco-operator"
 	hcoWhDeploymentName = "hco-webhook"
@@ -479,6 +479,46 @@ func GetClusterPermissions() []rbacv1.PolicyRule {
 				"*",
 			},
 		},
+		{
+			APIGroups: []string{
+				"hostpathprovisioner.kubevirt.io",
+			},
+			Resources: []string{
+				"*",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				"nodemaintenance.kubevirt.io",
+			},
+			Resources: []string{
+				"nodemaintenances",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"storage.k8s.io",
+			},
+			Resources: []string{
+				"storageclasses",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"create",
+				"delete",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"machineremediation.kubevirt.io",
@@ -520,6 +560,7 @@ func GetClusterPermissions() []rbacv1.PolicyRule {
 			Verbs: []string{
 				"get",
 				"list",
+				"watch",
 			},
 		},
 		{
@@ -667,6 +708,7 @@ func GetClusterPermissions() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"validatingwebhookconfigurations",
+				"mutatingwebhookconfigurations",
 			},
 			Verbs: []string{
 				"list",
@@ -682,6 +724,23 @@ func GetClusterPermissions() []rbacv1.PolicyRule {
 			Resources: []string{
 				"consoleclidownloads",
 				"consolequickstarts",
+				"consoleyamlsamples",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"create",
+				"delete",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"template.openshift.io",
+			},
+			Resources: []string{
+				"templates",
 			},
 			Verbs: []string{
 				"get",
@@ -1019,7 +1078,7 @@ func GetCSVBase(params *CSVBaseParams) *csvv1alpha1.ClusterServiceVersion {
 	// currently OLM is going to periodically kill HCO, due to that some request can got lost with a timeout error
 	// using a really high timeout can mitigate it giving more time to a new HCO instance.
 	// Please remove this once https://bugzilla.redhat.com/1868712 is not biting us anymore
-	var webhookTimeout int32 = 30
+	webhookTimeout := util.WebhookTimeoutSeconds
 
 	validatingWebhook := csvv1alpha1.WebhookDescription{
 		GenerateName:            util.HcoValidatingWebhook,
@@ -1047,6 +1106,34 @@ func GetCSVBase(params *CSVBaseParams) *csvv1alpha1.ClusterServiceVersion {
 		WebhookPath: &webhookPath,
 	}
 
+	hcMutatingWebhookPath := util.HCOMutatingWebhookPath
+
+	// sets the defaults of the HyperConverged CR
+	hcMutatingWebhook := csvv1alpha1.WebhookDescription{
+		GenerateName:            util.HcoMutatingWebhook,
+		Type:                    csvv1alpha1.MutatingAdmissionWebhook,
+		DeploymentName:          hcoWhDeploymentName,
+		ContainerPort:           util.WebhookPort,
+		AdmissionReviewVersions: []string{"v1beta1", "v1"},
+		SideEffects:             &sideEffect,
+		FailurePolicy:           &failurePolicy,
+		TimeoutSeconds:          &webhookTimeout,
+		Rules: []admissionregistrationv1.RuleWithOperations{
+			admissionregistrationv1.RuleWithOperations{
+				Operations: []admissionregistrationv1.OperationType{
+					admissionregistrationv1.Create,
+					admissionregistrationv1.Update,
+				},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{util.APIVersionGroup},
+					APIVersions: []string{util.APIVersionAlpha, util.APIVersionBeta, util.APIVersionV1},
+					Resources:   []string{"hyperconvergeds"},
+				},
+			},
+		},
+		WebhookPath: &hcMutatingWebhookPath,
+	}
+
 	mutatingWebhookSideEffects := admissionregistrationv1.SideEffectClassNoneOnDryRun
 	mutatingWebhookPath := util.HCONSWebhookPath
 
@@ -1077,6 +1164,60 @@ func GetCSVBase(params *CSVBaseParams) *csvv1alpha1.ClusterServiceVersion {
 		WebhookPath: &mutatingWebhookPath,
 	}
 
+	// The operand guard is optional, and it is enabled by an annotation of the HyperConverged CR. It must not block
+	// the operand CRs if the HCO webhook is not available.
+	operandWebhookFailurePolicy := admissionregistrationv1.Ignore
+	operandWebhookPath := util.HCOOperandWebhookPath
+	operandWebhookTimeout := util.OperandWebhookTimeoutSeconds
+
+	var operandRules []admissionregistrationv1.RuleWithOperations
+	for _, operand := range []struct{ group, resource string }{
+		{group: "kubevirt.io", resource: "kubevirts"},
+		{group: "cdi.kubevirt.io", resource: "cdis"},
+		{group: "networkaddonsoperator.network.kubevirt.io", resource: "networkaddonsconfigs"},
+		{group: "ssp.kubevirt.io", resource: "ssps"},
+		{group: "v2v.kubevirt.io", resource: "vmimportconfigs"},
+	} {
+		operandRules = append(operandRules, admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{
+				admissionregistrationv1.Update,
+			},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{operand.group},
+				APIVersions: []string{"*"},
+				Resources:   []string{operand.resource},
+			},
+		})
+	}
+
+	operandWebhook := csvv1alpha1.WebhookDescription{
+		GenerateName:            util.HcoOperandWebhook,
+		Type:                    csvv1alpha1.ValidatingAdmissionWebhook,
+		DeploymentName:          hcoWhDeploymentName,
+		ContainerPort:           util.WebhookPort,
+		AdmissionReviewVersions: []string{"v1beta1", "v1"},
+		SideEffects:             &sideEffect,
+		FailurePolicy:           &operandWebhookFailurePolicy,
+		TimeoutSeconds:          &operandWebhookTimeout,
+		Rules:                   operandRules,
+		WebhookPath:             &operandWebhookPath,
+	}
+
+	conversionWebhookPath := util.HCOConvertWebhookPath
+
+	conversionWebhook := csvv1alpha1.WebhookDescription{
+		GenerateName:            util.HcoConversionWebhook,
+		Type:                    csvv1alpha1.ConversionWebhook,
+		DeploymentName:          hcoWhDeploymentName,
+		ContainerPort:           util.WebhookPort,
+		AdmissionReviewVersions: []string{"v1beta1", "v1"},
+		SideEffects:             &sideEffect,
+		FailurePolicy:           &failurePolicy,
+		TimeoutSeconds:          &webhookTimeout,
+		ConversionCRDs:          []string{"hyperconvergeds.hco.kubevirt.io"},
+		WebhookPath:             &conversionWebhookPath,
+	}
+
 	return &csvv1alpha1.ClusterServiceVersion{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "operators.coreos.com/v1alpha1",
@@ -1164,7 +1305,7 @@ func GetCSVBase(params *CSVBaseParams) *csvv1alpha1.ClusterServiceVersion {
 			// Skip this in favor of having a separate function to get
 			// the actual StrategyDetailsDeployment when merging CSVs
 			InstallStrategy:    csvv1alpha1.NamedInstallStrategy{},
-			WebhookDefinitions: []csvv1alpha1.WebhookDescription{validatingWebhook, mutatingWebhook},
+			WebhookDefinitions: []csvv1alpha1.WebhookDescription{validatingWebhook, hcMutatingWebhook, mutatingWebhook, operandWebhook, conversionWebhook},
 			CustomResourceDefinitions: csvv1alpha1.CustomResourceDefinitions{
 				Owned: []csvv1alpha1.CRDDescription{
 					{
@@ -5,7 +5,56 @@ const (
 	ReconcileCompletedMessage = "Reconcile completed successfully"
 
 	// JSONPatch annotation names
-	JSONPatchKVAnnotationName   = "kubevirt.kubevirt.io/jsonpatch"
-	JSONPatchCDIAnnotationName  = "containerizeddataimporter.kubevirt.io/jsonpatch"
-	JSONPatchCNAOAnnotationName = "networkaddonsconfigs.kubevirt.io/jsonpatch"
+	JSONPatchKVAnnotationName            = "kubevirt.kubevirt.io/jsonpatch"
+	JSONPatchCDIAnnotationName           = "containerizeddataimporter.kubevirt.io/jsonpatch"
+	JSONPatchCNAOAnnotationName          = "networkaddonsconfigs.kubevirt.io/jsonpatch"
+	JSONPatchSSPAnnotationName           = "ssp.kubevirt.io/jsonpatch"
+	JSONPatchVMImportAnnotationName      = "vmimportconfigs.kubevirt.io/jsonpatch"
+	JSONPatchKVConfigAnnotationName      = "kubevirt-config.configmaps.kubevirt.io/jsonpatch"
+	JSONPatchStorageConfigAnnotationName = "kubevirt-storage-class-defaults.configmaps.kubevirt.io/jsonpatch"
+	JSONPatchIMSConfigAnnotationName     = "v2v-vmware.configmaps.kubevirt.io/jsonpatch"
+
+	// JSON merge patch annotation names
+	MergePatchKVAnnotationName            = "kubevirt.kubevirt.io/mergepatch"
+	MergePatchCDIAnnotationName           = "containerizeddataimporter.kubevirt.io/mergepatch"
+	MergePatchCNAOAnnotationName          = "networkaddonsconfigs.kubevirt.io/mergepatch"
+	MergePatchSSPAnnotationName           = "ssp.kubevirt.io/mergepatch"
+	MergePatchVMImportAnnotationName      = "vmimportconfigs.kubevirt.io/mergepatch"
+	MergePatchKVConfigAnnotationName      = "kubevirt-config.configmaps.kubevirt.io/mergepatch"
+	MergePatchStorageConfigAnnotationName = "kubevirt-storage-class-defaults.configmaps.kubevirt.io/mergepatch"
+	MergePatchIMSConfigAnnotationName     = "v2v-vmware.configmaps.kubevirt.io/mergepatch"
+
+	// Strategic merge patch annotation names
+	StrategicPatchKVAnnotationName            = "kubevirt.kubevirt.io/strategicpatch"
+	StrategicPatchCDIAnnotationName           = "containerizeddataimporter.kubevirt.io/strategicpatch"
+	StrategicPatchCNAOAnnotationName          = "networkaddonsconfigs.kubevirt.io/strategicpatch"
+	StrategicPatchSSPAnnotationName           = "ssp.kubevirt.io/strategicpatch"
+	StrategicPatchVMImportAnnotationName      = "vmimportconfigs.kubevirt.io/strategicpatch"
+	StrategicPatchKVConfigAnnotationName      = "kubevirt-config.configmaps.kubevirt.io/strategicpatch"
+	StrategicPatchStorageConfigAnnotationName = "kubevirt-storage-class-defaults.configmaps.kubevirt.io/strategicpatch"
+	StrategicPatchIMSConfigAnnotationName     = "v2v-vmware.configmaps.kubevirt.io/strategicpatch"
+
+	// OrphanSweeperDryRunAnnotationName is the HyperConverged annotation that makes the orphan sweeper only report the
+	// stale resources, instead of removing them
+	OrphanSweeperDryRunAnnotationName = "hco.kubevirt.io/orphanSweeperDryRun"
+
+	// PreviewAnnotationName is the HyperConverged annotation that holds a JSON merge patch of the HyperConverged CR.
+	// HCO reports the changes that the patched CR would make in the operand resources, without applying them
+	PreviewAnnotationName = "hco.kubevirt.io/preview"
+
+	// ForceUpgradeAnnotationName is the HyperConverged annotation that forces an upgrade, although blocking upgrade
+	// pre-flight checks failed, if its value is "true"
+	ForceUpgradeAnnotationName = "hco.kubevirt.io/forceUpgrade"
+
+	// OperandGuardAnnotationName is the HyperConverged annotation that makes the HCO webhook reject the spec changes
+	// of the operand CRs that were not made by HCO, if its value is "true"
+	OperandGuardAnnotationName = "hco.kubevirt.io/operandGuard"
+
+	// BreakGlassAnnotationName is the operand CR annotation that allows spec changes of the operand CR by any user,
+	// even if the operand guard is enabled, if its value is "true"
+	BreakGlassAnnotationName = "hco.kubevirt.io/breakGlass"
+
+	// PatchedKeysAnnotationName is the annotation of the patched ConfigMaps that lists the data keys that the patch
+	// annotations of the HyperConverged CR set or removed
+	PatchedKeysAnnotationName = "hco.kubevirt.io/patchedKeys"
 )
@@ -0,0 +1,67 @@
+package commonTestUtils
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// ClusterInfoMock is a ClusterInfo with a fixed set of capabilities, that can be changed by the tests
+type ClusterInfoMock struct {
+	capabilities map[hcoutil.Capability]bool
+	lock         *sync.RWMutex
+}
+
+// NewClusterInfoMock returns a ClusterInfo with the given capabilities
+func NewClusterInfoMock(capabilities ...hcoutil.Capability) *ClusterInfoMock {
+	ci := &ClusterInfoMock{
+		capabilities: make(map[hcoutil.Capability]bool),
+		lock:         &sync.RWMutex{},
+	}
+	ci.SetCapabilities(capabilities...)
+	return ci
+}
+
+// NewOpenShiftClusterInfoMock returns a ClusterInfo with all the capabilities
+func NewOpenShiftClusterInfoMock() *ClusterInfoMock {
+	return NewClusterInfoMock(hcoutil.Capabilities...)
+}
+
+// SetCapabilities replaces the capabilities of the mock
+func (ci *ClusterInfoMock) SetCapabilities(capabilities ...hcoutil.Capability) {
+	ci.lock.Lock()
+	defer ci.lock.Unlock()
+
+	ci.capabilities = make(map[hcoutil.Capability]bool)
+	for _, capability := range capabilities {
+		ci.capabilities[capability] = true
+	}
+}
+
+func (ClusterInfoMock) Init(_ context.Context, _ client.Reader, _ meta.RESTMapper, _ logr.Logger, _ bool) error {
+	return nil
+}
+
+func (ClusterInfoMock) RefreshCapabilities(_ context.Context, _ logr.Logger) (bool, error) {
+	return false, nil
+}
+
+func (ci *ClusterInfoMock) HasCapability(capability hcoutil.Capability) bool {
+	ci.lock.RLock()
+	defer ci.lock.RUnlock()
+
+	return ci.capabilities[capability]
+}
+
+func (ci *ClusterInfoMock) IsOpenshift() bool {
+	return ci.HasCapability(hcoutil.CapabilityClusterVersion)
+}
+
+func (ClusterInfoMock) IsRunningLocally() bool {
+	return false
+}
@@ -16,13 +16,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -148,5 +151,20 @@ func GetScheme() *runtime.Scheme {
 		Expect(f(testScheme)).To(BeNil())
 	}
 
+	// The NodeMaintenance API is not vendored. Register it as unstructured, so the fake client would be able to list it
+	nmListGVK := hcoutil.NodeMaintenanceGroupVersionKind.GroupVersion().WithKind(hcoutil.NodeMaintenanceGroupVersionKind.Kind + "List")
+	testScheme.AddKnownTypeWithName(hcoutil.NodeMaintenanceGroupVersionKind, &unstructured.Unstructured{})
+	testScheme.AddKnownTypeWithName(nmListGVK, &unstructured.UnstructuredList{})
+
+	// The same for the HostPathProvisioner API
+	hppGVK := schema.GroupVersionKind{Group: "hostpathprovisioner.kubevirt.io", Version: "v1beta1", Kind: "HostPathProvisioner"}
+	testScheme.AddKnownTypeWithName(hppGVK, &unstructured.Unstructured{})
+	testScheme.AddKnownTypeWithName(hppGVK.GroupVersion().WithKind(hppGVK.Kind+"List"), &unstructured.UnstructuredList{})
+
+	// The same for the OpenShift Template API, that is used by the extra manifests
+	templateGVK := schema.GroupVersionKind{Group: "template.openshift.io", Version: "v1", Kind: "Template"}
+	testScheme.AddKnownTypeWithName(templateGVK, &unstructured.Unstructured{})
+	testScheme.AddKnownTypeWithName(templateGVK.GroupVersion().WithKind(templateGVK.Kind+"List"), &unstructured.UnstructuredList{})
+
 	return testScheme
 }
@@ -0,0 +1,129 @@
+package hyperconverged
+
+import (
+	"fmt"
+	"sync"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// optionalWatch is a watch of secondary resources, whose CRD may be missing when HCO starts. The watch is started
+// once the CRD is established.
+type optionalWatch struct {
+	crdName string
+	objects []client.Object
+}
+
+var optionalWatches = []optionalWatch{
+	{crdName: operands.SspCrdName, objects: []client.Object{&sspv1beta1.SSP{}}},
+	{crdName: operands.VMImportConfigCrdName, objects: []client.Object{&vmimportv1beta1.VMImportConfig{}}},
+	// the metrics Service is deployed only with the ServiceMonitor
+	{crdName: operands.ServiceMonitorCrdName, objects: []client.Object{&corev1.Service{}, &monitoringv1.ServiceMonitor{}}},
+	{crdName: operands.PrometheusRuleCrdName, objects: []client.Object{&monitoringv1.PrometheusRule{}}},
+	{crdName: operands.HppCrdName, objects: []client.Object{operands.NewHostPathProvisionerWithNameOnly(&hcov1beta1.HyperConverged{})}},
+	// the NodeMaintenance CRs are read for the HyperConverged status
+	{crdName: operands.NodeMaintenanceCrdName, objects: []client.Object{hcoutil.NewNodeMaintenanceWithGVKOnly()}},
+}
+
+func isOptionalWatchCRD(name string) bool {
+	for _, w := range optionalWatches {
+		if w.crdName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// crdWatcher starts the optional watches when their CRDs are established, and reports the availability of the CRDs
+// to the operand handler, so the operands of missing CRDs are skipped instead of failing the reconciliation.
+//
+// A started watch can't be stopped; if its CRD is removed, the informer keeps retrying until the CRD is deployed
+// again.
+type crdWatcher struct {
+	controller controller.Controller
+	handler    func(resource client.Object) handler.EventHandler
+	lock       sync.Mutex
+	// the started watches, by the type of the watched object
+	started map[string]bool
+}
+
+func newCRDWatcher(c controller.Controller, handler func(resource client.Object) handler.EventHandler) *crdWatcher {
+	return &crdWatcher{
+		controller: c,
+		handler:    handler,
+		started:    make(map[string]bool),
+	}
+}
+
+// sync reads the optional CRDs, starts the watches of the newly established CRDs, and returns the availability of
+// each optional CRD.
+func (w *crdWatcher) sync(req *common.HcoRequest, cl client.Client) (map[string]bool, error) {
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := cl.List(req.Ctx, crds); err != nil {
+		return nil, err
+	}
+
+	established := make(map[string]bool, len(optionalWatches))
+	for _, crd := range crds.Items {
+		if isOptionalWatchCRD(crd.Name) && crd.DeletionTimestamp == nil && isCRDEstablished(&crd) {
+			established[crd.Name] = true
+		}
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	available := make(map[string]bool, len(optionalWatches))
+	for _, ow := range optionalWatches {
+		available[ow.crdName] = established[ow.crdName]
+		if !established[ow.crdName] {
+			continue
+		}
+
+		for _, obj := range ow.objects {
+			key := watchKey(obj)
+			if w.started[key] {
+				continue
+			}
+
+			req.Logger.Info("Starting a watch for an established CRD", "CRD", ow.crdName, "type", key)
+			if err := w.controller.Watch(&source.Kind{Type: obj}, w.handler(obj)); err != nil {
+				return nil, fmt.Errorf("failed to watch %s; %w", key, err)
+			}
+			w.started[key] = true
+		}
+	}
+
+	return available, nil
+}
+
+// watchKey identifies the type of a watched object. The unstructured objects are identified by their GVK.
+func watchKey(obj client.Object) string {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.GroupVersionKind().String()
+	}
+	return fmt.Sprintf("%T", obj)
+}
+
+func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == apiextensionsv1.Established {
+			return cond.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
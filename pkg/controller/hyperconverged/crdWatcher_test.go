@@ -0,0 +1,170 @@
+package hyperconverged
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+)
+
+// fakeController records the started watches
+type fakeController struct {
+	watched  []string
+	watchErr error
+}
+
+func (c *fakeController) Reconcile(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (c *fakeController) Watch(src source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error {
+	if c.watchErr != nil {
+		return c.watchErr
+	}
+	c.watched = append(c.watched, watchKey(src.(*source.Kind).Type))
+	return nil
+}
+
+func (c *fakeController) Start(_ context.Context) error {
+	return nil
+}
+
+func (c *fakeController) GetLogger() logr.Logger {
+	return log
+}
+
+var _ = Describe("CRD watcher", func() {
+	newCRD := func(name string, established bool) *apiextensionsv1.CustomResourceDefinition {
+		status := apiextensionsv1.ConditionFalse
+		if established {
+			status = apiextensionsv1.ConditionTrue
+		}
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+					{Type: apiextensionsv1.Established, Status: status},
+				},
+			},
+		}
+	}
+
+	var (
+		c       *fakeController
+		watcher *crdWatcher
+	)
+
+	BeforeEach(func() {
+		c = &fakeController{}
+		watcher = newCRDWatcher(c, func(_ client.Object) handler.EventHandler {
+			return &handler.EnqueueRequestForObject{}
+		})
+	})
+
+	It("should start the watches of the established CRDs only", func() {
+		hco := commonTestUtils.NewHco()
+		cl := commonTestUtils.InitClient([]runtime.Object{
+			hco,
+			newCRD(operands.SspCrdName, true),
+			newCRD(operands.ServiceMonitorCrdName, false),
+		})
+
+		available, err := watcher.sync(commonTestUtils.NewReq(hco), cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(available).To(Equal(map[string]bool{
+			operands.SspCrdName:             true,
+			operands.VMImportConfigCrdName:  false,
+			operands.ServiceMonitorCrdName:  false,
+			operands.PrometheusRuleCrdName:  false,
+			operands.HppCrdName:             false,
+			operands.NodeMaintenanceCrdName: false,
+		}))
+		Expect(c.watched).To(Equal([]string{"*v1beta1.SSP"}))
+	})
+
+	It("should start the watches when the CRDs become established, only once", func() {
+		hco := commonTestUtils.NewHco()
+		smCRD := newCRD(operands.ServiceMonitorCrdName, false)
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, smCRD})
+		req := commonTestUtils.NewReq(hco)
+
+		_, err := watcher.sync(req, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.watched).To(BeEmpty())
+
+		smCRD.Status.Conditions[0].Status = apiextensionsv1.ConditionTrue
+		Expect(cl.Update(context.TODO(), smCRD)).To(Succeed())
+
+		available, err := watcher.sync(req, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(available[operands.ServiceMonitorCrdName]).To(BeTrue())
+		Expect(c.watched).To(Equal([]string{"*v1.Service", "*v1.ServiceMonitor"}))
+
+		_, err = watcher.sync(req, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.watched).To(HaveLen(2))
+	})
+
+	It("should report a removed CRD as unavailable", func() {
+		hco := commonTestUtils.NewHco()
+		sspCRD := newCRD(operands.SspCrdName, true)
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, sspCRD})
+		req := commonTestUtils.NewReq(hco)
+
+		_, err := watcher.sync(req, cl)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cl.Delete(context.TODO(), sspCRD)).To(Succeed())
+
+		available, err := watcher.sync(req, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(available[operands.SspCrdName]).To(BeFalse())
+		Expect(c.watched).To(Equal([]string{"*v1beta1.SSP"}))
+	})
+
+	It("should start the watches of the HostPathProvisioner and the NodeMaintenance CRs", func() {
+		hco := commonTestUtils.NewHco()
+		cl := commonTestUtils.InitClient([]runtime.Object{
+			hco,
+			newCRD(operands.HppCrdName, true),
+			newCRD(operands.NodeMaintenanceCrdName, true),
+		})
+
+		available, err := watcher.sync(commonTestUtils.NewReq(hco), cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(available[operands.HppCrdName]).To(BeTrue())
+		Expect(available[operands.NodeMaintenanceCrdName]).To(BeTrue())
+		Expect(c.watched).To(Equal([]string{
+			"hostpathprovisioner.kubevirt.io/v1beta1, Kind=HostPathProvisioner",
+			"nodemaintenance.kubevirt.io/v1beta1, Kind=NodeMaintenance",
+		}))
+	})
+
+	It("should retry a watch that failed to start", func() {
+		hco := commonTestUtils.NewHco()
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, newCRD(operands.VMImportConfigCrdName, true)})
+		req := commonTestUtils.NewReq(hco)
+
+		c.watchErr = fmt.Errorf("fake error")
+		_, err := watcher.sync(req, cl)
+		Expect(err).To(HaveOccurred())
+
+		c.watchErr = nil
+		_, err = watcher.sync(req, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.watched).To(Equal([]string{"*v1beta1.VMImportConfig"}))
+	})
+})
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
@@ -11,12 +14,14 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -24,17 +29,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	networkaddonsv1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/migrations"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/preflight"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	version "github.com/kubevirt/hyperconverged-cluster-operator/version"
-	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
-	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 )
 
 var (
@@ -50,28 +55,32 @@ const (
 
 	// OpenshiftNamespace is for resources that belong in the openshift namespace
 
-	reconcileInit               = "Init"
-	reconcileInitMessage        = "Initializing HyperConverged cluster"
-	reconcileCompleted          = "ReconcileCompleted"
-	reconcileCompletedMessage   = "Reconcile completed successfully"
-	invalidRequestReason        = "InvalidRequest"
-	invalidRequestMessageFormat = "Request does not match expected name (%v) and namespace (%v)"
-	commonDegradedReason        = "HCODegraded"
-	commonProgressingReason     = "HCOProgressing"
-	taintedConfigurationReason  = "UnsupportedFeatureAnnotation"
-	taintedConfigurationMessage = "Unsupported feature was activated via an HCO annotation"
-
-	hcoVersionName    = "operator"
+	reconcileInit                = "Init"
+	reconcileInitMessage         = "Initializing HyperConverged cluster"
+	reconcileCompleted           = "ReconcileCompleted"
+	reconcileCompletedMessage    = "Reconcile completed successfully"
+	invalidRequestReason         = "InvalidRequest"
+	invalidRequestMessageFormat  = "Request does not match expected name (%v) and namespace (%v)"
+	commonDegradedReason         = "HCODegraded"
+	commonProgressingReason      = "HCOProgressing"
+	taintedConfigurationReason   = "UnsupportedFeatureAnnotation"
+	taintedConfigurationMessage  = "Unsupported feature was activated via an HCO annotation"
+	heterogeneousCPUModelsReason = "HeterogeneousCPUModels"
+	placementUnsatisfiableReason = "PlacementUnsatisfiable"
+	placementSatisfiableReason   = "PlacementSatisfiable"
+	migrationFailedReason        = "MigrationFailed"
+	preflightCheckFailedReason   = "UpgradePreflightCheckFailed"
+
+	// the interval of running the upgrade pre-flight checks again, while a failed blocking check prevents the upgrade
+	preflightRetryInterval = 2 * time.Minute
+
+	hcoVersionName    = hcoutil.HcoVersionName
 	secondaryCRPrefix = "hco-controlled-cr-"
 )
 
-// JSONPatchAnnotationNames - annotations used to patch operand CRs with unsupported/unofficial/hidden features.
+// PatchAnnotationNames - annotations used to patch operand resources with unsupported/unofficial/hidden features.
 // The presence of any of these annotations raises the hcov1beta1.ConditionTaintedConfiguration condition.
-var JSONPatchAnnotationNames = []string{
-	common.JSONPatchKVAnnotationName,
-	common.JSONPatchCDIAnnotationName,
-	common.JSONPatchCNAOAnnotationName,
-}
+var PatchAnnotationNames = operands.GetPatchAnnotationNames()
 
 // RegisterReconciler creates a new HyperConverged Reconciler and registers it into manager.
 func RegisterReconciler(mgr manager.Manager, ci hcoutil.ClusterInfo) error {
@@ -88,10 +97,13 @@ func newReconciler(mgr manager.Manager, ci hcoutil.ClusterInfo) reconcile.Reconc
 
 	return &ReconcileHyperConverged{
 		client:             mgr.GetClient(),
+		apiReader:          mgr.GetAPIReader(),
 		scheme:             mgr.GetScheme(),
 		recorder:           mgr.GetEventRecorderFor(hcoutil.HyperConvergedName),
 		cliDownloadHandler: &operands.CLIDownloadHandler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()},
-		operandHandler:     operands.NewOperandHandler(mgr.GetClient(), mgr.GetScheme(), ci.IsOpenshift(), hcoutil.GetEventEmitter()),
+		operandHandler:     operands.NewOperandHandler(mgr.GetClient(), mgr.GetScheme(), ci, hcoutil.GetEventEmitter()),
+		migrations:         migrations.GetRegistry(),
+		preflightChecks:    preflight.GetRegistry(),
 		upgradeMode:        false,
 		ownVersion:         ownVersion,
 		eventEmitter:       hcoutil.GetEventEmitter(),
@@ -125,33 +137,103 @@ func add(mgr manager.Manager, r reconcile.Reconciler, ci hcoutil.ClusterInfo) er
 		&kubevirtv1.KubeVirt{},
 		&cdiv1beta1.CDI{},
 		&networkaddonsv1.NetworkAddonsConfig{},
-		&sspv1beta1.SSP{},
 		&schedulingv1.PriorityClass{},
-		&vmimportv1beta1.VMImportConfig{},
 	}
-	if ci.IsOpenshift() {
-		secondaryResources = append(secondaryResources, []client.Object{
-			&corev1.Service{},
-			&monitoringv1.ServiceMonitor{},
-			&monitoringv1.PrometheusRule{},
-		}...)
+
+	secondaryResourceHandler := func(resource client.Object) handler.EventHandler {
+		msg := fmt.Sprintf("Reconciling for %T", resource)
+		return handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+			// enqueue using a placeholder to be able to discriminate request triggered
+			// by changes on the HyperConverged object from request triggered by changes
+			// on a secondary CR controlled by HCO
+			log.Info(msg)
+			return []reconcile.Request{
+				{NamespacedName: secCRPlaceholder},
+			}
+		})
+	}
+
+	// The watches of the optional CRDs are started by the reconciler, once the CRDs are established
+	if hcoReconciler, ok := r.(*ReconcileHyperConverged); ok {
+		hcoReconciler.crdWatcher = newCRDWatcher(c, secondaryResourceHandler)
+	}
+
+	// Watch the user supplied extra manifests ConfigMap; all the other ConfigMaps are ignored
+	err = c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+			log.Info("Reconciling for the extra manifests ConfigMap")
+			return []reconcile.Request{
+				{NamespacedName: secCRPlaceholder},
+			}
+		}),
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == operands.ExtraManifestsConfigMapName
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch the labels, the taints and the schedulability of the nodes, for the ConditionHeterogeneousCPUModels and the
+	// ConditionPlacementUnsatisfiable conditions; the other changes of the nodes, e.g. of their status, are ignored
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Node{}},
+		handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+			log.Info("Reconciling for a change of a node", "node", a.GetName())
+			return []reconcile.Request{
+				{NamespacedName: secCRPlaceholder},
+			}
+		}),
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldNode, oldOk := e.ObjectOld.(*corev1.Node)
+				newNode, newOk := e.ObjectNew.(*corev1.Node)
+				return oldOk && newOk && (!reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+					!reflect.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) ||
+					oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable)
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Detect the cluster capabilities again when the CRDs of their APIs are added or removed, and reconcile when the
+	// optional CRDs are changed. The operands that require a capability or an optional CRD are deployed or skipped
+	// accordingly, in the next reconciliation.
+	err = c.Watch(
+		&source.Kind{Type: &apiextensionsv1.CustomResourceDefinition{}},
+		handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+			crd := a.(*apiextensionsv1.CustomResourceDefinition)
+			changed := false
+			if hcoutil.IsCapabilityGroup(crd.Spec.Group) {
+				var err error
+				if changed, err = ci.RefreshCapabilities(context.Background(), log); err != nil {
+					log.Error(err, "failed to refresh the cluster capabilities")
+				}
+			}
+
+			if !changed && !isOptionalWatchCRD(crd.Name) {
+				return nil
+			}
+			log.Info("Reconciling for a change of a CRD", "CRD", crd.Name)
+			return []reconcile.Request{
+				{NamespacedName: secCRPlaceholder},
+			}
+		}),
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			return ok && (hcoutil.IsCapabilityGroup(crd.Spec.Group) || isOptionalWatchCRD(crd.Name))
+		}),
+	)
+	if err != nil {
+		return err
 	}
 
 	// Watch secondary resources
 	for _, resource := range secondaryResources {
-		msg := fmt.Sprintf("Reconciling for %T", resource)
-		err = c.Watch(
-			&source.Kind{Type: resource},
-			handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
-				// enqueue using a placeholder to be able to discriminate request triggered
-				// by changes on the HyperConverged object from request triggered by changes
-				// on a secondary CR controlled by HCO
-				log.Info(msg)
-				return []reconcile.Request{
-					{NamespacedName: secCRPlaceholder},
-				}
-			}),
-		)
+		err = c.Watch(&source.Kind{Type: resource}, secondaryResourceHandler(resource))
 		if err != nil {
 			return err
 		}
@@ -166,15 +248,20 @@ var _ reconcile.Reconciler = &ReconcileHyperConverged{}
 type ReconcileHyperConverged struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client             client.Client
+	client client.Client
+	// apiReader reads objects directly from the apiserver, from all the namespaces
+	apiReader          client.Reader
 	scheme             *runtime.Scheme
 	recorder           record.EventRecorder
 	cliDownloadHandler *operands.CLIDownloadHandler
 	operandHandler     *operands.OperandHandler
+	migrations         *migrations.Registry
+	preflightChecks    *preflight.Registry
 	upgradeMode        bool
 	ownVersion         string
 	eventEmitter       hcoutil.EventEmitter
 	firstLoop          bool
+	crdWatcher         *crdWatcher
 }
 
 // Reconcile reads that state of the cluster for a HyperConverged object and makes changes based on the state read
@@ -214,6 +301,8 @@ func (r *ReconcileHyperConverged) Reconcile(ctx context.Context, request reconci
 		r.firstLoopInitialization(hcoRequest)
 	}
 
+	r.syncOptionalCRDs(hcoRequest)
+
 	result, err := r.doReconcile(hcoRequest)
 	if err != nil {
 		r.eventEmitter.EmitEvent(hcoRequest.Instance, corev1.EventTypeWarning, "ReconcileError", err.Error())
@@ -303,14 +392,43 @@ func (r *ReconcileHyperConverged) doReconcile(req *common.HcoRequest) (reconcile
 	// an old version, since Status.Versions will be empty.
 	knownHcoVersion, _ := req.Instance.Status.GetVersion(hcoVersionName)
 
+	upgradeBlocked := false
 	if !r.upgradeMode && !init && knownHcoVersion != r.ownVersion {
-		r.upgradeMode = true
-		r.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeNormal, "UpgradeHCO", "Upgrading the HyperConverged to version "+r.ownVersion)
-		req.Logger.Info(fmt.Sprintf("Start upgrading from version %s to version %s", knownHcoVersion, r.ownVersion))
+		upgradeBlocked = r.isUpgradeBlocked(req)
+		if !upgradeBlocked {
+			r.upgradeMode = true
+			r.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeNormal, "UpgradeHCO", "Upgrading the HyperConverged to version "+r.ownVersion)
+			req.Logger.Info(fmt.Sprintf("Start upgrading from version %s to version %s", knownHcoVersion, r.ownVersion))
+		}
 	}
 
 	req.SetUpgradeMode(r.upgradeMode)
 
+	if err = r.runMigrations(req, init || upgradeBlocked, knownHcoVersion); err != nil {
+		req.Logger.Error(err, "failed to run the upgrade migrations")
+		req.Conditions.SetStatusCondition(conditionsv1.Condition{
+			Type:    hcov1beta1.ConditionReconcileComplete,
+			Status:  corev1.ConditionFalse,
+			Reason:  migrationFailedReason,
+			Message: fmt.Sprintf("Error while running the upgrade migrations: %v", err),
+		})
+		r.updateConditions(req)
+		hcoutil.SetReady(false)
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	// The CR may have been created before some of the defaults were added, or before the mutating webhook was deployed
+	if req.Instance.SetDefaults() {
+		req.Logger.Info("Setting the defaults of the HyperConverged CR")
+		req.Dirty = true
+	}
+
+	r.updateNodesUnderMaintenance(req)
+
+	r.detectHeterogeneousCPUModels(req)
+
+	r.detectUnsatisfiablePlacement(req)
+
 	r.cliDownloadHandler.Ensure(req)
 
 	err = r.operandHandler.Ensure(req)
@@ -330,9 +448,180 @@ func (r *ReconcileHyperConverged) doReconcile(req *common.HcoRequest) (reconcile
 
 	r.completeReconciliation(req)
 
+	if upgradeBlocked {
+		// run the pre-flight checks again, until the issues are fixed
+		return reconcile.Result{RequeueAfter: preflightRetryInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
+// runMigrations runs the pending upgrade migrations while upgrading, and the pending AfterUpgrade migrations once the
+// upgrade is completed. The AfterUpgrade migrations are skipped on the first deployment, and while a pending upgrade is
+// blocked by the pre-flight checks.
+func (r *ReconcileHyperConverged) runMigrations(req *common.HcoRequest, skipAfterUpgrade bool, fromVersion string) error {
+	if r.upgradeMode {
+		return r.migrations.Run(req, r.client, fromVersion)
+	}
+
+	if skipAfterUpgrade {
+		return nil
+	}
+
+	return r.migrations.RunAfterUpgrade(req, r.client)
+}
+
+// isUpgradeBlocked runs the upgrade pre-flight checks, and returns true if a failed blocking check prevents the
+// upgrade. HCO keeps reconciling the operands, but it does not complete the upgrade, nor runs the upgrade migrations,
+// until the issues are fixed, or until the upgrade is forced by the common.ForceUpgradeAnnotationName annotation.
+func (r *ReconcileHyperConverged) isUpgradeBlocked(req *common.HcoRequest) bool {
+	failed := r.preflightChecks.Run(req, r.apiReader)
+	if len(failed) == 0 {
+		return false
+	}
+
+	msg := fmt.Sprintf("Blocking upgrade pre-flight checks failed: %s", strings.Join(failed, ", "))
+	if req.Instance.Annotations[common.ForceUpgradeAnnotationName] == "true" {
+		req.Logger.Info("The upgrade is forced by the "+common.ForceUpgradeAnnotationName+" annotation", "failedChecks", failed)
+		r.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, "UpgradeForced", msg+"; the upgrade is forced by an annotation")
+		return false
+	}
+
+	req.Logger.Info("The upgrade is blocked by failed pre-flight checks", "failedChecks", failed)
+	r.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, "UpgradeBlocked", msg)
+	req.Conditions.SetStatusCondition(conditionsv1.Condition{
+		Type:    conditionsv1.ConditionUpgradeable,
+		Status:  corev1.ConditionFalse,
+		Reason:  preflightCheckFailedReason,
+		Message: msg + "; see status.upgradePreflightChecks",
+	})
+
+	return true
+}
+
+// updateNodesUnderMaintenance reports the nodes that are under maintenance by the node maintenance operator in the
+// HyperConverged status. A failure to read the NodeMaintenance CRs does not fail the reconciliation; the previous
+// status is kept until the next reconciliation.
+func (r *ReconcileHyperConverged) updateNodesUnderMaintenance(req *common.HcoRequest) {
+	nms, err := hcoutil.GetNodeMaintenances(req.Ctx, r.client)
+	if err != nil {
+		req.Logger.Error(err, "failed to read the NodeMaintenance CRs")
+		return
+	}
+
+	var nodes []hcov1beta1.NodeMaintenanceStatus
+	for _, nm := range nms {
+		nodes = append(nodes, hcov1beta1.NodeMaintenanceStatus{
+			NodeName:    nm.NodeName,
+			Reason:      nm.Reason,
+			Phase:       nm.Phase,
+			PendingPods: len(nm.PendingPods),
+			PendingVMs:  nm.PendingVMs(),
+		})
+	}
+
+	if !reflect.DeepEqual(req.Instance.Status.NodesUnderMaintenance, nodes) {
+		req.Instance.Status.NodesUnderMaintenance = nodes
+		req.StatusDirty = true
+	}
+}
+
+// detectHeterogeneousCPUModels raises the ConditionHeterogeneousCPUModels condition if the withHostPassthroughCPU
+// feature gate is enabled, but the workload nodes support different CPU models. Like the TaintedConfiguration
+// condition, it is removed instead of being set to False. A failure to read the nodes does not fail the reconciliation.
+func (r *ReconcileHyperConverged) detectHeterogeneousCPUModels(req *common.HcoRequest) {
+	var models map[string][]string
+	if req.Instance.Spec.FeatureGates.IsWithHostPassthroughCPUEnabled() {
+		nodes, err := hcoutil.GetPlacementNodes(req.Ctx, r.client, req.Instance.Spec.Workloads.NodePlacement)
+		if err != nil {
+			req.Logger.Error(err, "failed to read the workload nodes")
+			return
+		}
+		models = hcoutil.GetDistinctCPUModels(nodes)
+	}
+
+	existing := conditionsv1.FindStatusCondition(req.Instance.Status.Conditions, hcov1beta1.ConditionHeterogeneousCPUModels)
+
+	if len(models) <= 1 {
+		if existing != nil {
+			conditionsv1.RemoveStatusCondition(&req.Instance.Status.Conditions, hcov1beta1.ConditionHeterogeneousCPUModels)
+			req.Logger.Info("The workload nodes support the same CPU models")
+			req.StatusDirty = true
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("The %s feature gate is enabled, but the workload nodes support %d distinct sets of CPU models: %s",
+		hcov1beta1.WithHostPassthroughCPUGateName, len(models), hcoutil.FormatDistinctCPUModels(models))
+	if existing != nil && existing.Message == msg {
+		return
+	}
+
+	req.Logger.Info("Detected workload nodes with heterogeneous CPU models", "CPUModels", models)
+	r.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, heterogeneousCPUModelsReason, msg)
+	conditionsv1.SetStatusCondition(&req.Instance.Status.Conditions, conditionsv1.Condition{
+		Type:    hcov1beta1.ConditionHeterogeneousCPUModels,
+		Status:  corev1.ConditionTrue,
+		Reason:  heterogeneousCPUModelsReason,
+		Message: msg,
+	})
+	req.StatusDirty = true
+}
+
+// detectUnsatisfiablePlacement sets the ConditionPlacementUnsatisfiable condition, with the number of the eligible nodes
+// of the infra and of the workloads node placements. Only a placement that is set can be unsatisfiable; a placement
+// that is not set is the default, that is not restricted by HCO. A failure to read the nodes does not fail the
+// reconciliation; the previous condition is kept until the next reconciliation.
+func (r *ReconcileHyperConverged) detectUnsatisfiablePlacement(req *common.HcoRequest) {
+	var (
+		counts        []string
+		unsatisfiable []string
+	)
+	for _, placement := range []struct {
+		field     string
+		placement *sdkapi.NodePlacement
+	}{
+		{field: "spec.infra.nodePlacement", placement: req.Instance.Spec.Infra.NodePlacement},
+		{field: "spec.workloads.nodePlacement", placement: req.Instance.Spec.Workloads.NodePlacement},
+	} {
+		nodes, err := hcoutil.GetEligibleNodes(req.Ctx, r.client, placement.placement)
+		if err != nil {
+			req.Logger.Error(err, "failed to read the eligible nodes", "placement", placement.field)
+			return
+		}
+
+		counts = append(counts, fmt.Sprintf("%s matches %d eligible nodes", placement.field, len(nodes)))
+		if placement.placement != nil && len(nodes) == 0 {
+			unsatisfiable = append(unsatisfiable, placement.field)
+		}
+	}
+
+	cond := conditionsv1.Condition{
+		Type:    hcov1beta1.ConditionPlacementUnsatisfiable,
+		Status:  corev1.ConditionFalse,
+		Reason:  placementSatisfiableReason,
+		Message: strings.Join(counts, "; "),
+	}
+	if len(unsatisfiable) > 0 {
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = placementUnsatisfiableReason
+		cond.Message = fmt.Sprintf("%s do not match any eligible node; %s", strings.Join(unsatisfiable, " and "), cond.Message)
+	}
+
+	existing := conditionsv1.FindStatusCondition(req.Instance.Status.Conditions, hcov1beta1.ConditionPlacementUnsatisfiable)
+	if existing != nil && existing.Status == cond.Status && existing.Reason == cond.Reason && existing.Message == cond.Message {
+		return
+	}
+
+	if cond.Status == corev1.ConditionTrue && (existing == nil || existing.Status != corev1.ConditionTrue) {
+		req.Logger.Info("Detected an unsatisfiable node placement", "placements", unsatisfiable)
+		r.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, placementUnsatisfiableReason, cond.Message)
+	}
+
+	conditionsv1.SetStatusCondition(&req.Instance.Status.Conditions, cond)
+	req.StatusDirty = true
+}
+
 // getHyperConverged gets the HyperConverged resource from the Kubernetes API.
 func (r *ReconcileHyperConverged) getHyperConverged(req *common.HcoRequest) (*hcov1beta1.HyperConverged, error) {
 	instance := &hcov1beta1.HyperConverged{}
@@ -364,13 +653,21 @@ func (r *ReconcileHyperConverged) updateHyperConverged(request *common.HcoReques
 	// we need to update the status and the metadata separately.
 	// Moreover, we need to update the status first, in order to prevent a conflict.
 
+	// The status update overrides the spec and the metadata with the stored ones; keep their changes.
+	spec := request.Instance.Spec.DeepCopy()
+	meta := request.Instance.ObjectMeta.DeepCopy()
+
 	err := r.updateHyperConvergedStatus(request)
 	if err != nil {
 		r.logHyperConvergedUpdateError(request, err, "Failed to update HCO Status")
 		return err
 	}
 
-	// Doing it here because status.update overrides spec for some reason
+	request.Instance.Spec = *spec
+	request.Instance.Labels = meta.Labels
+	request.Instance.Annotations = meta.Annotations
+	request.Instance.Finalizers = meta.Finalizers
+
 	r.recoverHCOVersion(request)
 
 	err = r.updateHyperConvergedSpecMetadata(request)
@@ -708,6 +1005,9 @@ func (r *ReconcileHyperConverged) completeReconciliation(req *common.HcoRequest)
 			req.Instance.Spec.Version = r.ownVersion
 			req.Dirty = true
 
+			// the migrations run again in the next upgrade, if they apply to it
+			req.Instance.Status.CompletedMigrations = nil
+
 			r.upgradeMode = false
 			req.ComponentUpgradeInProgress = false
 			req.Logger.Info(fmt.Sprintf("Successfuly upgraded to version %s", r.ownVersion))
@@ -781,27 +1081,25 @@ func (r *ReconcileHyperConverged) detectTaintedConfiguration(req *common.HcoRequ
 		hcov1beta1.ConditionTaintedConfiguration)
 
 	// A tainted configuration state is indicated by the
-	// presence of at least one of the JSON Patch annotations
-	tainted := false
-	for _, jpa := range JSONPatchAnnotationNames {
-		_, exists := req.Instance.ObjectMeta.Annotations[jpa]
-		if exists {
-			tainted = true
-			break
+	// presence of at least one of the patch annotations
+	var active []string
+	for _, pa := range PatchAnnotationNames {
+		if _, exists := req.Instance.ObjectMeta.Annotations[pa]; exists {
+			active = append(active, pa)
 		}
 	}
 
-	if tainted {
+	if len(active) > 0 {
 		conditionsv1.SetStatusCondition(&req.Instance.Status.Conditions, conditionsv1.Condition{
 			Type:    hcov1beta1.ConditionTaintedConfiguration,
 			Status:  corev1.ConditionTrue,
 			Reason:  taintedConfigurationReason,
-			Message: taintedConfigurationMessage,
+			Message: fmt.Sprintf("%s: %s", taintedConfigurationMessage, strings.Join(active, ", ")),
 		})
 
 		if !conditionExists {
 			// Only log at "first occurrence" of detection
-			req.Logger.Info("Detected tainted configuration state for HCO")
+			req.Logger.Info("Detected tainted configuration state for HCO", "annotations", active)
 			req.StatusDirty = true
 		}
 	} else { // !tainted
@@ -823,12 +1121,28 @@ func (r *ReconcileHyperConverged) firstLoopInitialization(request *common.HcoReq
 	r.eventEmitter.UpdateClient(request.Ctx, r.client, request.Logger)
 
 	// Initialize operand handler.
-	r.operandHandler.FirstUseInitiation(r.scheme, hcoutil.GetClusterInfo().IsOpenshift(), request.Instance)
+	r.operandHandler.FirstUseInitiation(request.Instance)
 
 	// Avoid re-initializing.
 	r.firstLoop = false
 }
 
+// syncOptionalCRDs starts the watches of the newly established optional CRDs, and skips the operands of the missing
+// ones.
+func (r *ReconcileHyperConverged) syncOptionalCRDs(req *common.HcoRequest) {
+	if r.crdWatcher == nil {
+		return
+	}
+
+	available, err := r.crdWatcher.sync(req, r.client)
+	if err != nil {
+		req.Logger.Error(err, "failed to watch the optional CRDs")
+		return
+	}
+
+	r.operandHandler.SetCRDsAvailability(available)
+}
+
 // recoverHCOVersion recovers Spec.Version if upgrade missed when upgrade completed
 func (r *ReconcileHyperConverged) recoverHCOVersion(request *common.HcoRequest) {
 	knownHcoVersion, versionFound := request.Instance.Status.GetVersion(hcoVersionName)
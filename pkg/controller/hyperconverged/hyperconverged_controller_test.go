@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
@@ -38,6 +39,7 @@ import (
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 )
@@ -173,10 +175,12 @@ var _ = Describe("HyperconvergedController", func() {
 				Expect(kvList.Items).Should(HaveLen(1))
 				kv := kvList.Items[0]
 				Expect(kv.Spec.Configuration.DeveloperConfiguration).ToNot(BeNil())
-				Expect(kv.Spec.Configuration.DeveloperConfiguration.FeatureGates).To(HaveLen(10))
+				Expect(kv.Spec.Configuration.DeveloperConfiguration.FeatureGates).To(HaveLen(12))
 
 				Expect(kv.Spec.Configuration.DeveloperConfiguration.FeatureGates).To(ContainElements("DataVolumes", "SRIOV", "LiveMigration", "CPUManager", "CPUNodeDiscovery", "Sidecar", "Snapshot"))
 				Expect(kv.Spec.Configuration.DeveloperConfiguration.FeatureGates).To(ContainElements("SRIOVLiveMigration", "HotplugVolumes", "HostDevices"))
+				// the missing feature gates are set to their defaults
+				Expect(kv.Spec.Configuration.DeveloperConfiguration.FeatureGates).To(ContainElements("WithHostModelCPU", "HypervStrictCheck"))
 			})
 
 			It("should find all managed resources", func() {
@@ -216,7 +220,8 @@ var _ = Describe("HyperconvergedController", func() {
 				expectedCNA, err := operands.NewNetworkAddons(hco)
 				Expect(err).ToNot(HaveOccurred())
 				expectedCNA.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/cnas/%s", expectedCNA.Namespace, expectedCNA.Name)
-				expectedSSP := operands.NewSSP(hco)
+				expectedSSP, err := operands.NewSSP(hco)
+				Expect(err).ToNot(HaveOccurred())
 				expectedSSP.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/ctbs/%s", expectedSSP.Namespace, expectedSSP.Name)
 				// Add all of the objects to the client
 				cl := commonTestUtils.InitClient([]runtime.Object{hco, expectedKVConfig, expectedKVStorageConfig, expectedKVStorageRole, expectedKVStorageRoleBinding, expectedKV, expectedCDI, expectedCNA, expectedSSP})
@@ -299,7 +304,8 @@ var _ = Describe("HyperconvergedController", func() {
 				expectedCNA, err := operands.NewNetworkAddons(hco)
 				Expect(err).ToNot(HaveOccurred())
 				expectedCNA.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/cnas/%s", expectedCNA.Namespace, expectedCNA.Name)
-				expectedSSP := operands.NewSSP(hco)
+				expectedSSP, err := operands.NewSSP(hco)
+				Expect(err).ToNot(HaveOccurred())
 				expectedSSP.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/ssps/%s", expectedSSP.Namespace, expectedSSP.Name)
 				// Add all of the objects to the client
 				cl := commonTestUtils.InitClient([]runtime.Object{hco, expectedKVConfig, expectedKVStorageConfig, expectedKVStorageRole, expectedKVStorageRoleBinding, expectedKV, expectedCDI, expectedCNA, expectedSSP})
@@ -418,7 +424,8 @@ var _ = Describe("HyperconvergedController", func() {
 						Status: corev1.ConditionFalse,
 					},
 				}
-				expectedSSP := operands.NewSSP(hco)
+				expectedSSP, err := operands.NewSSP(hco)
+				Expect(err).ToNot(HaveOccurred())
 				expectedSSP.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/ctbs/%s", expectedSSP.Namespace, expectedSSP.Name)
 				expectedSSP.Status.Conditions = getGenericCompletedConditions()
 				// Add all of the objects to the client
@@ -945,6 +952,38 @@ var _ = Describe("HyperconvergedController", func() {
 				Expect(cond.Status).Should(BeEquivalentTo("False"))
 			})
 
+			It("should set the missing defaults during upgrade", func() {
+				expected.hco.Status.UpdateVersion(hcoVersionName, oldVersion)
+				expected.hco.Spec.Version = oldVersion
+				origFeatureGates := expected.hco.Spec.FeatureGates
+				// created before the feature gates defaults were added
+				expected.hco.Spec.FeatureGates = nil
+				defer func() {
+					expected.hco.Spec.FeatureGates = origFeatureGates
+				}()
+
+				cl := expected.initClient()
+				foundResource, _ := doReconcile(cl, expected.hco)
+
+				Expect(foundResource.Spec.FeatureGates).ToNot(BeNil())
+				Expect(foundResource.Spec.FeatureGates.WithHostModelCPU).ToNot(BeNil())
+				Expect(*foundResource.Spec.FeatureGates.WithHostModelCPU).To(BeTrue())
+				Expect(foundResource.Spec.FeatureGates.HotplugVolumes).ToNot(BeNil())
+				Expect(*foundResource.Spec.FeatureGates.HotplugVolumes).To(BeFalse())
+			})
+
+			It("should set the missing defaults when not upgrading", func() {
+				exp := getBasicDeployment()
+				// e.g. created while the mutating webhook was not available
+				exp.hco.Spec.FeatureGates = nil
+
+				foundResource, _ := doReconcile(exp.initClient(), exp.hco)
+
+				Expect(foundResource.Spec.FeatureGates).ToNot(BeNil())
+				Expect(foundResource.Spec.FeatureGates.WithHostModelCPU).ToNot(BeNil())
+				Expect(*foundResource.Spec.FeatureGates.WithHostModelCPU).To(BeTrue())
+			})
+
 			It("detect upgrade w/o HCO Version", func() {
 				// CDI is not ready
 				expected.cdi.Status.Conditions = getGenericProgressingConditions()
@@ -980,6 +1019,138 @@ var _ = Describe("HyperconvergedController", func() {
 				Expect(cond.Status).Should(BeEquivalentTo("False"))
 			})
 
+			Context("upgrade pre-flight checks", func() {
+				var exp *BasicExpected
+
+				BeforeEach(func() {
+					exp = getBasicDeployment()
+					exp.hco.Status.UpdateVersion(hcoVersionName, oldVersion)
+					exp.hco.Spec.Version = oldVersion
+					exp.hco.Spec.LocalStorageClassName = "missing-sc"
+				})
+
+				reconcileUpgrade := func(cl client.Client) (*ReconcileHyperConverged, reconcile.Result, *hcov1beta1.HyperConverged) {
+					r := initReconciler(cl)
+					r.ownVersion = newVersion
+
+					res, err := r.Reconcile(context.TODO(), request)
+					Expect(err).ToNot(HaveOccurred())
+
+					foundResource := &hcov1beta1.HyperConverged{}
+					Expect(cl.Get(context.TODO(), request.NamespacedName, foundResource)).To(Succeed())
+					return r, res, foundResource
+				}
+
+				It("should block the upgrade if a blocking check fails, and keep reconciling the operands", func() {
+					r, res, foundResource := reconcileUpgrade(exp.initClient())
+					Expect(r.upgradeMode).To(BeFalse())
+					Expect(res.RequeueAfter).To(Equal(preflightRetryInterval))
+
+					Expect(foundResource.Status.UpgradePreflightChecks).To(ContainElement(hcov1beta1.UpgradePreflightCheckStatus{
+						Name:     "required-storage-classes",
+						Blocking: true,
+						Passed:   false,
+						Message:  "the missing-sc local storage class does not exist",
+					}))
+
+					cond := conditionsv1.FindStatusCondition(foundResource.Status.Conditions, conditionsv1.ConditionUpgradeable)
+					Expect(cond).ToNot(BeNil())
+					Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(preflightCheckFailedReason))
+					Expect(cond.Message).To(ContainSubstring("required-storage-classes"))
+
+					// the operands are still reconciled
+					cond = conditionsv1.FindStatusCondition(foundResource.Status.Conditions, hcov1beta1.ConditionReconcileComplete)
+					Expect(cond).ToNot(BeNil())
+					Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+
+					ver, ok := foundResource.Status.GetVersion(hcoVersionName)
+					Expect(ok).To(BeTrue())
+					Expect(ver).To(Equal(oldVersion))
+					Expect(foundResource.Spec.Version).To(Equal(oldVersion))
+				})
+
+				It("should start the upgrade if the blocking checks pass", func() {
+					sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "missing-sc"}}
+					cl := commonTestUtils.InitClient(append(exp.toArray(), sc))
+
+					r, res, foundResource := reconcileUpgrade(cl)
+					Expect(r.upgradeMode).To(BeTrue())
+					Expect(res.RequeueAfter).To(BeZero())
+
+					for _, check := range foundResource.Status.UpgradePreflightChecks {
+						Expect(check.Passed).To(BeTrue(), "check %s failed: %s", check.Name, check.Message)
+					}
+				})
+
+				It("should force the upgrade by the annotation", func() {
+					exp.hco.Annotations = map[string]string{common.ForceUpgradeAnnotationName: "true"}
+
+					r, res, foundResource := reconcileUpgrade(exp.initClient())
+					Expect(r.upgradeMode).To(BeTrue())
+					Expect(res.RequeueAfter).To(BeZero())
+
+					// the failure is still reported
+					Expect(foundResource.Status.UpgradePreflightChecks).To(ContainElement(hcov1beta1.UpgradePreflightCheckStatus{
+						Name:     "required-storage-classes",
+						Blocking: true,
+						Passed:   false,
+						Message:  "the missing-sc local storage class does not exist",
+					}))
+				})
+			})
+
+			Context("upgrade migrations", func() {
+				var exp *BasicExpected
+
+				BeforeEach(func() {
+					exp = getBasicDeployment()
+					exp.hco.Status.UpdateVersion(hcoVersionName, oldVersion)
+					exp.hco.Spec.Version = oldVersion
+					exp.hco.Annotations = nil
+
+					// CDI is not ready
+					exp.cdi.Status.Conditions = getGenericProgressingConditions()
+				})
+
+				It("should keep the changes of the migrations along with the status changes, and record them", func() {
+					cl := exp.initClient()
+					foundResource, _ := doReconcile(cl, exp.hco)
+					Expect(foundResource.Annotations).To(HaveKeyWithValue(hcov1beta1.DeployOVSAnnotation, "false"))
+					Expect(foundResource.Status.CompletedMigrations).ToNot(ContainElement("deploy-ovs-annotation"))
+
+					foundResource, _ = doReconcile(cl, foundResource)
+					Expect(foundResource.Status.CompletedMigrations).To(ContainElement("deploy-ovs-annotation"))
+				})
+
+				It("should reset the completed migrations once the upgrade is completed", func() {
+					exp.hco.Status.CompletedMigrations = []string{"deploy-ovs-annotation"}
+					exp.cdi.Status.Conditions = getGenericCompletedConditions()
+					exp.kv.Status.ObservedKubeVirtVersion = newComponentVersion
+					exp.cdi.Status.ObservedVersion = newComponentVersion
+					exp.cna.Status.ObservedVersion = newComponentVersion
+					exp.vmi.Status.ObservedVersion = newComponentVersion
+					exp.ssp.Status.ObservedVersion = newComponentVersion
+
+					foundResource, _ := doReconcile(exp.initClient(), exp.hco)
+					ver, _ := foundResource.Status.GetVersion(hcoVersionName)
+					Expect(ver).To(Equal(newVersion))
+					Expect(foundResource.Status.CompletedMigrations).To(BeEmpty())
+				})
+			})
+
+			It("should recover spec.version if it was not updated when the upgrade was completed", func() {
+				exp := getBasicDeployment()
+				exp.hco.Status.UpdateVersion(hcoVersionName, newVersion)
+				exp.hco.Spec.Version = oldVersion
+
+				// CDI is not ready; spec.version is recovered anyway
+				exp.cdi.Status.Conditions = getGenericProgressingConditions()
+
+				foundResource, _ := doReconcile(exp.initClient(), exp.hco)
+				Expect(foundResource.Spec.Version).To(Equal(newVersion))
+			})
+
 			It("don't complete upgrade if kubevirt version is not match to the kubevirt version env ver", func() {
 				os.Setenv(hcoutil.HcoKvIoVersionName, newVersion)
 
@@ -1569,10 +1740,21 @@ var _ = Describe("HyperconvergedController", func() {
 						Type:    hcov1beta1.ConditionTaintedConfiguration,
 						Status:  corev1.ConditionTrue,
 						Reason:  taintedConfigurationReason,
-						Message: taintedConfigurationMessage,
+						Message: taintedConfigurationMessage + ": " + common.JSONPatchKVAnnotationName,
 					})))
 				})
 
+				By("Verify that the applied patch is recorded in the status", func() {
+					Expect(foundResource.Status.AppliedPatches).To(Equal([]hcov1beta1.AppliedPatchStatus{
+						{
+							Annotation: common.JSONPatchKVAnnotationName,
+							Type:       operands.JSONPatchType,
+							Kind:       "KubeVirt",
+							Name:       operands.NewKubeVirtWithNameOnly(hco).Name,
+						},
+					}))
+				})
+
 				By("Verify that KV was modified by the annotation", func() {
 					kv := &kubevirtv1.KubeVirt{}
 					kvSearch := operands.NewKubeVirtWithNameOnly(hco)
@@ -1588,6 +1770,58 @@ var _ = Describe("HyperconvergedController", func() {
 				})
 			})
 
+			It("Lists all the active patch annotations, and records the patches of all the operand resources", func() {
+				hco := commonTestUtils.NewHco()
+				hco.ObjectMeta.Annotations = map[string]string{
+					common.MergePatchCDIAnnotationName:               `{"spec": {"config": {"featureGates": ["fg1"]}}}`,
+					common.StrategicPatchStorageConfigAnnotationName: `{"data": {"accessMode": "ReadWriteMany"}}`,
+				}
+
+				cl := commonTestUtils.InitClient([]runtime.Object{hco})
+				r := initReconciler(cl)
+
+				res, err := r.Reconcile(context.TODO(), request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).Should(Equal(reconcile.Result{Requeue: true}))
+
+				foundResource := &hcov1beta1.HyperConverged{}
+				Expect(
+					cl.Get(context.TODO(),
+						types.NamespacedName{Name: hco.Name, Namespace: hco.Namespace},
+						foundResource),
+				).To(BeNil())
+
+				Expect(foundResource.Status.Conditions).To(ContainElement(testlib.RepresentCondition(conditionsv1.Condition{
+					Type:    hcov1beta1.ConditionTaintedConfiguration,
+					Status:  corev1.ConditionTrue,
+					Reason:  taintedConfigurationReason,
+					Message: taintedConfigurationMessage + ": " + common.MergePatchCDIAnnotationName + ", " + common.StrategicPatchStorageConfigAnnotationName,
+				})))
+
+				Expect(foundResource.Status.AppliedPatches).To(Equal([]hcov1beta1.AppliedPatchStatus{
+					{
+						Annotation: common.MergePatchCDIAnnotationName,
+						Type:       operands.MergePatchType,
+						Kind:       "CDI",
+						Name:       operands.NewCDIWithNameOnly(hco).Name,
+					},
+					{
+						Annotation: common.StrategicPatchStorageConfigAnnotationName,
+						Type:       operands.StrategicMergePatchType,
+						Kind:       "ConfigMap",
+						Name:       "kubevirt-storage-class-defaults",
+					},
+				}))
+
+				storageConfig := &corev1.ConfigMap{}
+				Expect(
+					cl.Get(context.TODO(),
+						types.NamespacedName{Name: "kubevirt-storage-class-defaults", Namespace: hco.Namespace},
+						storageConfig),
+				).To(BeNil())
+				Expect(storageConfig.Data).To(HaveKeyWithValue("accessMode", "ReadWriteMany"))
+			})
+
 			It("Removes the TaintedConfiguration condition upon removal of such configuration", func() {
 				hco := commonTestUtils.NewHco()
 				hco.Status.Conditions = append(hco.Status.Conditions, conditionsv1.Condition{
@@ -1626,5 +1860,245 @@ var _ = Describe("HyperconvergedController", func() {
 			})
 
 		})
+
+		Context("Detection of heterogeneous CPU models", func() {
+			newNode := func(name string, models ...string) *corev1.Node {
+				node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+				for _, model := range models {
+					node.Labels[hcoutil.CPUModelLabelPrefix+model] = "true"
+				}
+				return node
+			}
+
+			getCPUModelsCondition := func(cl client.Client, hco *hcov1beta1.HyperConverged) *conditionsv1.Condition {
+				foundResource := &hcov1beta1.HyperConverged{}
+				Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(hco), foundResource)).To(Succeed())
+				return conditionsv1.FindStatusCondition(foundResource.Status.Conditions, hcov1beta1.ConditionHeterogeneousCPUModels)
+			}
+
+			It("should raise the HeterogeneousCPUModels condition when withHostPassthroughCPU is enabled", func() {
+				expected := getBasicDeployment()
+				enabled := true
+				expected.hco.Spec.FeatureGates.WithHostPassthroughCPU = &enabled
+
+				cl := expected.initClient()
+				Expect(cl.Create(context.TODO(), newNode("node1", "Haswell", "IvyBridge"))).To(Succeed())
+				Expect(cl.Create(context.TODO(), newNode("node2", "IvyBridge"))).To(Succeed())
+
+				r := initReconciler(cl)
+				_, err := r.Reconcile(context.TODO(), request)
+				Expect(err).ToNot(HaveOccurred())
+
+				cond := getCPUModelsCondition(cl, expected.hco)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(heterogeneousCPUModelsReason))
+				Expect(cond.Message).To(ContainSubstring("[Haswell,IvyBridge] on nodes node1; [IvyBridge] on nodes node2"))
+			})
+
+			It("should not raise the HeterogeneousCPUModels condition when withHostPassthroughCPU is disabled", func() {
+				expected := getBasicDeployment()
+
+				cl := expected.initClient()
+				Expect(cl.Create(context.TODO(), newNode("node1", "Haswell", "IvyBridge"))).To(Succeed())
+				Expect(cl.Create(context.TODO(), newNode("node2", "IvyBridge"))).To(Succeed())
+
+				r := initReconciler(cl)
+				_, err := r.Reconcile(context.TODO(), request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(getCPUModelsCondition(cl, expected.hco)).To(BeNil())
+			})
+
+			It("should remove the HeterogeneousCPUModels condition when the nodes are homogeneous", func() {
+				expected := getBasicDeployment()
+				enabled := true
+				expected.hco.Spec.FeatureGates.WithHostPassthroughCPU = &enabled
+				expected.hco.Status.Conditions = append(expected.hco.Status.Conditions, conditionsv1.Condition{
+					Type:    hcov1beta1.ConditionHeterogeneousCPUModels,
+					Status:  corev1.ConditionTrue,
+					Reason:  heterogeneousCPUModelsReason,
+					Message: "fake message",
+				})
+
+				cl := expected.initClient()
+				Expect(cl.Create(context.TODO(), newNode("node1", "IvyBridge"))).To(Succeed())
+				Expect(cl.Create(context.TODO(), newNode("node2", "IvyBridge"))).To(Succeed())
+
+				r := initReconciler(cl)
+				_, err := r.Reconcile(context.TODO(), request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(getCPUModelsCondition(cl, expected.hco)).To(BeNil())
+			})
+		})
+
+		Context("Node maintenance", func() {
+			newNodeMaintenance := func(name, nodeName, phase string, pendingPods ...string) *unstructured.Unstructured {
+				nm := hcoutil.NewNodeMaintenanceWithGVKOnly()
+				nm.SetName(name)
+				nm.Object["spec"] = map[string]interface{}{
+					"nodeName": nodeName,
+					"reason":   "hardware upgrade",
+				}
+				pods := make([]interface{}, 0, len(pendingPods))
+				for _, pod := range pendingPods {
+					pods = append(pods, pod)
+				}
+				nm.Object["status"] = map[string]interface{}{
+					"phase":       phase,
+					"pendingPods": pods,
+				}
+				return nm
+			}
+
+			It("should report the nodes under maintenance, sorted by node name", func() {
+				hco := commonTestUtils.NewHco()
+				cl := commonTestUtils.InitClient([]runtime.Object{
+					hco,
+					newNodeMaintenance("nm-b", "node02", hcoutil.NodeMaintenanceRunning, "virt-launcher-vm1-abcde", "virt-launcher-vm2-fghij", "other-pod"),
+					newNodeMaintenance("nm-a", "node01", "Succeeded"),
+				})
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.updateNodesUnderMaintenance(req)
+
+				Expect(req.StatusDirty).To(BeTrue())
+				Expect(hco.Status.NodesUnderMaintenance).To(Equal([]hcov1beta1.NodeMaintenanceStatus{
+					{NodeName: "node01", Reason: "hardware upgrade", Phase: "Succeeded"},
+					{NodeName: "node02", Reason: "hardware upgrade", Phase: hcoutil.NodeMaintenanceRunning, PendingPods: 3, PendingVMs: 2},
+				}))
+			})
+
+			It("should not update the status if nothing was changed", func() {
+				hco := commonTestUtils.NewHco()
+				hco.Status.NodesUnderMaintenance = []hcov1beta1.NodeMaintenanceStatus{
+					{NodeName: "node01", Reason: "hardware upgrade", Phase: "Succeeded"},
+				}
+				cl := commonTestUtils.InitClient([]runtime.Object{hco, newNodeMaintenance("nm-a", "node01", "Succeeded")})
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.updateNodesUnderMaintenance(req)
+
+				Expect(req.StatusDirty).To(BeFalse())
+			})
+
+			It("should remove nodes that are no longer under maintenance", func() {
+				hco := commonTestUtils.NewHco()
+				hco.Status.NodesUnderMaintenance = []hcov1beta1.NodeMaintenanceStatus{
+					{NodeName: "node01", Reason: "hardware upgrade", Phase: "Succeeded"},
+				}
+				cl := commonTestUtils.InitClient([]runtime.Object{hco})
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.updateNodesUnderMaintenance(req)
+
+				Expect(req.StatusDirty).To(BeTrue())
+				Expect(hco.Status.NodesUnderMaintenance).To(BeNil())
+			})
+		})
+
+		Context("Detection of an unsatisfiable placement", func() {
+			newNode := func(name string, labels map[string]string) *corev1.Node {
+				return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+			}
+
+			var nodes []runtime.Object
+
+			BeforeEach(func() {
+				nodes = []runtime.Object{
+					newNode("node01", map[string]string{"role": "infra"}),
+					newNode("node02", map[string]string{"role": "worker"}),
+					newNode("node03", map[string]string{"role": "worker"}),
+				}
+			})
+
+			getCondition := func(hco *hcov1beta1.HyperConverged) *conditionsv1.Condition {
+				return conditionsv1.FindStatusCondition(hco.Status.Conditions, hcov1beta1.ConditionPlacementUnsatisfiable)
+			}
+
+			It("should report the number of the eligible nodes", func() {
+				hco := commonTestUtils.NewHco()
+				hco.Spec.Workloads.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "worker"}}
+				cl := commonTestUtils.InitClient(append(nodes, hco))
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.detectUnsatisfiablePlacement(req)
+
+				Expect(req.StatusDirty).To(BeTrue())
+				cond := getCondition(hco)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(placementSatisfiableReason))
+				Expect(cond.Message).To(Equal("spec.infra.nodePlacement matches 3 eligible nodes; spec.workloads.nodePlacement matches 2 eligible nodes"))
+			})
+
+			It("should raise the condition if a placement does not match any node", func() {
+				hco := commonTestUtils.NewHco()
+				hco.Spec.Infra.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "infar"}}
+				cl := commonTestUtils.InitClient(append(nodes, hco))
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.detectUnsatisfiablePlacement(req)
+
+				cond := getCondition(hco)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(placementUnsatisfiableReason))
+				Expect(cond.Message).To(HavePrefix("spec.infra.nodePlacement do not match any eligible node; spec.infra.nodePlacement matches 0 eligible nodes"))
+
+				expectedEvents := []commonTestUtils.MockEvent{
+					{EventType: corev1.EventTypeWarning, Reason: placementUnsatisfiableReason, Msg: cond.Message},
+				}
+				Expect(r.eventEmitter.(*commonTestUtils.EventEmitterMock).CheckEvents(expectedEvents)).To(BeTrue())
+			})
+
+			It("should not count the nodes that are under maintenance", func() {
+				hco := commonTestUtils.NewHco()
+				hco.Spec.Infra.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "infra"}}
+				nm := hcoutil.NewNodeMaintenanceWithGVKOnly()
+				nm.SetName("nm-a")
+				nm.Object["spec"] = map[string]interface{}{"nodeName": "node01"}
+				cl := commonTestUtils.InitClient(append(nodes, hco, nm))
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.detectUnsatisfiablePlacement(req)
+
+				cond := getCondition(hco)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+			})
+
+			It("should not flag a placement that is not set", func() {
+				hco := commonTestUtils.NewHco()
+				cl := commonTestUtils.InitClient([]runtime.Object{hco})
+				r := initReconciler(cl)
+				req := commonTestUtils.NewReq(hco)
+
+				r.detectUnsatisfiablePlacement(req)
+
+				cond := getCondition(hco)
+				Expect(cond).ToNot(BeNil())
+				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+			})
+
+			It("should not update the status if nothing was changed", func() {
+				hco := commonTestUtils.NewHco()
+				cl := commonTestUtils.InitClient(append(nodes, hco))
+				r := initReconciler(cl)
+
+				r.detectUnsatisfiablePlacement(commonTestUtils.NewReq(hco))
+				req := commonTestUtils.NewReq(hco)
+				r.detectUnsatisfiablePlacement(req)
+
+				Expect(req.StatusDirty).To(BeFalse())
+			})
+		})
 	})
 })
@@ -9,7 +9,9 @@ import (
 	networkaddonsv1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/migrations"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/preflight"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	"github.com/kubevirt/hyperconverged-cluster-operator/version"
 	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
@@ -45,12 +47,15 @@ var (
 func initReconciler(client client.Client) *ReconcileHyperConverged {
 	s := commonTestUtils.GetScheme()
 	eventEmitter := commonTestUtils.NewEventEmitterMock()
-	operandHandler := operands.NewOperandHandler(client, s, true, eventEmitter)
+	operandHandler := operands.NewOperandHandler(client, s, commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
 	// Create a ReconcileHyperConverged object with the scheme and fake client
 	return &ReconcileHyperConverged{
 		client:             client,
+		apiReader:          client,
 		scheme:             s,
 		operandHandler:     operandHandler,
+		migrations:         migrations.GetRegistry(),
+		preflightChecks:    preflight.GetRegistry(),
 		eventEmitter:       eventEmitter,
 		cliDownloadHandler: &operands.CLIDownloadHandler{Client: client, Scheme: s},
 		firstLoop:          true,
@@ -123,6 +128,8 @@ func getBasicDeployment() *BasicExpected {
 			},
 		},
 	}
+	// the CR is stored with the defaults, that are set by the API server and by the mutating webhook
+	hco.SetDefaults()
 	res.hco = hco
 
 	res.pc = operands.NewKubeVirtPriorityClass(hco)
@@ -178,12 +185,14 @@ func getBasicDeployment() *BasicExpected {
 	expectedCNA.Status.Conditions = getGenericCompletedConditions()
 	res.cna = expectedCNA
 
-	expectedSSP := operands.NewSSP(hco)
+	expectedSSP, err := operands.NewSSP(hco)
+	Expect(err).ToNot(HaveOccurred())
 	expectedSSP.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/ctbs/%s", expectedSSP.Namespace, expectedSSP.Name)
 	expectedSSP.Status.Conditions = getGenericCompletedConditions()
 	res.ssp = expectedSSP
 
-	expectedVMI := operands.NewVMImportForCR(hco)
+	expectedVMI, err := operands.NewVMImportForCR(hco)
+	Expect(err).ToNot(HaveOccurred())
 	expectedVMI.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/vmimportconfigs/%s", expectedVMI.Namespace, expectedVMI.Name)
 	expectedVMI.Status.Conditions = getGenericCompletedConditions()
 	res.vmi = expectedVMI
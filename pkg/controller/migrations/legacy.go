@@ -0,0 +1,102 @@
+package migrations
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+)
+
+// legacyMigrations is the list of the registered migrations. New migrations are appended to the end of the list.
+var legacyMigrations = []Migration{
+	{
+		// Starting from HCO 1.3.0, OvS is not deployed by default. Keep the OvS state of upgraded clusters that don't
+		// have the deployOVS annotation yet, whatever version they are upgraded from.
+		Name:         "deploy-ovs-annotation",
+		Precondition: isDeployOvsAnnotationMissing,
+		Migrate:      setDeployOvsAnnotation,
+	},
+	{
+		// The migrations key of the kubevirt-config ConfigMap was set by old HCO versions, and it is not supported
+		// anymore.
+		Name:         "kubevirt-config-migrations-key",
+		Precondition: isKvConfigMigrationsKeySet,
+		Migrate:      removeKvConfigMigrationsKey,
+	},
+	{
+		// The CRDs of the previous generations of SSP are removed once the new SSP is deployed.
+		Name:         "remove-old-ssp-crds",
+		AfterUpgrade: true,
+		Precondition: isOldSspCRDsRemovalRequired,
+		Migrate:      removeOldSspCRDs,
+	},
+}
+
+func isDeployOvsAnnotationMissing(req *common.HcoRequest, _ client.Client) (bool, error) {
+	_, exists := req.Instance.Annotations[hcov1beta1.DeployOVSAnnotation]
+	return !exists, nil
+}
+
+// setDeployOvsAnnotation sets the deployOVS annotation to true if OvS is deployed; otherwise, to false.
+func setDeployOvsAnnotation(req *common.HcoRequest, cl client.Client) error {
+	cna := operands.NewNetworkAddonsWithNameOnly(req.Instance)
+	err := cl.Get(req.Ctx, client.ObjectKeyFromObject(cna), cna)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if req.Instance.Annotations == nil {
+		req.Instance.Annotations = map[string]string{}
+	}
+
+	if err == nil && cna.Spec.Ovs != nil {
+		req.Instance.Annotations[hcov1beta1.DeployOVSAnnotation] = "true"
+		req.Logger.Info("deployOVS annotation is set to true.")
+	} else {
+		req.Instance.Annotations[hcov1beta1.DeployOVSAnnotation] = "false"
+		req.Logger.Info("deployOVS annotation is set to false.")
+	}
+
+	req.Dirty = true
+	return nil
+}
+
+// getKubeVirtConfig returns the kubevirt-config ConfigMap, or nil if it does not exist
+func getKubeVirtConfig(req *common.HcoRequest, cl client.Client) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := cl.Get(req.Ctx, client.ObjectKey{Namespace: req.Instance.Namespace, Name: operands.KubeVirtConfigMapName}, cm)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return cm, nil
+}
+
+func isKvConfigMigrationsKeySet(req *common.HcoRequest, cl client.Client) (bool, error) {
+	cm, err := getKubeVirtConfig(req, cl)
+	if err != nil || cm == nil {
+		return false, err
+	}
+
+	_, found := cm.Data[operands.MigrationsConfigKey]
+	return found, nil
+}
+
+func removeKvConfigMigrationsKey(req *common.HcoRequest, cl client.Client) error {
+	cm, err := getKubeVirtConfig(req, cl)
+	if err != nil || cm == nil {
+		return err
+	}
+
+	req.Logger.Info(fmt.Sprintf("Deleting %s on existing KubeVirt config", operands.MigrationsConfigKey))
+	delete(cm.Data, operands.MigrationsConfigKey)
+	return cl.Update(req.Ctx, cm)
+}
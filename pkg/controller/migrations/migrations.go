@@ -0,0 +1,150 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blang/semver"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// Migration is an upgrade step, that migrates the resources that were deployed by a previous version of HCO.
+type Migration struct {
+	// Name is the unique name of the migration. It is recorded in the HyperConverged status once the migration is
+	// completed.
+	Name string
+	// FromVersions is the semver range of the HCO versions that the migration upgrades from; e.g. "<1.3.0". An empty
+	// range matches all the versions.
+	FromVersions string
+	// AfterUpgrade migrations run once the upgrade is completed, i.e. once the new versions of the operands are
+	// deployed; e.g. to remove the resources of the previous versions of the operands. The previous HCO version is not
+	// known anymore at that point, so an AfterUpgrade migration applies to all the versions.
+	AfterUpgrade bool
+	// Precondition checks if the migration is still required. A migration is recorded as completed only once its
+	// precondition returns false, so a migration whose changes were not persisted, e.g. because the update of the
+	// HyperConverged CR failed, runs again.
+	Precondition func(req *common.HcoRequest, cl client.Client) (bool, error)
+	// Migrate runs the migration. It must be idempotent, because it runs again if the upgrade was interrupted before
+	// the migration was recorded as completed.
+	Migrate func(req *common.HcoRequest, cl client.Client) error
+
+	fromVersions semver.Range
+}
+
+// Registry is the ordered list of the upgrade migrations
+type Registry struct {
+	migrations []Migration
+}
+
+// NewRegistry validates the migrations and returns a registry that runs them in the given order.
+func NewRegistry(migrations ...Migration) (*Registry, error) {
+	names := make(map[string]bool, len(migrations))
+	registry := &Registry{migrations: make([]Migration, 0, len(migrations))}
+
+	for _, m := range migrations {
+		if m.Name == "" {
+			return nil, errors.New("missing migration name")
+		}
+		if names[m.Name] {
+			return nil, fmt.Errorf("duplicated migration name %s", m.Name)
+		}
+		names[m.Name] = true
+
+		if m.Precondition == nil || m.Migrate == nil {
+			return nil, fmt.Errorf("migration %s must define both the Precondition and the Migrate functions", m.Name)
+		}
+
+		if m.FromVersions == "" {
+			m.fromVersions = allVersions
+		} else if m.AfterUpgrade {
+			return nil, fmt.Errorf("migration %s runs after the upgrade, and can't define a version range", m.Name)
+		} else {
+			fromVersions, err := semver.ParseRange(m.FromVersions)
+			if err != nil {
+				return nil, fmt.Errorf("wrong version range of migration %s; %w", m.Name, err)
+			}
+			m.fromVersions = fromVersions
+		}
+
+		registry.migrations = append(registry.migrations, m)
+	}
+
+	return registry, nil
+}
+
+// Run runs the pending migrations that apply to the version HCO is upgraded from, and records the completed ones in
+// the HyperConverged status. Run stops on the first failing migration, so the following reconciliation resumes from it.
+//
+// An empty fromVersion means that the previous version is older than the versions that report their version in the
+// HyperConverged status, and it is handled as version 0.0.0.
+func (r Registry) Run(req *common.HcoRequest, cl client.Client, fromVersion string) error {
+	from := semver.Version{}
+	if fromVersion != "" {
+		var err error
+		if from, err = semver.ParseTolerant(fromVersion); err != nil {
+			return fmt.Errorf("can't parse the previous HCO version %s; %w", fromVersion, err)
+		}
+	}
+
+	return r.run(req, cl, func(m Migration) bool {
+		return !m.AfterUpgrade && m.fromVersions(from)
+	})
+}
+
+// RunAfterUpgrade runs the pending AfterUpgrade migrations, and records the completed ones in the HyperConverged
+// status.
+func (r Registry) RunAfterUpgrade(req *common.HcoRequest, cl client.Client) error {
+	return r.run(req, cl, func(m Migration) bool {
+		return m.AfterUpgrade
+	})
+}
+
+func (r Registry) run(req *common.HcoRequest, cl client.Client, applies func(m Migration) bool) error {
+	for _, m := range r.migrations {
+		if hcoutil.ContainsString(req.Instance.Status.CompletedMigrations, m.Name) || !applies(m) {
+			continue
+		}
+
+		required, err := m.Precondition(req, cl)
+		if err != nil {
+			return fmt.Errorf("failed to check the precondition of migration %s; %w", m.Name, err)
+		}
+
+		if !required {
+			req.Logger.Info("migration is completed", "migration", m.Name)
+			req.Instance.Status.CompletedMigrations = append(req.Instance.Status.CompletedMigrations, m.Name)
+			req.StatusDirty = true
+			continue
+		}
+
+		// the migration is recorded as completed by the following reconciliation, once its changes are persisted
+		req.Logger.Info("running migration", "migration", m.Name)
+		if err = m.Migrate(req, cl); err != nil {
+			return fmt.Errorf("migration %s failed; %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+var registry *Registry
+
+func allVersions(semver.Version) bool {
+	return true
+}
+
+// GetRegistry returns the registry of the HCO upgrade migrations
+func GetRegistry() *Registry {
+	return registry
+}
+
+func init() {
+	var err error
+	registry, err = NewRegistry(legacyMigrations...)
+	if err != nil {
+		panic(err)
+	}
+}
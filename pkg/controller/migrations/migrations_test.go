@@ -0,0 +1,406 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+
+	networkaddonsshared "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/shared"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	objectreferencesv1 "github.com/openshift/custom-resource-status/objectreferences/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+)
+
+var _ = Describe("Migrations", func() {
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		req = commonTestUtils.NewReq(hco)
+	})
+
+	// newMigration returns a migration that counts its runs in the runs map. A required migration is not required
+	// anymore once it ran.
+	newMigration := func(name, fromVersions string, required bool, runs map[string]int) Migration {
+		return Migration{
+			Name:         name,
+			FromVersions: fromVersions,
+			Precondition: func(_ *common.HcoRequest, _ client.Client) (bool, error) {
+				return required && runs[name] == 0, nil
+			},
+			Migrate: func(_ *common.HcoRequest, _ client.Client) error {
+				runs[name]++
+				return nil
+			},
+		}
+	}
+
+	newAfterUpgradeMigration := func(name string, runs map[string]int) Migration {
+		m := newMigration(name, "", true, runs)
+		m.AfterUpgrade = true
+		return m
+	}
+
+	Context("NewRegistry", func() {
+		noop := func(_ *common.HcoRequest, _ client.Client) error { return nil }
+		required := func(_ *common.HcoRequest, _ client.Client) (bool, error) { return true, nil }
+
+		table.DescribeTable("should reject wrong migrations", func(migrations []Migration, errMsg string) {
+			registry, err := NewRegistry(migrations...)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(errMsg))
+			Expect(registry).To(BeNil())
+		},
+			table.Entry("missing name",
+				[]Migration{{FromVersions: "<1.0.0", Precondition: required, Migrate: noop}},
+				"missing migration name"),
+			table.Entry("duplicated name",
+				[]Migration{
+					{Name: "a", FromVersions: "<1.0.0", Precondition: required, Migrate: noop},
+					{Name: "a", FromVersions: "<2.0.0", Precondition: required, Migrate: noop},
+				},
+				"duplicated migration name a"),
+			table.Entry("missing precondition",
+				[]Migration{{Name: "a", FromVersions: "<1.0.0", Migrate: noop}},
+				"must define both"),
+			table.Entry("missing migrate function",
+				[]Migration{{Name: "a", FromVersions: "<1.0.0", Precondition: required}},
+				"must define both"),
+			table.Entry("wrong version range",
+				[]Migration{{Name: "a", FromVersions: "not-a-range", Precondition: required, Migrate: noop}},
+				"wrong version range of migration a"),
+			table.Entry("version range of an AfterUpgrade migration",
+				[]Migration{{Name: "a", FromVersions: "<1.0.0", AfterUpgrade: true, Precondition: required, Migrate: noop}},
+				"migration a runs after the upgrade, and can't define a version range"),
+		)
+
+		It("should validate the registered migrations", func() {
+			Expect(GetRegistry()).ToNot(BeNil())
+			Expect(GetRegistry().migrations).To(HaveLen(len(legacyMigrations)))
+		})
+	})
+
+	Context("Run", func() {
+		It("should run only the migrations of the previous version", func() {
+			runs := map[string]int{}
+			registry, err := NewRegistry(
+				newMigration("from-1.2", "<1.3.0", true, runs),
+				newMigration("from-1.3", ">=1.3.0 <1.4.0", true, runs),
+				newMigration("from-1.3-and-up", ">=1.3.0", true, runs),
+				newMigration("from-any", "", true, runs),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "1.3.1")).To(Succeed())
+			Expect(runs).To(Equal(map[string]int{"from-1.3": 1, "from-1.3-and-up": 1, "from-any": 1}))
+		})
+
+		It("should record the migrations as completed once they are not required anymore", func() {
+			runs := map[string]int{}
+			registry, err := NewRegistry(
+				newMigration("first", "", true, runs),
+				newMigration("second", "", true, runs),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "1.3.1")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(BeEmpty())
+			Expect(req.StatusDirty).To(BeFalse())
+
+			Expect(registry.Run(req, nil, "1.3.1")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(Equal([]string{"first", "second"}))
+			Expect(req.StatusDirty).To(BeTrue())
+			Expect(runs).To(Equal(map[string]int{"first": 1, "second": 1}))
+		})
+
+		It("should run a migration again if its changes were not persisted", func() {
+			runs := map[string]int{}
+			lost := newMigration("lost", "", true, runs)
+			lost.Precondition = func(_ *common.HcoRequest, _ client.Client) (bool, error) {
+				return true, nil
+			}
+
+			registry, err := NewRegistry(lost)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "1.3.1")).To(Succeed())
+			Expect(registry.Run(req, nil, "1.3.1")).To(Succeed())
+			Expect(runs).To(Equal(map[string]int{"lost": 2}))
+			Expect(hco.Status.CompletedMigrations).To(BeEmpty())
+		})
+
+		It("should handle a missing previous version as a very old version", func() {
+			runs := map[string]int{}
+			registry, err := NewRegistry(
+				newMigration("from-1.2", "<1.3.0", true, runs),
+				newMigration("from-1.3", ">=1.3.0", true, runs),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "")).To(Succeed())
+			Expect(runs).To(Equal(map[string]int{"from-1.2": 1}))
+		})
+
+		It("should accept a 'v' prefix and pre-release versions", func() {
+			runs := map[string]int{}
+			registry, err := NewRegistry(newMigration("from-1.3", "<1.4.0", true, runs))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "v1.4.0-unstable")).To(Succeed())
+			Expect(runs).To(Equal(map[string]int{"from-1.3": 1}))
+		})
+
+		It("should fail if the previous version can't be parsed", func() {
+			registry, err := NewRegistry()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "not-a-version")).ToNot(Succeed())
+		})
+
+		It("should record a migration that is not required without running it", func() {
+			runs := map[string]int{}
+			registry, err := NewRegistry(newMigration("not-required", "<1.3.0", false, runs))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "1.2.0")).To(Succeed())
+			Expect(runs).To(BeEmpty())
+			Expect(hco.Status.CompletedMigrations).To(Equal([]string{"not-required"}))
+		})
+
+		It("should resume after a failed migration, without running the completed ones again", func() {
+			runs := map[string]int{}
+			failure := errors.New("fake migration error")
+			failing := newMigration("failing", "<1.3.0", true, runs)
+			failing.Migrate = func(_ *common.HcoRequest, _ client.Client) error {
+				runs["failing"]++
+				if runs["failing"] == 1 {
+					return failure
+				}
+				return nil
+			}
+			failing.Precondition = func(_ *common.HcoRequest, _ client.Client) (bool, error) {
+				return runs["failing"] < 2, nil
+			}
+
+			registry, err := NewRegistry(
+				newMigration("first", "<1.3.0", false, runs),
+				failing,
+				newMigration("last", "<1.3.0", false, runs),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = registry.Run(req, nil, "1.2.0")
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, failure)).To(BeTrue())
+			Expect(hco.Status.CompletedMigrations).To(Equal([]string{"first"}))
+			Expect(runs).To(Equal(map[string]int{"failing": 1}))
+
+			Expect(registry.Run(req, nil, "1.2.0")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(Equal([]string{"first", "last"}))
+			Expect(runs).To(Equal(map[string]int{"failing": 2}))
+
+			Expect(registry.Run(req, nil, "1.2.0")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(Equal([]string{"first", "last", "failing"}))
+			Expect(runs).To(Equal(map[string]int{"failing": 2}))
+		})
+
+		It("should run the AfterUpgrade migrations only after the upgrade", func() {
+			runs := map[string]int{}
+			registry, err := NewRegistry(
+				newMigration("upgrade", "", true, runs),
+				newAfterUpgradeMigration("after-upgrade", runs),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil, "1.3.1")).To(Succeed())
+			Expect(runs).To(Equal(map[string]int{"upgrade": 1}))
+
+			Expect(registry.RunAfterUpgrade(req, nil)).To(Succeed())
+			Expect(runs).To(Equal(map[string]int{"upgrade": 1, "after-upgrade": 1}))
+
+			Expect(registry.RunAfterUpgrade(req, nil)).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(Equal([]string{"after-upgrade"}))
+		})
+	})
+
+	Context("deploy-ovs-annotation", func() {
+		It("should set the annotation to true if OvS is deployed", func() {
+			cna := operands.NewNetworkAddonsWithNameOnly(hco)
+			cna.Spec.Ovs = &networkaddonsshared.Ovs{}
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, cna})
+
+			Expect(GetRegistry().Run(req, cl, "1.2.0")).To(Succeed())
+			Expect(hco.Annotations).To(HaveKeyWithValue(hcov1beta1.DeployOVSAnnotation, "true"))
+			Expect(req.Dirty).To(BeTrue())
+
+			Expect(GetRegistry().Run(req, cl, "1.2.0")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(ContainElement("deploy-ovs-annotation"))
+		})
+
+		It("should set the annotation to false if OvS is not deployed", func() {
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, operands.NewNetworkAddonsWithNameOnly(hco)})
+
+			Expect(GetRegistry().Run(req, cl, "1.2.0")).To(Succeed())
+			Expect(hco.Annotations).To(HaveKeyWithValue(hcov1beta1.DeployOVSAnnotation, "false"))
+		})
+
+		It("should set the annotation to false if the NetworkAddonsConfig does not exist", func() {
+			cl := commonTestUtils.InitClient([]runtime.Object{hco})
+
+			Expect(GetRegistry().Run(req, cl, "1.2.0")).To(Succeed())
+			Expect(hco.Annotations).To(HaveKeyWithValue(hcov1beta1.DeployOVSAnnotation, "false"))
+		})
+
+		It("should keep an existing annotation", func() {
+			hco.Annotations = map[string]string{hcov1beta1.DeployOVSAnnotation: "false"}
+			cna := operands.NewNetworkAddonsWithNameOnly(hco)
+			cna.Spec.Ovs = &networkaddonsshared.Ovs{}
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, cna})
+
+			Expect(GetRegistry().Run(req, cl, "1.2.0")).To(Succeed())
+			Expect(hco.Annotations).To(HaveKeyWithValue(hcov1beta1.DeployOVSAnnotation, "false"))
+			Expect(hco.Status.CompletedMigrations).To(ContainElement("deploy-ovs-annotation"))
+			Expect(req.Dirty).To(BeFalse())
+		})
+
+		It("should set a missing annotation whatever version HCO is upgraded from", func() {
+			cna := operands.NewNetworkAddonsWithNameOnly(hco)
+			cna.Spec.Ovs = &networkaddonsshared.Ovs{}
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, cna})
+
+			Expect(GetRegistry().Run(req, cl, "1.3.0")).To(Succeed())
+			Expect(hco.Annotations).To(HaveKeyWithValue(hcov1beta1.DeployOVSAnnotation, "true"))
+		})
+	})
+
+	Context("kubevirt-config-migrations-key", func() {
+		getKvConfig := func(cl client.Client) *corev1.ConfigMap {
+			cm := &corev1.ConfigMap{}
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: hco.Namespace, Name: operands.KubeVirtConfigMapName}, cm)).To(Succeed())
+			return cm
+		}
+
+		It("should remove the migrations key of the kubevirt-config ConfigMap", func() {
+			cm := operands.NewKubeVirtConfigForCR(hco, hco.Namespace)
+			cm.Data[operands.MigrationsConfigKey] = "old-migrationsconfig-value-that-we-should-remove"
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, cm})
+
+			Expect(GetRegistry().Run(req, cl, "1.3.0")).To(Succeed())
+			Expect(getKvConfig(cl).Data).ToNot(HaveKey(operands.MigrationsConfigKey))
+			Expect(getKvConfig(cl).Data).To(HaveKey(operands.FeatureGatesKey))
+
+			Expect(GetRegistry().Run(req, cl, "1.3.0")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(ContainElement("kubevirt-config-migrations-key"))
+		})
+
+		It("should not be required if the kubevirt-config ConfigMap does not exist", func() {
+			cl := commonTestUtils.InitClient([]runtime.Object{hco})
+
+			Expect(GetRegistry().Run(req, cl, "1.3.0")).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(ContainElement("kubevirt-config-migrations-key"))
+		})
+	})
+
+	Context("remove-old-ssp-crds", func() {
+		oldSspCRDObjects := func() []runtime.Object {
+			objs := make([]runtime.Object, 0, len(oldSspCRDs))
+			for _, gk := range oldSspCRDs {
+				objs = append(objs, &apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: groupKindToCRDName(gk)},
+				})
+			}
+			return objs
+		}
+
+		oldSspRelatedObjects := []corev1.ObjectReference{
+			{
+				APIVersion: "ssp.kubevirt.io/v1",
+				Kind:       "KubevirtCommonTemplatesBundle",
+				Name:       "common-templates-kubevirt-hyperconverged",
+				Namespace:  "openshift",
+			},
+			{
+				APIVersion: "ssp.kubevirt.io/v1",
+				Kind:       "KubevirtTemplateValidator",
+				Name:       "template-validator-kubevirt-hyperconverged",
+				Namespace:  "kubevirt-hyperconverged",
+			},
+		}
+
+		otherRelatedObject := corev1.ObjectReference{
+			APIVersion: "cdi.kubevirt.io/v1beta1",
+			Kind:       "CDI",
+			Name:       "cdi-kubevirt-hyperconverged",
+		}
+
+		BeforeEach(func() {
+			for _, objRef := range append(oldSspRelatedObjects, otherRelatedObject) {
+				Expect(objectreferencesv1.SetObjectReference(&hco.Status.RelatedObjects, objRef)).To(Succeed())
+			}
+		})
+
+		It("should not remove the old CRDs before the upgrade is completed", func() {
+			cl := commonTestUtils.InitClient(oldSspCRDObjects())
+
+			Expect(GetRegistry().Run(req, cl, "1.3.0")).To(Succeed())
+
+			crds := &apiextensionsv1.CustomResourceDefinitionList{}
+			Expect(cl.List(context.TODO(), crds)).To(Succeed())
+			Expect(crds.Items).To(HaveLen(len(oldSspCRDs)))
+		})
+
+		It("should remove the old CRDs and their related objects once the upgrade is completed", func() {
+			cl := commonTestUtils.InitClient(oldSspCRDObjects())
+
+			Expect(GetRegistry().RunAfterUpgrade(req, cl)).To(Succeed())
+
+			crds := &apiextensionsv1.CustomResourceDefinitionList{}
+			Expect(cl.List(context.TODO(), crds)).To(Succeed())
+			Expect(crds.Items).To(BeEmpty())
+			Expect(hco.Status.RelatedObjects).To(Equal([]corev1.ObjectReference{otherRelatedObject}))
+			Expect(req.StatusDirty).To(BeTrue())
+
+			Expect(GetRegistry().RunAfterUpgrade(req, cl)).To(Succeed())
+			Expect(hco.Status.CompletedMigrations).To(ContainElement("remove-old-ssp-crds"))
+		})
+
+		It("should remove the related objects again if the status update failed", func() {
+			cl := commonTestUtils.InitClient(nil)
+
+			Expect(GetRegistry().RunAfterUpgrade(req, cl)).To(Succeed())
+			Expect(hco.Status.RelatedObjects).To(Equal([]corev1.ObjectReference{otherRelatedObject}))
+
+			// simulate a status update failure
+			for _, objRef := range oldSspRelatedObjects {
+				Expect(objectreferencesv1.SetObjectReference(&hco.Status.RelatedObjects, objRef)).To(Succeed())
+			}
+
+			Expect(GetRegistry().RunAfterUpgrade(req, cl)).To(Succeed())
+			Expect(hco.Status.RelatedObjects).To(Equal([]corev1.ObjectReference{otherRelatedObject}))
+			Expect(hco.Status.CompletedMigrations).ToNot(ContainElement("remove-old-ssp-crds"))
+		})
+
+		It("should fail if an old CRD can't be removed", func() {
+			cl := commonTestUtils.InitClient(oldSspCRDObjects())
+			cl.InitiateDeleteErrors(func(obj client.Object) error {
+				return errors.New("fake delete error")
+			})
+
+			err := GetRegistry().RunAfterUpgrade(req, cl)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("remove-old-ssp-crds"))
+			Expect(hco.Status.CompletedMigrations).ToNot(ContainElement("remove-old-ssp-crds"))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	objectreferencesv1 "github.com/openshift/custom-resource-status/objectreferences/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+)
+
+const (
+	// These group are no longer supported. Use these constants to remove unused resources
+	prevSspGroup = "ssp.kubevirt.io"
+	origSspGroup = "kubevirt.io"
+)
+
+// Old SSP CRDs that need to be removed from the cluster.
+// Removal of the CRDs also leads to removal of its CRs.
+var oldSspCRDs = []schema.GroupKind{
+	// These are the 2nd generation SSP CRDs,
+	// where the group name has been changed to "ssp.kubevirt.io"
+	{Group: prevSspGroup, Kind: "KubevirtCommonTemplatesBundle"},
+	{Group: prevSspGroup, Kind: "KubevirtNodeLabellerBundle"},
+	{Group: prevSspGroup, Kind: "KubevirtTemplateValidator"},
+	{Group: prevSspGroup, Kind: "KubevirtMetricsAggregation"},
+
+	// These are the original SSP CRDs, with the group name "kubevirt.io".
+	// We attempt to remove these too, for upgrades from even older version.
+	{Group: origSspGroup, Kind: "KubevirtCommonTemplatesBundle"},
+	{Group: origSspGroup, Kind: "KubevirtNodeLabellerBundle"},
+	{Group: origSspGroup, Kind: "KubevirtTemplateValidator"},
+	{Group: origSspGroup, Kind: "KubevirtMetricsAggregation"},
+}
+
+// isOldSspCRDsRemovalRequired returns true if one of the old SSP CRDs still exists, or if one of their CRs is still in
+// the list of the related objects
+func isOldSspCRDsRemovalRequired(req *common.HcoRequest, cl client.Client) (bool, error) {
+	for _, gk := range oldSspCRDs {
+		err := cl.Get(req.Ctx, client.ObjectKey{Name: groupKindToCRDName(gk)}, &apiextensionsv1.CustomResourceDefinition{})
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	return len(getOldSspRelatedObjects(req)) > 0, nil
+}
+
+// removeOldSspCRDs removes the old SSP CRDs, and removes their CRs from the list of related objects
+func removeOldSspCRDs(req *common.HcoRequest, cl client.Client) error {
+	for _, gk := range oldSspCRDs {
+		crdName := groupKindToCRDName(gk)
+		crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: crdName}}
+
+		err := cl.Delete(req.Ctx, crd)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to remove the %s CRD; %w", crdName, err)
+			}
+			continue
+		}
+
+		req.Logger.Info("successfully removed CRD", "CRD Name", crdName)
+	}
+
+	for _, objRef := range getOldSspRelatedObjects(req) {
+		if err := objectreferencesv1.RemoveObjectReference(&req.Instance.Status.RelatedObjects, objRef); err != nil {
+			return fmt.Errorf("failed removing object reference %s %s from HCO.Status.RelatedObjects; %w", objRef.Kind, objRef.Name, err)
+		}
+		req.StatusDirty = true
+	}
+
+	return nil
+}
+
+// getOldSspRelatedObjects returns the CRs of the old SSP CRDs, in the list of the related objects
+func getOldSspRelatedObjects(req *common.HcoRequest) []corev1.ObjectReference {
+	var objRefs []corev1.ObjectReference
+	for _, objRef := range req.Instance.Status.RelatedObjects {
+		objGK := objRef.GroupVersionKind().GroupKind()
+		for _, gk := range oldSspCRDs {
+			if objGK == gk {
+				objRefs = append(objRefs, objRef)
+				break
+			}
+		}
+	}
+
+	return objRefs
+}
+
+// This function creates a CRD name with the form <plural>.<group>,
+// where <plural> is the lowercase plural form of the Kind.
+// Note that this doesn't use any generic pluralization, so may not
+// work for CRDs other than the old SSP CRDs we're trying to handle here.
+func groupKindToCRDName(gk schema.GroupKind) string {
+	plural := strings.ToLower(gk.Kind) + "s"
+	return fmt.Sprintf("%s.%s", plural, gk.Group)
+}
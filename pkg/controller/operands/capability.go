@@ -0,0 +1,137 @@
+package operands
+
+import (
+	"sync"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The capabilities that the optional operands require
+var (
+	// SSP deploys the common templates, and is supported only on OpenShift
+	sspCapabilities         = []hcoutil.Capability{hcoutil.CapabilityClusterVersion}
+	monitoringCapabilities  = []hcoutil.Capability{hcoutil.CapabilityMonitoring}
+	quickStartCapabilities  = []hcoutil.Capability{hcoutil.CapabilityConsole}
+	cliDownloadCapabilities = []hcoutil.Capability{hcoutil.CapabilityConsole}
+)
+
+// The optional CRDs, that may be deployed after HCO starts, or be removed
+const (
+	SspCrdName             = "ssps.ssp.kubevirt.io"
+	VMImportConfigCrdName  = "vmimportconfigs.v2v.kubevirt.io"
+	ServiceMonitorCrdName  = "servicemonitors.monitoring.coreos.com"
+	PrometheusRuleCrdName  = "prometheusrules.monitoring.coreos.com"
+	HppCrdName             = "hostpathprovisioners.hostpathprovisioner.kubevirt.io"
+	NodeMaintenanceCrdName = "nodemaintenances.nodemaintenance.kubevirt.io"
+)
+
+// capableOperand deploys an operand only if the cluster has all the capabilities and the CRDs that the operand
+// requires. The requirements are checked on each reconciliation, so the operand is deployed once its APIs become
+// available, and is skipped if they are removed.
+type capableOperand struct {
+	Operand
+	ci           hcoutil.ClusterInfo
+	capabilities []hcoutil.Capability
+	crds         *crdAvailability
+	crdNames     []string
+	// get the resource of the operand, to report it as skipped
+	getResource func(hc *hcov1beta1.HyperConverged) client.Object
+}
+
+func newCapableOperand(operand Operand, ci hcoutil.ClusterInfo, getResource func(hc *hcov1beta1.HyperConverged) client.Object, capabilities ...hcoutil.Capability) *capableOperand {
+	return &capableOperand{
+		Operand:      operand,
+		ci:           ci,
+		capabilities: capabilities,
+		getResource:  getResource,
+	}
+}
+
+// requireCRDs adds the CRDs that the operand requires
+func (o *capableOperand) requireCRDs(crds *crdAvailability, names ...string) *capableOperand {
+	o.crds = crds
+	o.crdNames = names
+	return o
+}
+
+func (o *capableOperand) isCapable() bool {
+	return hasCapabilities(o.ci, o.capabilities...) && (o.crds == nil || o.crds.isAvailable(o.crdNames...))
+}
+
+func (o *capableOperand) ensure(req *common.HcoRequest) *EnsureResult {
+	if o.isCapable() {
+		return o.Operand.ensure(req)
+	}
+
+	resource := o.getResource(req.Instance)
+	return NewEnsureResult(resource).SetName(resource.GetName()).SetSkipped().SetUpgradeDone(req.ComponentUpgradeInProgress)
+}
+
+func (o *capableOperand) preview(req *common.HcoRequest, hc *hcov1beta1.HyperConverged) (*OperandPreview, error) {
+	if o.isCapable() {
+		return o.Operand.preview(req, hc)
+	}
+
+	return nil, nil
+}
+
+type capabilityChecker interface {
+	HasCapability(capability hcoutil.Capability) bool
+}
+
+// staticCapabilities is a fixed set of capabilities, for rendering the operands without accessing the cluster
+type staticCapabilities []hcoutil.Capability
+
+func (c staticCapabilities) HasCapability(capability hcoutil.Capability) bool {
+	for _, known := range c {
+		if known == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCapabilities returns true if the cluster has all the capabilities
+func hasCapabilities(checker capabilityChecker, capabilities ...hcoutil.Capability) bool {
+	for _, capability := range capabilities {
+		if !checker.HasCapability(capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// crdAvailability is the availability of the optional CRDs, as reported by the controller. A CRD is considered
+// available until it is reported otherwise; then its operands are skipped, instead of failing the reconciliation.
+type crdAvailability struct {
+	lock        sync.RWMutex
+	unavailable map[string]bool
+}
+
+func newCRDAvailability() *crdAvailability {
+	return &crdAvailability{unavailable: make(map[string]bool)}
+}
+
+func (a *crdAvailability) set(available map[string]bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for name, isAvailable := range available {
+		a.unavailable[name] = !isAvailable
+	}
+}
+
+func (a *crdAvailability) isAvailable(names ...string) bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	for _, name := range names {
+		if a.unavailable[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -23,6 +23,7 @@ import (
 
 const (
 	cdiRoleName                   = "hco.kubevirt.io:config-reader"
+	storageConfigMapName          = "kubevirt-storage-class-defaults"
 	HonorWaitForFirstConsumerGate = "HonorWaitForFirstConsumer"
 )
 
@@ -157,7 +158,7 @@ func NewCDI(hc *hcov1beta1.HyperConverged, opts ...string) (*cdiv1beta1.CDI, err
 	cdi := NewCDIWithNameOnly(hc, opts...)
 	cdi.Spec = spec
 
-	if err := applyPatchToSpec(hc, common.JSONPatchCDIAnnotationName, cdi); err != nil {
+	if err := applyPatchAnnotations(hc, cdiPatchTarget, cdi); err != nil {
 		return nil, err
 	}
 
@@ -247,7 +248,7 @@ func NewKubeVirtStorageRoleForCR(cr *hcov1beta1.HyperConverged, namespace string
 			{
 				APIGroups:     []string{""},
 				Resources:     []string{"configmaps"},
-				ResourceNames: []string{"kubevirt-storage-class-defaults"},
+				ResourceNames: []string{storageConfigMapName},
 				Verbs:         []string{"get", "watch", "list"},
 			},
 		},
@@ -301,7 +302,7 @@ func newStorageConfigHandler(Client client.Client, Scheme *runtime.Scheme) *stor
 type storageConfigHooks struct{}
 
 func (h storageConfigHooks) getFullCr(hc *hcov1beta1.HyperConverged) (client.Object, error) {
-	return NewKubeVirtStorageConfigForCR(hc, hc.Namespace), nil
+	return newPatchedConfigMap(hc, storageConfigPatchTarget, NewKubeVirtStorageConfigForCR(hc, hc.Namespace))
 }
 func (h storageConfigHooks) getEmptyCr() client.Object                               { return &corev1.ConfigMap{} }
 func (h storageConfigHooks) validate() error                                         { return nil }
@@ -329,6 +330,10 @@ func (h *storageConfigHooks) updateCr(req *common.HcoRequest, Client client.Clie
 		}
 	}
 
+	if updatePatchedConfigMapData(found, storageConfig, NewKubeVirtStorageConfigForCR(req.Instance, found.Namespace)) {
+		needsUpdate = true
+	}
+
 	if !reflect.DeepEqual(found.Labels, storageConfig.Labels) {
 		util.DeepCopyLabels(&storageConfig.ObjectMeta, &found.ObjectMeta)
 		needsUpdate = true
@@ -356,7 +361,7 @@ func NewKubeVirtStorageConfigForCR(cr *hcov1beta1.HyperConverged, namespace stri
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kubevirt-storage-class-defaults",
+			Name:      storageConfigMapName,
 			Labels:    getLabels(cr, hcoutil.AppComponentStorage),
 			Namespace: namespace,
 		},
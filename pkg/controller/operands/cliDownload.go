@@ -21,6 +21,11 @@ type CLIDownloadHandler genericOperand
 func (h CLIDownloadHandler) Ensure(req *common.HcoRequest) error {
 	ccd := NewConsoleCLIDownload(req.Instance)
 
+	if !req.Instance.Spec.Components.IsCLIDownloadsEnabled() {
+		_, err := removeResource(h.Client, h.Scheme, req, ccd)
+		return err
+	}
+
 	found := NewConsoleCLIDownload(req.Instance)
 	err := hcoutil.EnsureCreated(req.Ctx, h.Client, found, req.Logger)
 	if err != nil {
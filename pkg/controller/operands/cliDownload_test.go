@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
@@ -168,5 +169,19 @@ var _ = Describe("CLI Download", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(Equal(fakeErr))
 		})
+
+		It("should remove the ConsoleCLIDownload if the CLI downloads are disabled", func() {
+			disabled := false
+			hco.Spec.Components = &hcov1beta1.HyperConvergedComponents{CLIDownloads: &disabled}
+			expectedResource := NewConsoleCLIDownload(hco)
+			cl := commonTestUtils.InitClient([]runtime.Object{expectedResource})
+			handler := &CLIDownloadHandler{Client: cl, Scheme: commonTestUtils.GetScheme()}
+			err := handler.Ensure(req)
+			Expect(err).To(BeNil())
+
+			foundResource := &consolev1.ConsoleCLIDownload{}
+			err = cl.Get(context.TODO(), client.ObjectKeyFromObject(expectedResource), foundResource)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
 	})
 })
@@ -2,6 +2,7 @@ package operands
 
 import (
 	"fmt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"strings"
 )
@@ -10,13 +11,20 @@ type EnsureResult struct {
 	Updated     bool
 	Overwritten bool
 	Created     bool
+	Deleted     bool
 	UpgradeDone bool
+	Skipped     bool
 	Err         error
 	Type        string
 	Name        string
 }
 
 func NewEnsureResult(resource runtime.Object) *EnsureResult {
+	// unstructured resources are identified by their kind
+	if u, ok := resource.(*unstructured.Unstructured); ok {
+		return &EnsureResult{Type: u.GetKind()}
+	}
+
 	t := fmt.Sprintf("%T", resource)
 	p := strings.LastIndex(t, ".")
 	return &EnsureResult{Type: t[p+1:]}
@@ -32,6 +40,11 @@ func (r *EnsureResult) SetCreated() *EnsureResult {
 	return r
 }
 
+func (r *EnsureResult) SetDeleted(deleted bool) *EnsureResult {
+	r.Deleted = deleted
+	return r
+}
+
 func (r *EnsureResult) SetUpdated() *EnsureResult {
 	r.Updated = true
 	return r
@@ -47,6 +60,12 @@ func (r *EnsureResult) SetUpgradeDone(upgradeDone bool) *EnsureResult {
 	return r
 }
 
+// SetSkipped marks an operand that is not deployed, because the cluster does not support it
+func (r *EnsureResult) SetSkipped() *EnsureResult {
+	r.Skipped = true
+	return r
+}
+
 func (r *EnsureResult) SetName(name string) *EnsureResult {
 	r.Name = name
 	return r
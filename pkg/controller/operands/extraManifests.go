@@ -0,0 +1,415 @@
+package operands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/go-logr/logr"
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	extraManifestsLocationVarName = "EXTRA_MANIFESTS_LOCATION"
+	defaultExtraManifestsLocation = "./extraManifests"
+
+	// ExtraManifestsConfigMapName is the name of the optional ConfigMap, in the namespace of the HyperConverged CR, that
+	// holds user supplied extra manifests. Each data entry may contain several YAML documents.
+	ExtraManifestsConfigMapName = "hco-extra-manifests"
+)
+
+// the namespace where the console looks for the dashboards, that are ConfigMaps with the
+// console.openshift.io/dashboard label
+const consoleConfigManagedNamespace = "openshift-config-managed"
+
+// extraManifestKind describes a kind that can be deployed as an extra manifest
+type extraManifestKind struct {
+	namespaced bool
+	// namespaced extra manifests are deployed in the namespace of the HyperConverged CR, unless they explicitly set
+	// one of these namespaces
+	namespaces []string
+}
+
+// extraManifestKinds is the allow list of the kinds that can be deployed as extra manifests
+var extraManifestKinds = map[schema.GroupKind]extraManifestKind{
+	{Group: "", Kind: "ConfigMap"}:                             {namespaced: true, namespaces: []string{consoleConfigManagedNamespace}},
+	{Group: "monitoring.coreos.com", Kind: "PrometheusRule"}:   {namespaced: true},
+	{Group: "console.openshift.io", Kind: "ConsoleYAMLSample"}: {namespaced: false},
+	// the templates of the openshift namespace are the ones that are shown in the catalog
+	{Group: "template.openshift.io", Kind: "Template"}: {namespaced: true, namespaces: []string{hcoutil.OpenshiftNamespace}},
+}
+
+// extraManifestReservedNames are the names, by kind, of the resources that HCO deploys in its namespace; extra
+// manifests can't use them.
+var extraManifestReservedNames = map[schema.GroupKind]map[string]bool{
+	{Group: "", Kind: "ConfigMap"}: {
+		KubeVirtConfigMapName:       true,
+		storageConfigMapName:        true,
+		imsConfigMapName:            true,
+		PreviewConfigMapName:        true,
+		hcoutil.AuditConfigMapName:  true,
+		ExtraManifestsConfigMapName: true,
+	},
+}
+
+// the top level fields of an extra manifest that are not reconciled
+var extraManifestUnmanagedFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+	"status":     true,
+}
+
+// extraManifestsLoader reads the extra manifests from the image directory and from the user supplied ConfigMap, and
+// generates an operand for each one of them. The manifests that are removed from their source are pruned by the
+// orphan sweeper.
+type extraManifestsLoader struct {
+	client       client.Client
+	scheme       *runtime.Scheme
+	eventEmitter hcoutil.EventEmitter
+	// the manifests of the image directory; read once, on the first reconciliation
+	dirManifests []*unstructured.Unstructured
+}
+
+func newExtraManifestsLoader(client client.Client, scheme *runtime.Scheme, eventEmitter hcoutil.EventEmitter) *extraManifestsLoader {
+	return &extraManifestsLoader{
+		client:       client,
+		scheme:       scheme,
+		eventEmitter: eventEmitter,
+	}
+}
+
+func getExtraManifestsDirPath() string {
+	filesLocation := os.Getenv(extraManifestsLocationVarName)
+	if filesLocation == "" {
+		return defaultExtraManifestsLocation
+	}
+
+	return filesLocation
+}
+
+// loadDir reads the extra manifests from the image directory. A missing directory is not an error.
+func (l *extraManifestsLoader) loadDir(logger log.Logger) error {
+	filesLocation := getExtraManifestsDirPath()
+
+	if err := validateQuickstartDir(filesLocation); err != nil {
+		return errors.Unwrap(err)
+	}
+
+	var manifests []*unstructured.Unstructured
+	err := filepath.Walk(filesLocation, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !(strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml")) {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			logger.Error(err, "Can't open the extra manifest file", "file name", path)
+			return err
+		}
+		defer file.Close()
+
+		fileManifests, err := hcoutil.ReadManifests(file)
+		if err != nil {
+			logger.Error(err, "Can't read the extra manifests from the yaml file", "file name", path)
+			return nil
+		}
+		manifests = append(manifests, fileManifests...)
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	l.dirManifests = manifests
+	return nil
+}
+
+// getHandlers returns an operand for each one of the valid extra manifests
+func (l *extraManifestsLoader) getHandlers(req *common.HcoRequest) []Operand {
+	manifests := l.getManifests(req)
+
+	handlers := make([]Operand, 0, len(manifests))
+	for _, manifest := range manifests {
+		handlers = append(handlers, newExtraManifestHandler(l.client, l.scheme, l.eventEmitter, manifest))
+	}
+
+	return handlers
+}
+
+// getManifests returns the valid extra manifests, with the HCO labels and namespace. The manifests of the image
+// directory come first; a manifest of the ConfigMap with the same kind and name as a previous manifest is ignored.
+func (l *extraManifestsLoader) getManifests(req *common.HcoRequest) []*unstructured.Unstructured {
+	manifests := l.dirManifests
+
+	cmManifests, err := l.loadConfigMap(req)
+	if err != nil {
+		req.Logger.Error(err, "can't read the extra manifests ConfigMap", "name", ExtraManifestsConfigMapName)
+	}
+	manifests = append(manifests[:len(manifests):len(manifests)], cmManifests...)
+
+	required := make([]*unstructured.Unstructured, 0, len(manifests))
+	deployed := make(map[string]bool, len(manifests))
+	for _, manifest := range manifests {
+		prepared, err := prepareExtraManifest(req.Instance, manifest)
+		if err == nil {
+			key := renderedResourceKey(prepared.GetKind(), prepared.GetName())
+			if deployed[key] {
+				err = errors.New("duplicated manifest")
+			} else {
+				deployed[key] = true
+			}
+		}
+
+		if err != nil {
+			req.Logger.Error(err, "ignoring an invalid extra manifest", "kind", manifest.GetKind(), "name", manifest.GetName())
+			l.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, "InvalidExtraManifest",
+				fmt.Sprintf("Ignoring the extra manifest %s %s: %v", manifest.GetKind(), manifest.GetName(), err))
+			continue
+		}
+
+		required = append(required, prepared)
+	}
+
+	return required
+}
+
+// loadConfigMap reads the extra manifests from the user supplied ConfigMap. A missing ConfigMap is not an error.
+func (l *extraManifestsLoader) loadConfigMap(req *common.HcoRequest) ([]*unstructured.Unstructured, error) {
+	cm := &corev1.ConfigMap{}
+	err := l.client.Get(req.Ctx, client.ObjectKey{Namespace: req.Instance.Namespace, Name: ExtraManifestsConfigMapName}, cm)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var manifests []*unstructured.Unstructured
+	var wrongEntries []string
+	for _, key := range keys {
+		entryManifests, err := hcoutil.ReadManifests(strings.NewReader(cm.Data[key]))
+		if err != nil {
+			wrongEntries = append(wrongEntries, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		manifests = append(manifests, entryManifests...)
+	}
+
+	if len(wrongEntries) > 0 {
+		return manifests, fmt.Errorf("can't read the entries %s", strings.Join(wrongEntries, "; "))
+	}
+
+	return manifests, nil
+}
+
+// prepareExtraManifest validates an extra manifest, and returns a copy of it with the HCO labels and namespace
+func prepareExtraManifest(hc *hcov1beta1.HyperConverged, manifest *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	gk := manifest.GroupVersionKind().GroupKind()
+	kind, allowed := extraManifestKinds[gk]
+	if !allowed {
+		return nil, fmt.Errorf("the %s kind is not supported", gk)
+	}
+
+	if manifest.GetName() == "" {
+		return nil, errors.New("missing name")
+	}
+
+	required := manifest.DeepCopy()
+	if kind.namespaced {
+		namespace, err := getExtraManifestNamespace(hc, kind, required.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+		if namespace == hc.Namespace && extraManifestReservedNames[gk][required.GetName()] {
+			return nil, errors.New("the name is reserved for a resource of HCO")
+		}
+		required.SetNamespace(namespace)
+	} else {
+		required.SetNamespace("")
+	}
+
+	labels := required.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range getLabels(hc, hcoutil.AppComponentDeployment) {
+		labels[k] = v
+	}
+	required.SetLabels(labels)
+
+	required.SetResourceVersion("")
+	required.SetUID("")
+	required.SetOwnerReferences(nil)
+	unstructured.RemoveNestedField(required.Object, "status")
+
+	return required, nil
+}
+
+// getExtraManifestNamespace returns the namespace of a namespaced extra manifest: the namespace of the HyperConverged
+// CR if the manifest does not set any, or one of the namespaces that are allowed for its kind.
+func getExtraManifestNamespace(hc *hcov1beta1.HyperConverged, kind extraManifestKind, namespace string) (string, error) {
+	if namespace == "" || namespace == hc.Namespace {
+		return hc.Namespace, nil
+	}
+
+	for _, allowed := range kind.namespaces {
+		if namespace == allowed {
+			return namespace, nil
+		}
+	}
+
+	allowed := append([]string{hc.Namespace}, kind.namespaces...)
+	return "", fmt.Errorf("the namespace %s is not supported; supported namespaces: %s", namespace, strings.Join(allowed, ", "))
+}
+
+// extraManifestHandler reconciles a single extra manifest
+type extraManifestHandler struct {
+	genericOperand
+	eventEmitter hcoutil.EventEmitter
+}
+
+func newExtraManifestHandler(Client client.Client, Scheme *runtime.Scheme, eventEmitter hcoutil.EventEmitter, required *unstructured.Unstructured) *extraManifestHandler {
+	return &extraManifestHandler{
+		genericOperand: genericOperand{
+			Client: Client,
+			Scheme: Scheme,
+			crType: required.GetKind(),
+			isCr:   false,
+			hooks:  &extraManifestHooks{required: required},
+		},
+		eventEmitter: eventEmitter,
+	}
+}
+
+// ensure deploys the extra manifest. A failure to deploy a user supplied manifest does not fail the reconciliation of
+// the other operands; it is only reported.
+func (h *extraManifestHandler) ensure(req *common.HcoRequest) *EnsureResult {
+	res := h.genericOperand.ensure(req)
+	if res.Err == nil {
+		return res
+	}
+
+	if meta.IsNoMatchError(res.Err) {
+		// the API of this kind is not installed in the cluster
+		req.Logger.Info("can't deploy an extra manifest; the kind is not supported by the cluster", "kind", h.crType, "name", res.Name)
+		return &EnsureResult{Type: res.Type, UpgradeDone: req.ComponentUpgradeInProgress}
+	}
+
+	req.Logger.Error(res.Err, "failed to deploy an extra manifest", "kind", h.crType, "name", res.Name)
+	h.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, "ExtraManifestFailed",
+		fmt.Sprintf("Failed to deploy the extra manifest %s %s: %v", h.crType, res.Name, res.Err))
+
+	// keep the name, so an existing resource is not removed as an orphan
+	return &EnsureResult{Type: res.Type, Name: res.Name, UpgradeDone: req.ComponentUpgradeInProgress}
+}
+
+type extraManifestHooks struct {
+	required *unstructured.Unstructured
+}
+
+func (h extraManifestHooks) getFullCr(_ *hcov1beta1.HyperConverged) (client.Object, error) {
+	return h.required.DeepCopy(), nil
+}
+
+func (h extraManifestHooks) getEmptyCr() client.Object {
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(h.required.GroupVersionKind())
+	return found
+}
+
+func (h extraManifestHooks) validate() error                                        { return nil }
+func (h extraManifestHooks) postFound(_ *common.HcoRequest, _ runtime.Object) error { return nil }
+func (h extraManifestHooks) getConditions(_ runtime.Object) []conditionsv1.Condition {
+	return nil
+}
+func (h extraManifestHooks) checkComponentVersion(_ runtime.Object) bool { return true }
+func (h extraManifestHooks) getObjectMeta(cr runtime.Object) *metav1.ObjectMeta {
+	obj := cr.(*unstructured.Unstructured)
+	return &metav1.ObjectMeta{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		Labels:          obj.GetLabels(),
+		OwnerReferences: obj.GetOwnerReferences(),
+	}
+}
+func (h extraManifestHooks) reset() { /* no implementation */ }
+
+// updateCr reverts any change of the manifest content and of its labels. Labels and annotations that were added to the
+// resource are kept.
+func (h extraManifestHooks) updateCr(req *common.HcoRequest, Client client.Client, exists runtime.Object, _ runtime.Object) (bool, bool, error) {
+	found, ok := exists.(*unstructured.Unstructured)
+	if !ok {
+		return false, false, errors.New("can't convert to unstructured")
+	}
+
+	needsUpdate := false
+	for field, value := range h.required.Object {
+		if !extraManifestUnmanagedFields[field] && !reflect.DeepEqual(found.Object[field], value) {
+			found.Object[field] = runtime.DeepCopyJSONValue(value)
+			needsUpdate = true
+		}
+	}
+
+	for field := range found.Object {
+		if _, required := h.required.Object[field]; !required && !extraManifestUnmanagedFields[field] {
+			delete(found.Object, field)
+			needsUpdate = true
+		}
+	}
+
+	labels := found.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range h.required.GetLabels() {
+		if labels[k] != v {
+			labels[k] = v
+			needsUpdate = true
+		}
+	}
+
+	if !needsUpdate {
+		return false, false, nil
+	}
+
+	if req.HCOTriggered {
+		req.Logger.Info("Updating an existing extra manifest to its required content", "kind", h.required.GetKind(), "name", h.required.GetName())
+	} else {
+		req.Logger.Info("Reconciling an externally updated extra manifest to its required content", "kind", h.required.GetKind(), "name", h.required.GetName())
+	}
+
+	found.SetLabels(labels)
+	if err := Client.Update(req.Ctx, found); err != nil {
+		return false, false, err
+	}
+
+	return true, !req.HCOTriggered, nil
+}
@@ -0,0 +1,417 @@
+package operands
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	consolev1 "github.com/openshift/api/console/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	extraConfigMapYaml = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra-config-map
+  labels:
+    console.openshift.io/dashboard: "true"
+data:
+  key: value
+`
+	extraYamlSampleYaml = `apiVersion: console.openshift.io/v1
+kind: ConsoleYAMLSample
+metadata:
+  name: extra-yaml-sample
+  namespace: some-namespace
+spec:
+  title: extra sample
+  description: an extra sample
+  targetResource:
+    apiVersion: kubevirt.io/v1
+    kind: VirtualMachine
+  yaml: |
+    apiVersion: kubevirt.io/v1
+    kind: VirtualMachine
+`
+	extraPrometheusRuleYaml = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: extra-prometheus-rule
+spec:
+  groups:
+  - name: extra.rules
+    rules:
+    - record: extra:metric
+      expr: vector(1)
+`
+	extraTemplateYaml = `apiVersion: template.openshift.io/v1
+kind: Template
+metadata:
+  name: extra-template
+objects: []
+`
+	extraDeploymentYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: not-allowed
+`
+)
+
+var _ = Describe("Extra manifests", func() {
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+	var eventEmitter *commonTestUtils.EventEmitterMock
+
+	logger := zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)).WithName("extraManifests_test")
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		req = commonTestUtils.NewReq(hco)
+		eventEmitter = commonTestUtils.NewEventEmitterMock()
+	})
+
+	newExtraManifestsConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ExtraManifestsConfigMapName,
+				Namespace: hco.Namespace,
+			},
+			Data: data,
+		}
+	}
+
+	newHandler := func(cl client.Client) *OperandHandler {
+		return &OperandHandler{
+			client:         cl,
+			eventEmitter:   eventEmitter,
+			extraManifests: newExtraManifestsLoader(cl, commonTestUtils.GetScheme(), eventEmitter),
+		}
+	}
+
+	getConfigMap := func(cl client.Client, name string) (*corev1.ConfigMap, error) {
+		cm := &corev1.ConfigMap{}
+		err := cl.Get(context.TODO(), client.ObjectKey{Namespace: hco.Namespace, Name: name}, cm)
+		return cm, err
+	}
+
+	Context("ReadManifests", func() {
+		It("should read all the documents of the yaml", func() {
+			manifests, err := hcoutil.ReadManifests(strings.NewReader(extraConfigMapYaml + "---\n---\n" + extraPrometheusRuleYaml))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifests).To(HaveLen(2))
+			Expect(manifests[0].GetKind()).To(Equal("ConfigMap"))
+			Expect(manifests[1].GetKind()).To(Equal("PrometheusRule"))
+		})
+
+		It("should fail for a wrong yaml", func() {
+			_, err := hcoutil.ReadManifests(strings.NewReader("kind: [ConfigMap"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("prepareExtraManifest", func() {
+		read := func(content string) *unstructured.Unstructured {
+			manifests, err := hcoutil.ReadManifests(strings.NewReader(content))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifests).To(HaveLen(1))
+			return manifests[0]
+		}
+
+		It("should set the HCO namespace and labels of a namespaced manifest", func() {
+			prepared, err := prepareExtraManifest(hco, read(extraConfigMapYaml))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prepared.GetNamespace()).To(Equal(hco.Namespace))
+			Expect(prepared.GetLabels()).To(HaveKeyWithValue("console.openshift.io/dashboard", "true"))
+			Expect(prepared.GetLabels()).To(HaveKeyWithValue(hcoutil.AppLabel, hco.Name))
+			Expect(prepared.GetLabels()).To(HaveKeyWithValue(hcoutil.AppLabelManagedBy, hcoutil.OperatorName))
+		})
+
+		It("should drop the namespace of a cluster scoped manifest", func() {
+			prepared, err := prepareExtraManifest(hco, read(extraYamlSampleYaml))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prepared.GetNamespace()).To(BeEmpty())
+		})
+
+		It("should reject a kind that is not supported", func() {
+			_, err := prepareExtraManifest(hco, read(extraDeploymentYaml))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not supported"))
+		})
+
+		It("should reject a namespaced manifest of another namespace", func() {
+			manifest := read(extraConfigMapYaml)
+			manifest.SetNamespace("other-namespace")
+			_, err := prepareExtraManifest(hco, manifest)
+			Expect(err).To(HaveOccurred())
+		})
+
+		DescribeTable("should accept the explicit namespaces that are allowed for the kind",
+			func(content, namespace string) {
+				manifest := read(content)
+				manifest.SetNamespace(namespace)
+				prepared, err := prepareExtraManifest(hco, manifest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(prepared.GetNamespace()).To(Equal(namespace))
+			},
+			Entry("ConfigMap in the HCO namespace", extraConfigMapYaml, commonTestUtils.Namespace),
+			Entry("dashboard in openshift-config-managed", extraConfigMapYaml, "openshift-config-managed"),
+			Entry("Template in openshift", extraTemplateYaml, "openshift"),
+		)
+
+		DescribeTable("should reject the namespaces that are not allowed for the kind",
+			func(content, namespace string) {
+				manifest := read(content)
+				manifest.SetNamespace(namespace)
+				_, err := prepareExtraManifest(hco, manifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is not supported"))
+			},
+			Entry("ConfigMap in openshift", extraConfigMapYaml, "openshift"),
+			Entry("PrometheusRule in openshift-config-managed", extraPrometheusRuleYaml, "openshift-config-managed"),
+			Entry("Template in openshift-config-managed", extraTemplateYaml, "openshift-config-managed"),
+		)
+
+		DescribeTable("should reject the names of the ConfigMaps that HCO deploys",
+			func(name string) {
+				manifest := read(extraConfigMapYaml)
+				manifest.SetName(name)
+				_, err := prepareExtraManifest(hco, manifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("reserved"))
+			},
+			Entry("kubevirt-config", "kubevirt-config"),
+			Entry("kubevirt-storage-class-defaults", "kubevirt-storage-class-defaults"),
+			Entry("v2v-vmware", "v2v-vmware"),
+			Entry("the preview ConfigMap", PreviewConfigMapName),
+			Entry("the audit ConfigMap", hcoutil.AuditConfigMapName),
+			Entry("the extra manifests ConfigMap", ExtraManifestsConfigMapName),
+		)
+
+		It("should accept the name of an HCO ConfigMap in another namespace", func() {
+			manifest := read(extraConfigMapYaml)
+			manifest.SetName(KubeVirtConfigMapName)
+			manifest.SetNamespace("openshift-config-managed")
+			_, err := prepareExtraManifest(hco, manifest)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("image directory", func() {
+		It("should use env var to override the directory location", func() {
+			dir, err := ioutil.TempDir("", "extraManifests")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			Expect(ioutil.WriteFile(path.Join(dir, "manifests.yaml"), []byte(extraConfigMapYaml+"---\n"+extraYamlSampleYaml), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(path.Join(dir, "wrong.yaml"), []byte("kind: [ConfigMap"), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(path.Join(dir, "not-a-manifest.txt"), []byte(extraPrometheusRuleYaml), 0644)).To(Succeed())
+
+			_ = os.Setenv(extraManifestsLocationVarName, dir)
+			defer os.Unsetenv(extraManifestsLocationVarName)
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco})
+			handler := newHandler(cl)
+			Expect(handler.extraManifests.loadDir(logger)).To(Succeed())
+			Expect(handler.extraManifests.dirManifests).To(HaveLen(2))
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			cm, err := getConfigMap(cl, "extra-config-map")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKeyWithValue("key", "value"))
+
+			sample := &consolev1.ConsoleYAMLSample{}
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Name: "extra-yaml-sample"}, sample)).To(Succeed())
+			Expect(sample.Spec.Title).To(BeEquivalentTo("extra sample"))
+			Expect(sample.Labels).To(HaveKeyWithValue(hcoutil.AppLabel, hco.Name))
+		})
+
+		It("should not fail if the directory does not exist", func() {
+			_ = os.Setenv(extraManifestsLocationVarName, "/not/existing/dir")
+			defer os.Unsetenv(extraManifestsLocationVarName)
+
+			loader := newExtraManifestsLoader(commonTestUtils.InitClient([]runtime.Object{hco}), commonTestUtils.GetScheme(), eventEmitter)
+			Expect(loader.loadDir(logger)).To(Succeed())
+			Expect(loader.dirManifests).To(BeEmpty())
+		})
+	})
+
+	Context("ConfigMap", func() {
+		It("should deploy the manifests of the ConfigMap and ignore the invalid ones", func() {
+			extraCm := newExtraManifestsConfigMap(map[string]string{
+				"manifests.yaml":  extraConfigMapYaml + "---\n" + extraPrometheusRuleYaml,
+				"deployment.yaml": extraDeploymentYaml,
+			})
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, extraCm})
+
+			Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+			_, err := getConfigMap(cl, "extra-config-map")
+			Expect(err).ToNot(HaveOccurred())
+
+			rule := &unstructured.Unstructured{}
+			rule.SetGroupVersionKind(schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"})
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: hco.Namespace, Name: "extra-prometheus-rule"}, rule)).To(Succeed())
+
+			Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{
+				{EventType: corev1.EventTypeWarning, Reason: "InvalidExtraManifest", Msg: "Ignoring the extra manifest Deployment not-allowed: the Deployment.apps kind is not supported"},
+			})).To(BeTrue())
+		})
+
+		It("should deploy a dashboard in openshift-config-managed and a template in openshift", func() {
+			dashboard := strings.Replace(extraConfigMapYaml, "  name: extra-config-map\n", "  name: extra-config-map\n  namespace: openshift-config-managed\n", 1)
+			template := strings.Replace(extraTemplateYaml, "  name: extra-template\n", "  name: extra-template\n  namespace: openshift\n", 1)
+			extraCm := newExtraManifestsConfigMap(map[string]string{"manifests.yaml": dashboard + "---\n" + template})
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, extraCm})
+			handler := newHandler(cl)
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			dashboardKey := client.ObjectKey{Namespace: "openshift-config-managed", Name: "extra-config-map"}
+			Expect(cl.Get(context.TODO(), dashboardKey, cm)).To(Succeed())
+			Expect(cm.Labels).To(HaveKeyWithValue("console.openshift.io/dashboard", "true"))
+
+			tmpl := &unstructured.Unstructured{}
+			tmpl.SetGroupVersionKind(schema.GroupVersionKind{Group: "template.openshift.io", Version: "v1", Kind: "Template"})
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "openshift", Name: "extra-template"}, tmpl)).To(Succeed())
+
+		})
+
+		It("should remove a dashboard that was removed from the ConfigMap", func() {
+			dashboard := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "old-dashboard",
+					Namespace: "openshift-config-managed",
+					Labels:    getLabels(hco, hcoutil.AppComponentDeployment),
+				},
+			}
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, dashboard})
+
+			Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+			err := cl.Get(context.TODO(), client.ObjectKeyFromObject(dashboard), &corev1.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should continue with the other manifests if a manifest can't be deployed", func() {
+			extraCm := newExtraManifestsConfigMap(map[string]string{
+				"manifests.yaml": extraConfigMapYaml + "---\n" + extraPrometheusRuleYaml,
+			})
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, extraCm})
+			cl.InitiateCreateErrors(func(obj client.Object) error {
+				if obj.GetName() == "extra-config-map" {
+					return errors.New("fake create error")
+				}
+				return nil
+			})
+
+			Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+			_, err := getConfigMap(cl, "extra-config-map")
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			rule := &unstructured.Unstructured{}
+			rule.SetGroupVersionKind(schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"})
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: hco.Namespace, Name: "extra-prometheus-rule"}, rule)).To(Succeed())
+
+			Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{
+				{EventType: corev1.EventTypeWarning, Reason: "ExtraManifestFailed", Msg: "Failed to deploy the extra manifest ConfigMap extra-config-map: fake create error"},
+			})).To(BeTrue())
+		})
+
+		It("should not remove a deployed manifest that failed to update", func() {
+			extraCm := newExtraManifestsConfigMap(map[string]string{"cm.yaml": extraConfigMapYaml})
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, extraCm})
+			handler := newHandler(cl)
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			cm, err := getConfigMap(cl, "extra-config-map")
+			Expect(err).ToNot(HaveOccurred())
+			cm.Data["key"] = "modified"
+			Expect(cl.Update(context.TODO(), cm)).To(Succeed())
+
+			cl.InitiateUpdateErrors(func(obj client.Object) error {
+				if obj.GetName() == "extra-config-map" {
+					return errors.New("fake update error")
+				}
+				return nil
+			})
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			cm, err = getConfigMap(cl, "extra-config-map")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cm.Data).To(HaveKeyWithValue("key", "modified"))
+		})
+
+		It("should revert changes of a deployed manifest", func() {
+			extraCm := newExtraManifestsConfigMap(map[string]string{"cm.yaml": extraConfigMapYaml})
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, extraCm})
+			handler := newHandler(cl)
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			cm, err := getConfigMap(cl, "extra-config-map")
+			Expect(err).ToNot(HaveOccurred())
+			cm.Data["key"] = "modified"
+			cm.Data["other"] = "added"
+			cm.Labels[hcoutil.AppLabel] = "modified"
+			Expect(cl.Update(context.TODO(), cm)).To(Succeed())
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			cm, err = getConfigMap(cl, "extra-config-map")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cm.Data).To(Equal(map[string]string{"key": "value"}))
+			Expect(cm.Labels).To(HaveKeyWithValue(hcoutil.AppLabel, hco.Name))
+			Expect(cm.Labels).To(HaveKeyWithValue("console.openshift.io/dashboard", "true"))
+		})
+
+		It("should remove a manifest that was removed from the ConfigMap", func() {
+			extraCm := newExtraManifestsConfigMap(map[string]string{
+				"template.yaml": extraTemplateYaml,
+			})
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, extraCm})
+			handler := newHandler(cl)
+
+			template := &unstructured.Unstructured{}
+			template.SetGroupVersionKind(schema.GroupVersionKind{Group: "template.openshift.io", Version: "v1", Kind: "Template"})
+			templateKey := client.ObjectKey{Namespace: hco.Namespace, Name: "extra-template"}
+
+			Expect(handler.Ensure(req)).To(Succeed())
+			Expect(cl.Get(context.TODO(), templateKey, template)).To(Succeed())
+
+			delete(extraCm.Data, "template.yaml")
+			Expect(cl.Update(context.TODO(), extraCm)).To(Succeed())
+
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			err := cl.Get(context.TODO(), templateKey, template)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			// the extra manifests ConfigMap itself is not labeled by HCO, and so it is never swept
+			_, err = getConfigMap(cl, ExtraManifestsConfigMapName)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,440 @@
+package operands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	hppName            = "hostpath-provisioner"
+	hppKind            = "HostPathProvisioner"
+	hppProvisioner     = "kubevirt.io/hostpath-provisioner"
+	defaultStoragePool = hppName
+)
+
+// The HostPath Provisioner API is not vendored, so the HostPathProvisioner CR is handled as an unstructured object
+var hppGroupVersionKind = schema.GroupVersionKind{
+	Group:   "hostpathprovisioner.kubevirt.io",
+	Version: "v1beta1",
+	Kind:    hppKind,
+}
+
+// ***********  HostPath Provisioner Handlers  ***********
+
+// hppHandler handles a resource that is deployed only if the HyperConverged spec.hostPathProvisioner is set. If it's
+// not set, the resource is removed, in case it was deployed before.
+type hppHandler struct {
+	genericOperand
+	// the type name to report in the events; the HostPathProvisioner CR is unstructured, so its golang type name is
+	// meaningless.
+	resourceType string
+	// removes the resources of this handler that are no longer required.
+	removeStale func(h *hppHandler, req *common.HcoRequest, required client.Object) error
+	// get the resource to remove if the HostPath Provisioner is disabled
+	getResource func(hc *hcov1beta1.HyperConverged) client.Object
+}
+
+func newHppHandler(Client client.Client, Scheme *runtime.Scheme) *hppHandler {
+	return &hppHandler{
+		genericOperand: genericOperand{
+			Client:                 Client,
+			Scheme:                 Scheme,
+			crType:                 hppKind,
+			isCr:                   true,
+			removeExistingOwner:    false,
+			setControllerReference: false,
+			hooks:                  &hppHooks{},
+		},
+		resourceType: hppKind,
+		removeStale:  removeStaleHpp,
+		getResource: func(hc *hcov1beta1.HyperConverged) client.Object {
+			return NewHostPathProvisionerWithNameOnly(hc)
+		},
+	}
+}
+
+func newHppStorageClassHandler(Client client.Client, Scheme *runtime.Scheme) *hppHandler {
+	return &hppHandler{
+		genericOperand: genericOperand{
+			Client:                 Client,
+			Scheme:                 Scheme,
+			crType:                 "HPPStorageClass",
+			isCr:                   false,
+			removeExistingOwner:    false,
+			setControllerReference: false,
+			hooks:                  &hppStorageClassHooks{},
+		},
+		resourceType: "StorageClass",
+		removeStale:  removeStaleHppStorageClasses,
+		getResource: func(hc *hcov1beta1.HyperConverged) client.Object {
+			return NewHppStorageClass(hc)
+		},
+	}
+}
+
+func (h *hppHandler) ensure(req *common.HcoRequest) *EnsureResult {
+	var res *EnsureResult
+	var required client.Object
+
+	if req.Instance.Spec.HostPathProvisioner != nil {
+		res = h.genericOperand.ensure(req)
+		if res.Err != nil {
+			return res
+		}
+		// getFullCr is cached, and it was already called by ensure
+		required, _ = h.hooks.getFullCr(req.Instance)
+	} else {
+		res = &EnsureResult{UpgradeDone: req.ComponentUpgradeInProgress}
+	}
+	res.Type = h.resourceType
+
+	if err := h.removeStale(h, req, required); err != nil {
+		return res.Error(err)
+	}
+
+	return res
+}
+
+func (h *hppHandler) preview(req *common.HcoRequest, hc *hcov1beta1.HyperConverged) (*OperandPreview, error) {
+	if hc.Spec.HostPathProvisioner != nil {
+		return h.genericOperand.preview(req, hc)
+	}
+
+	return previewRemoval(req, h.Client, h.getResource(hc))
+}
+
+// removeStaleHpp removes the HostPathProvisioner CR if the HostPath Provisioner is disabled
+func removeStaleHpp(h *hppHandler, req *common.HcoRequest, required client.Object) error {
+	if required != nil {
+		return nil
+	}
+
+	_, err := removeResource(h.Client, h.Scheme, req, NewHostPathProvisionerWithNameOnly(req.Instance))
+	return err
+}
+
+// removeStaleHppStorageClasses removes the HostPath Provisioner StorageClasses that were created by HCO, but are not
+// required anymore; e.g. if the storage pool was renamed, or if the HostPath Provisioner was disabled.
+func removeStaleHppStorageClasses(h *hppHandler, req *common.HcoRequest, required client.Object) error {
+	scList := &storagev1.StorageClassList{}
+	err := h.Client.List(req.Ctx, scList, client.MatchingLabels{
+		hcoutil.AppLabel:          req.Instance.Name,
+		hcoutil.AppLabelManagedBy: hcoutil.OperatorName,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range scList.Items {
+		sc := &scList.Items[i]
+		if sc.Provisioner != hppProvisioner || (required != nil && sc.Name == required.GetName()) {
+			continue
+		}
+
+		if _, err := removeResource(h.Client, h.Scheme, req, sc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type hppHooks struct {
+	cache *unstructured.Unstructured
+}
+
+func (h *hppHooks) getFullCr(hc *hcov1beta1.HyperConverged) (client.Object, error) {
+	if h.cache == nil {
+		hpp, err := NewHostPathProvisioner(hc)
+		if err != nil {
+			return nil, err
+		}
+		h.cache = hpp
+	}
+	return h.cache, nil
+}
+func (h hppHooks) getEmptyCr() client.Object                              { return newUnstructuredHpp() }
+func (h hppHooks) validate() error                                        { return nil }
+func (h hppHooks) postFound(_ *common.HcoRequest, _ runtime.Object) error { return nil }
+func (h hppHooks) getConditions(cr runtime.Object) []conditionsv1.Condition {
+	status, err := getHppStatus(cr.(*unstructured.Unstructured))
+	if err != nil {
+		return nil
+	}
+	return status.Conditions
+}
+func (h hppHooks) checkComponentVersion(cr runtime.Object) bool {
+	status, err := getHppStatus(cr.(*unstructured.Unstructured))
+	if err != nil {
+		return false
+	}
+	return checkComponentVersion(hcoutil.HppoVersionEnvV, status.ObservedVersion)
+}
+func (h hppHooks) getObjectMeta(cr runtime.Object) *metav1.ObjectMeta {
+	hpp := cr.(*unstructured.Unstructured)
+	return &metav1.ObjectMeta{
+		Name:            hpp.GetName(),
+		Labels:          hpp.GetLabels(),
+		OwnerReferences: hpp.GetOwnerReferences(),
+	}
+}
+func (h *hppHooks) reset() {
+	h.cache = nil
+}
+
+// the HostPathProvisioner spec fields that are managed by HCO; the rest of the spec is left to the user
+var hppManagedSpecFields = []string{"pathConfig", "workload"}
+
+func (h *hppHooks) updateCr(req *common.HcoRequest, Client client.Client, exists runtime.Object, required runtime.Object) (bool, bool, error) {
+	hpp, ok1 := required.(*unstructured.Unstructured)
+	found, ok2 := exists.(*unstructured.Unstructured)
+	if !ok1 || !ok2 {
+		return false, false, errors.New("can't convert to HostPathProvisioner")
+	}
+
+	needsUpdate := !reflect.DeepEqual(found.GetLabels(), hpp.GetLabels())
+	for _, field := range hppManagedSpecFields {
+		requiredVal, _, _ := unstructured.NestedFieldCopy(hpp.Object, "spec", field)
+		foundVal, _, _ := unstructured.NestedFieldCopy(found.Object, "spec", field)
+		if !reflect.DeepEqual(foundVal, requiredVal) {
+			needsUpdate = true
+		}
+	}
+
+	if !needsUpdate {
+		return false, false, nil
+	}
+
+	if req.HCOTriggered {
+		req.Logger.Info("Updating existing HostPathProvisioner's Spec to new opinionated values")
+	} else {
+		req.Logger.Info("Reconciling an externally updated HostPathProvisioner's Spec to its opinionated values")
+	}
+
+	found.SetLabels(hpp.GetLabels())
+	for _, field := range hppManagedSpecFields {
+		if val, ok, _ := unstructured.NestedFieldCopy(hpp.Object, "spec", field); ok {
+			if err := unstructured.SetNestedField(found.Object, val, "spec", field); err != nil {
+				return false, false, err
+			}
+		} else {
+			unstructured.RemoveNestedField(found.Object, "spec", field)
+		}
+	}
+
+	err := Client.Update(req.Ctx, found)
+	if err != nil {
+		return false, false, err
+	}
+	return true, !req.HCOTriggered, nil
+}
+
+// hppStatus is the part of the HostPathProvisioner status that is used by HCO
+type hppStatus struct {
+	Conditions      []conditionsv1.Condition `json:"conditions,omitempty"`
+	ObservedVersion string                   `json:"observedVersion,omitempty"`
+}
+
+func getHppStatus(hpp *unstructured.Unstructured) (*hppStatus, error) {
+	status := &hppStatus{}
+	statusMap, found, err := unstructured.NestedMap(hpp.Object, "status")
+	if err != nil || !found {
+		return status, err
+	}
+
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(statusMap, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func newUnstructuredHpp() *unstructured.Unstructured {
+	hpp := &unstructured.Unstructured{}
+	hpp.SetGroupVersionKind(hppGroupVersionKind)
+	return hpp
+}
+
+// NewHostPathProvisionerWithNameOnly returns a HostPathProvisioner CR with no spec. The HostPathProvisioner CR is
+// cluster scoped.
+func NewHostPathProvisionerWithNameOnly(hc *hcov1beta1.HyperConverged) *unstructured.Unstructured {
+	hpp := newUnstructuredHpp()
+	hpp.SetName(hppName)
+	hpp.SetLabels(getLabels(hc, hcoutil.AppComponentStorage))
+	return hpp
+}
+
+// NewHostPathProvisioner returns the HostPathProvisioner CR that is required by the HyperConverged
+// spec.hostPathProvisioner. The node placement of the provisioner defaults to the workloads node placement.
+func NewHostPathProvisioner(hc *hcov1beta1.HyperConverged) (*unstructured.Unstructured, error) {
+	hpp := NewHostPathProvisionerWithNameOnly(hc)
+
+	config := hc.Spec.HostPathProvisioner
+	if config == nil {
+		return nil, errors.New("the HostPath Provisioner is not configured")
+	}
+
+	spec := map[string]interface{}{
+		"pathConfig": map[string]interface{}{
+			"path":            config.Path,
+			"useNamingPrefix": false,
+		},
+	}
+
+	placement := config.Workload
+	if placement == nil {
+		placement = hc.Spec.Workloads.NodePlacement
+	}
+
+	if placement != nil {
+		workload, err := runtime.DefaultUnstructuredConverter.ToUnstructured(placement.DeepCopy())
+		if err != nil {
+			return nil, err
+		}
+		spec["workload"] = workload
+	}
+
+	hpp.Object["spec"] = spec
+	return hpp, nil
+}
+
+type hppStorageClassHooks struct {
+	cache *storagev1.StorageClass
+}
+
+func (h *hppStorageClassHooks) getFullCr(hc *hcov1beta1.HyperConverged) (client.Object, error) {
+	if h.cache == nil {
+		h.cache = NewHppStorageClass(hc)
+	}
+	return h.cache, nil
+}
+func (h hppStorageClassHooks) getEmptyCr() client.Object                              { return &storagev1.StorageClass{} }
+func (h hppStorageClassHooks) validate() error                                        { return nil }
+func (h hppStorageClassHooks) postFound(_ *common.HcoRequest, _ runtime.Object) error { return nil }
+func (h hppStorageClassHooks) getConditions(_ runtime.Object) []conditionsv1.Condition {
+	return nil
+}
+func (h hppStorageClassHooks) checkComponentVersion(_ runtime.Object) bool { return true }
+func (h hppStorageClassHooks) getObjectMeta(cr runtime.Object) *metav1.ObjectMeta {
+	return &cr.(*storagev1.StorageClass).ObjectMeta
+}
+func (h *hppStorageClassHooks) reset() {
+	h.cache = nil
+}
+
+func (h *hppStorageClassHooks) updateCr(req *common.HcoRequest, Client client.Client, exists runtime.Object, required runtime.Object) (bool, bool, error) {
+	sc, ok1 := required.(*storagev1.StorageClass)
+	found, ok2 := exists.(*storagev1.StorageClass)
+	if !ok1 || !ok2 {
+		return false, false, errors.New("can't convert to a StorageClass")
+	}
+
+	// never modify a StorageClass with the same name, that was not created by HCO
+	if !isHcoStorageClass(found, req.Instance) {
+		msg := fmt.Sprintf("the %s StorageClass was not created by HCO; please set another spec.hostPathProvisioner.storagePool", found.Name)
+		req.Logger.Info("Existing StorageClass wasn't deployed by HCO, ignoring", "StorageClass", found.Name)
+		req.Conditions.SetStatusCondition(conditionsv1.Condition{
+			Type:    conditionsv1.ConditionDegraded,
+			Status:  corev1.ConditionTrue,
+			Reason:  "HPPStorageClassConflict",
+			Message: msg,
+		})
+		return false, false, nil
+	}
+
+	// The provisioner, the reclaim policy and the volume binding mode are immutable; the StorageClass must be
+	// replaced in order to modify them.
+	if found.Provisioner != sc.Provisioner ||
+		!reflect.DeepEqual(found.ReclaimPolicy, sc.ReclaimPolicy) ||
+		!reflect.DeepEqual(found.VolumeBindingMode, sc.VolumeBindingMode) {
+
+		req.Logger.Info("Replacing the HostPath Provisioner StorageClass with its opinionated values")
+		if err := Client.Delete(req.Ctx, found); err != nil {
+			return false, false, err
+		}
+
+		newSc := sc.DeepCopy()
+		newSc.ResourceVersion = ""
+		if err := Client.Create(req.Ctx, newSc); err != nil {
+			return false, false, err
+		}
+		return true, !req.HCOTriggered, nil
+	}
+
+	if !reflect.DeepEqual(found.Labels, sc.Labels) {
+		req.Logger.Info("Updating existing HostPath Provisioner StorageClass's labels")
+		hcoutil.DeepCopyLabels(&sc.ObjectMeta, &found.ObjectMeta)
+		err := Client.Update(req.Ctx, found)
+		if err != nil {
+			return false, false, err
+		}
+		return true, false, nil
+	}
+
+	return false, false, nil
+}
+
+// ValidateHppStoragePool rejects a HostPath Provisioner storage pool with the name of an existing StorageClass, that
+// was not created by HCO.
+func ValidateHppStoragePool(ctx context.Context, cli client.Client, hc *hcov1beta1.HyperConverged) error {
+	if hc.Spec.HostPathProvisioner == nil {
+		return nil
+	}
+
+	sc := NewHppStorageClass(hc)
+	found := &storagev1.StorageClass{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(sc), found); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !isHcoStorageClass(found, hc) {
+		return fmt.Errorf("the %s StorageClass already exists, and was not created by HCO; please set another spec.hostPathProvisioner.storagePool", sc.Name)
+	}
+
+	return nil
+}
+
+// isHcoStorageClass checks the app and the managed-by labels of a StorageClass, to tell if HCO created it.
+func isHcoStorageClass(sc *storagev1.StorageClass, hc *hcov1beta1.HyperConverged) bool {
+	required := getLabels(hc, hcoutil.AppComponentStorage)
+	return sc.Labels[hcoutil.AppLabel] == required[hcoutil.AppLabel] &&
+		sc.Labels[hcoutil.AppLabelManagedBy] == required[hcoutil.AppLabelManagedBy]
+}
+
+// NewHppStorageClass returns the StorageClass of the HostPath Provisioner storage pool
+func NewHppStorageClass(hc *hcov1beta1.HyperConverged) *storagev1.StorageClass {
+	name := defaultStoragePool
+	if hc.Spec.HostPathProvisioner != nil && hc.Spec.HostPathProvisioner.StoragePool != "" {
+		name = hc.Spec.HostPathProvisioner.StoragePool
+	}
+
+	reclaimPolicy := corev1.PersistentVolumeReclaimDelete
+	bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: getLabels(hc, hcoutil.AppComponentStorage),
+		},
+		Provisioner:       hppProvisioner,
+		ReclaimPolicy:     &reclaimPolicy,
+		VolumeBindingMode: &bindingMode,
+	}
+}
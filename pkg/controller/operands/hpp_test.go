@@ -0,0 +1,346 @@
+package operands
+
+import (
+	"context"
+	"os"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	"github.com/openshift/custom-resource-status/testlib"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/reference"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("HostPath Provisioner", func() {
+	const hppPath = "/var/hpvolumes"
+
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		hco.Spec.HostPathProvisioner = &hcov1beta1.HostPathProvisionerConfig{Path: hppPath}
+		req = commonTestUtils.NewReq(hco)
+	})
+
+	getHpp := func(cl client.Client) (*unstructured.Unstructured, error) {
+		found := newUnstructuredHpp()
+		err := cl.Get(context.TODO(), client.ObjectKey{Name: hppName}, found)
+		return found, err
+	}
+
+	Context("HostPathProvisioner CR", func() {
+		It("should create if not present", func() {
+			cl := commonTestUtils.InitClient([]runtime.Object{})
+			handler := newHppHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Created).To(BeTrue())
+			Expect(res.Type).To(Equal(hppKind))
+
+			found, err := getHpp(cl)
+			Expect(err).To(BeNil())
+			Expect(found.GetLabels()).To(HaveKeyWithValue(hcoutil.AppLabel, commonTestUtils.Name))
+			path, _, _ := unstructured.NestedString(found.Object, "spec", "pathConfig", "path")
+			Expect(path).To(Equal(hppPath))
+			_, hasWorkload, _ := unstructured.NestedMap(found.Object, "spec", "workload")
+			Expect(hasWorkload).To(BeFalse())
+		})
+
+		It("should not create if spec.hostPathProvisioner is not set", func() {
+			hco.Spec.HostPathProvisioner = nil
+			cl := commonTestUtils.InitClient([]runtime.Object{})
+			handler := newHppHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Created).To(BeFalse())
+
+			_, err := getHpp(cl)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should use the workloads node placement by default", func() {
+			hco.Spec.Workloads.NodePlacement = commonTestUtils.NewNodePlacement()
+
+			hpp, err := NewHostPathProvisioner(hco)
+			Expect(err).To(BeNil())
+			nodeSelector, _, _ := unstructured.NestedStringMap(hpp.Object, "spec", "workload", "nodeSelector")
+			Expect(nodeSelector).To(Equal(hco.Spec.Workloads.NodePlacement.NodeSelector))
+
+			hco.Spec.HostPathProvisioner.Workload = commonTestUtils.NewOtherNodePlacement()
+			hpp, err = NewHostPathProvisioner(hco)
+			Expect(err).To(BeNil())
+			nodeSelector, _, _ = unstructured.NestedStringMap(hpp.Object, "spec", "workload", "nodeSelector")
+			Expect(nodeSelector).To(Equal(hco.Spec.HostPathProvisioner.Workload.NodeSelector))
+		})
+
+		It("should find if present and add it to the related objects", func() {
+			existing, err := NewHostPathProvisioner(hco)
+			Expect(err).To(BeNil())
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := newHppHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Created).To(BeFalse())
+			Expect(res.Updated).To(BeFalse())
+
+			objectRef, err := reference.GetReference(handler.Scheme, existing)
+			Expect(err).To(BeNil())
+			Expect(hco.Status.RelatedObjects).To(ContainElement(*objectRef))
+		})
+
+		It("should reconcile the managed fields and keep the rest of the spec", func() {
+			existing, err := NewHostPathProvisioner(hco)
+			Expect(err).To(BeNil())
+			Expect(unstructured.SetNestedField(existing.Object, "/other/path", "spec", "pathConfig", "path")).To(Succeed())
+			Expect(unstructured.SetNestedField(existing.Object, "Always", "spec", "imagePullPolicy")).To(Succeed())
+			req.HCOTriggered = false // mock a reconciliation triggered by a change in the HostPathProvisioner CR
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := newHppHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Updated).To(BeTrue())
+			Expect(res.Overwritten).To(BeTrue())
+
+			found, err := getHpp(cl)
+			Expect(err).To(BeNil())
+			path, _, _ := unstructured.NestedString(found.Object, "spec", "pathConfig", "path")
+			Expect(path).To(Equal(hppPath))
+			pullPolicy, _, _ := unstructured.NestedString(found.Object, "spec", "imagePullPolicy")
+			Expect(pullPolicy).To(Equal("Always"))
+		})
+
+		It("should remove the node placement if it was removed from HCO", func() {
+			hco.Spec.HostPathProvisioner.Workload = commonTestUtils.NewNodePlacement()
+			existing, err := NewHostPathProvisioner(hco)
+			Expect(err).To(BeNil())
+			hco.Spec.HostPathProvisioner.Workload = nil
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := newHppHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Updated).To(BeTrue())
+			Expect(res.Overwritten).To(BeFalse())
+
+			found, err := getHpp(cl)
+			Expect(err).To(BeNil())
+			_, hasWorkload, _ := unstructured.NestedMap(found.Object, "spec", "workload")
+			Expect(hasWorkload).To(BeFalse())
+		})
+
+		It("should delete the CR if spec.hostPathProvisioner was removed", func() {
+			existing, err := NewHostPathProvisioner(hco)
+			Expect(err).To(BeNil())
+			objectRef, err := reference.GetReference(commonTestUtils.GetScheme(), existing)
+			Expect(err).To(BeNil())
+			hco.Status.RelatedObjects = []corev1.ObjectReference{*objectRef}
+			hco.Spec.HostPathProvisioner = nil
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := newHppHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+
+			_, err = getHpp(cl)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(hco.Status.RelatedObjects).To(BeEmpty())
+			Expect(req.StatusDirty).To(BeTrue())
+		})
+
+		Context("conditions and version", func() {
+			const hppVersion = "v1.2.3"
+
+			var origVersion string
+
+			BeforeEach(func() {
+				origVersion = os.Getenv(hcoutil.HppoVersionEnvV)
+				Expect(os.Setenv(hcoutil.HppoVersionEnvV, hppVersion)).To(Succeed())
+				req.UpgradeMode = true
+			})
+
+			AfterEach(func() {
+				Expect(os.Setenv(hcoutil.HppoVersionEnvV, origVersion)).To(Succeed())
+			})
+
+			setStatus := func(hpp *unstructured.Unstructured, available corev1.ConditionStatus, version string) {
+				hpp.Object["status"] = map[string]interface{}{
+					"observedVersion": version,
+					"conditions": []interface{}{
+						map[string]interface{}{"type": string(conditionsv1.ConditionAvailable), "status": string(available), "message": "hpp message"},
+						map[string]interface{}{"type": string(conditionsv1.ConditionProgressing), "status": string(corev1.ConditionFalse)},
+						map[string]interface{}{"type": string(conditionsv1.ConditionDegraded), "status": string(corev1.ConditionFalse)},
+					},
+				}
+			}
+
+			It("should report the upgrade as done when the CR is available with the expected version", func() {
+				existing, err := NewHostPathProvisioner(hco)
+				Expect(err).To(BeNil())
+				setStatus(existing, corev1.ConditionTrue, hppVersion)
+
+				cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+				res := newHppHandler(cl, commonTestUtils.GetScheme()).ensure(req)
+				Expect(res.Err).To(BeNil())
+				Expect(res.UpgradeDone).To(BeTrue())
+				Expect(req.Conditions).To(BeEmpty())
+			})
+
+			It("should not report the upgrade as done when the CR is with an old version", func() {
+				existing, err := NewHostPathProvisioner(hco)
+				Expect(err).To(BeNil())
+				setStatus(existing, corev1.ConditionTrue, "v1.0.0")
+
+				cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+				res := newHppHandler(cl, commonTestUtils.GetScheme()).ensure(req)
+				Expect(res.Err).To(BeNil())
+				Expect(res.UpgradeDone).To(BeFalse())
+			})
+
+			It("should propagate the CR conditions", func() {
+				existing, err := NewHostPathProvisioner(hco)
+				Expect(err).To(BeNil())
+				setStatus(existing, corev1.ConditionFalse, hppVersion)
+
+				cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+				res := newHppHandler(cl, commonTestUtils.GetScheme()).ensure(req)
+				Expect(res.Err).To(BeNil())
+				Expect(res.UpgradeDone).To(BeFalse())
+
+				cond, found := req.Conditions[conditionsv1.ConditionAvailable]
+				Expect(found).To(BeTrue())
+				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				Expect(cond.Reason).To(Equal("HostPathProvisionerNotAvailable"))
+				Expect(cond.Message).To(ContainSubstring("hpp message"))
+			})
+		})
+	})
+
+	Context("StorageClass", func() {
+		It("should create the default storage class if not present", func() {
+			cl := commonTestUtils.InitClient([]runtime.Object{})
+			handler := newHppStorageClassHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Created).To(BeTrue())
+			Expect(res.Type).To(Equal("StorageClass"))
+
+			found := &storagev1.StorageClass{}
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Name: defaultStoragePool}, found)).To(Succeed())
+			Expect(found.Provisioner).To(Equal(hppProvisioner))
+			Expect(*found.VolumeBindingMode).To(Equal(storagev1.VolumeBindingWaitForFirstConsumer))
+		})
+
+		It("should name the storage class after the storage pool", func() {
+			hco.Spec.HostPathProvisioner.StoragePool = "local-pool"
+			Expect(NewHppStorageClass(hco).Name).To(Equal("local-pool"))
+		})
+
+		It("should replace a storage class with modified immutable fields", func() {
+			existing := NewHppStorageClass(hco)
+			bindingMode := storagev1.VolumeBindingImmediate
+			existing.VolumeBindingMode = &bindingMode
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := newHppStorageClassHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Updated).To(BeTrue())
+
+			found := &storagev1.StorageClass{}
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Name: existing.Name}, found)).To(Succeed())
+			Expect(*found.VolumeBindingMode).To(Equal(storagev1.VolumeBindingWaitForFirstConsumer))
+		})
+
+		It("should remove the storage class of a renamed storage pool", func() {
+			stale := NewHppStorageClass(hco)
+			hco.Spec.HostPathProvisioner.StoragePool = "new-pool"
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, stale})
+			handler := newHppStorageClassHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Created).To(BeTrue())
+
+			scList := &storagev1.StorageClassList{}
+			Expect(cl.List(context.TODO(), scList)).To(Succeed())
+			Expect(scList.Items).To(HaveLen(1))
+			Expect(scList.Items[0].Name).To(Equal("new-pool"))
+		})
+
+		It("should not remove storage classes that were not created by HCO", func() {
+			notOurs := NewHppStorageClass(hco)
+			notOurs.Labels = nil
+			hco.Spec.HostPathProvisioner = nil
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, notOurs})
+			handler := newHppStorageClassHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+
+			found := &storagev1.StorageClass{}
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Name: notOurs.Name}, found)).To(Succeed())
+		})
+
+		It("should not modify a storage class with the same name, that was not created by HCO", func() {
+			notOurs := NewHppStorageClass(hco)
+			notOurs.Labels = map[string]string{hcoutil.AppLabel: hco.Name}
+			notOurs.Provisioner = "kubernetes.io/no-provisioner"
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, notOurs})
+			handler := newHppStorageClassHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+			Expect(res.Updated).To(BeFalse())
+
+			found := &storagev1.StorageClass{}
+			Expect(cl.Get(context.TODO(), client.ObjectKey{Name: notOurs.Name}, found)).To(Succeed())
+			Expect(found.Provisioner).To(Equal("kubernetes.io/no-provisioner"))
+			Expect(found.Labels).To(Equal(notOurs.Labels))
+
+			Expect(req.Conditions[conditionsv1.ConditionDegraded]).To(testlib.RepresentCondition(conditionsv1.Condition{
+				Type:    conditionsv1.ConditionDegraded,
+				Status:  corev1.ConditionTrue,
+				Reason:  "HPPStorageClassConflict",
+				Message: "the hostpath-provisioner StorageClass was not created by HCO; please set another spec.hostPathProvisioner.storagePool",
+			}))
+		})
+
+		It("should remove the storage class if spec.hostPathProvisioner was removed", func() {
+			existing := NewHppStorageClass(hco)
+			hco.Spec.HostPathProvisioner = nil
+
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := newHppStorageClassHandler(cl, commonTestUtils.GetScheme())
+
+			res := handler.ensure(req)
+			Expect(res.Err).To(BeNil())
+
+			found := &storagev1.StorageClass{}
+			err := cl.Get(context.TODO(), client.ObjectKey{Name: existing.Name}, found)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
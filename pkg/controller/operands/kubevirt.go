@@ -6,6 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -24,6 +25,7 @@ import (
 
 const (
 	kubevirtDefaultNetworkInterfaceValue = "masquerade"
+	KubeVirtConfigMapName                = "kubevirt-config"
 	// We can import the constants below from Kubevirt virt-config package
 	// after Kubevirt will consume k8s.io v0.19.2 or higher
 	FeatureGatesKey         = "feature-gates"
@@ -90,7 +92,8 @@ var (
 	}
 )
 
-// KubeVirt feature gates that are exposed in HCO API
+// KubeVirt feature gates that are exposed in HCO API. The mapping of the HCO feature gates to these names is
+// declared in the HCO feature gate registry.
 const (
 	HotplugVolumesGate       = "HotplugVolumes"
 	kvWithHostPassthroughCPU = "WithHostPassthroughCPU"
@@ -155,7 +158,7 @@ func (h *kubevirtHooks) updateCr(req *common.HcoRequest, Client client.Client, e
 	if !ok1 || !ok2 {
 		return false, false, errors.New("can't convert to KubeVirt")
 	}
-	if !reflect.DeepEqual(found.Spec, virt.Spec) ||
+	if !reflect.DeepEqual(getSortedFeatureGatesSpec(&found.Spec), getSortedFeatureGatesSpec(&virt.Spec)) ||
 		!reflect.DeepEqual(found.Labels, virt.Labels) {
 		if req.HCOTriggered {
 			req.Logger.Info("Updating existing KubeVirt's Spec to new opinionated values")
@@ -173,6 +176,19 @@ func (h *kubevirtHooks) updateCr(req *common.HcoRequest, Client client.Client, e
 	return false, false, nil
 }
 
+// getSortedFeatureGatesSpec returns the KubeVirt spec with sorted feature gates; the spec is copied if they are not
+// sorted. The specs are compared with sorted feature gates, so a KubeVirt CR with the same feature gates in a different
+// order, e.g. from a previous HCO version or from a jsonpatch annotation, is not updated.
+func getSortedFeatureGatesSpec(spec *kubevirtv1.KubeVirtSpec) *kubevirtv1.KubeVirtSpec {
+	if spec.Configuration.DeveloperConfiguration == nil || sort.StringsAreSorted(spec.Configuration.DeveloperConfiguration.FeatureGates) {
+		return spec
+	}
+
+	sorted := spec.DeepCopy()
+	sort.Strings(sorted.Configuration.DeveloperConfiguration.FeatureGates)
+	return sorted
+}
+
 func NewKubeVirt(hc *hcov1beta1.HyperConverged, opts ...string) (*kubevirtv1.KubeVirt, error) {
 	config, err := getKVConfig(hc)
 	if err != nil {
@@ -189,7 +205,7 @@ func NewKubeVirt(hc *hcov1beta1.HyperConverged, opts ...string) (*kubevirtv1.Kub
 	kv := NewKubeVirtWithNameOnly(hc, opts...)
 	kv.Spec = spec
 
-	if err := applyPatchToSpec(hc, common.JSONPatchKVAnnotationName, kv); err != nil {
+	if err := applyPatchAnnotations(hc, kubeVirtPatchTarget, kv); err != nil {
 		return nil, err
 	}
 
@@ -310,7 +326,7 @@ func newKvConfigHandler(Client client.Client, Scheme *runtime.Scheme) *kvConfigH
 type kvConfigHooks struct{}
 
 func (h kvConfigHooks) getFullCr(hc *hcov1beta1.HyperConverged) (client.Object, error) {
-	return NewKubeVirtConfigForCR(hc, hc.Namespace), nil
+	return newPatchedConfigMap(hc, kvConfigPatchTarget, NewKubeVirtConfigForCR(hc, hc.Namespace))
 }
 func (h kvConfigHooks) getEmptyCr() client.Object                             { return &corev1.ConfigMap{} }
 func (h kvConfigHooks) validate() error                                       { return nil }
@@ -335,6 +351,7 @@ func (h *kvConfigHooks) updateCr(req *common.HcoRequest, Client client.Client, e
 	}
 
 	changed = h.updateData(found, kubevirtConfig) || changed
+	changed = h.updatePatchedData(req, found, kubevirtConfig) || changed
 
 	if !reflect.DeepEqual(found.Labels, kubevirtConfig.Labels) {
 		util.DeepCopyLabels(&kubevirtConfig.ObjectMeta, &found.ObjectMeta)
@@ -353,10 +370,6 @@ func (h *kvConfigHooks) updateDataOnUpgrade(req *common.HcoRequest, found *corev
 		changed = true
 	}
 
-	if h.removeOldKeys(req, found) {
-		changed = true
-	}
-
 	return changed
 }
 
@@ -369,6 +382,12 @@ func (h *kvConfigHooks) updateData(found *corev1.ConfigMap, required *corev1.Con
 	return false
 }
 
+// updatePatchedData reconciles the keys that are added, modified or removed by the patch annotations of the
+// HyperConverged CR, as the other keys of the kubevirt-config ConfigMap are only set on creation or upgrade
+func (h *kvConfigHooks) updatePatchedData(req *common.HcoRequest, found *corev1.ConfigMap, required *corev1.ConfigMap) bool {
+	return updatePatchedConfigMapData(found, required, NewKubeVirtConfigForCR(req.Instance, found.Namespace))
+}
+
 func (h *kvConfigHooks) updateKvConfigMap(req *common.HcoRequest, Client client.Client, found *corev1.ConfigMap) (bool, bool, error) {
 	err := Client.Update(req.Ctx, found)
 	if err != nil {
@@ -378,26 +397,12 @@ func (h *kvConfigHooks) updateKvConfigMap(req *common.HcoRequest, Client client.
 	return true, false, nil
 }
 
-type featureGateChecks map[string]func() bool
-
-func getFeatureGateChecks(featureGates *hcov1beta1.HyperConvergedFeatureGates) featureGateChecks {
-	return map[string]func() bool{
-		HotplugVolumesGate:       featureGates.IsHotplugVolumesEnabled,
-		kvWithHostPassthroughCPU: featureGates.IsWithHostPassthroughCPUEnabled,
-		kvWithHostModelCPU:       featureGates.IsWithHostModelCPUEnabled,
-		SRIOVLiveMigrationGate:   featureGates.IsSRIOVLiveMigrationEnabled,
-		kvHypervStrictCheck:      featureGates.IsHypervStrictCheckEnabled,
-		GPUGate:                  featureGates.IsGPUAssignmentEnabled,
-		HostDevicesGate:          featureGates.IsHostDevicesAssignmentEnabled,
-	}
-}
-
 func (h *kvConfigHooks) forceDefaultKeys(req *common.HcoRequest, found *corev1.ConfigMap, kubevirtConfig *corev1.ConfigMap) bool {
 	changed := false
 	// only virtconfig.SmbiosConfigKey, virtconfig.MachineTypeKey, virtconfig.SELinuxLauncherTypeKey,
 	// virtconfig.FeatureGatesKey and virtconfig.UseEmulationKey are going to be manipulated
 	// and only on HCO upgrades.
-	// virtconfig.MigrationsConfigKey is going to be removed if set in the past (only during upgrades).
+	// virtconfig.MigrationsConfigKey is removed by an upgrade migration, if it was set in the past.
 	// TODO: This is going to change in the next HCO release where the whole configMap is going
 	// to be continuously reconciled
 	for _, k := range []string{
@@ -414,16 +419,6 @@ func (h *kvConfigHooks) forceDefaultKeys(req *common.HcoRequest, found *corev1.C
 	return changed
 }
 
-func (h *kvConfigHooks) removeOldKeys(req *common.HcoRequest, found *corev1.ConfigMap) bool {
-	if _, ok := found.Data[MigrationsConfigKey]; ok {
-		req.Logger.Info(fmt.Sprintf("Deleting %s on existing KubeVirt config", MigrationsConfigKey))
-		delete(found.Data, MigrationsConfigKey)
-		return true
-	}
-	return false
-
-}
-
 func (h *kvConfigHooks) forceDefaultValues(req *common.HcoRequest, found *corev1.ConfigMap, kubevirtConfig *corev1.ConfigMap, k string) bool {
 	if found.Data[k] != kubevirtConfig.Data[k] {
 		req.Logger.Info(fmt.Sprintf("Updating %s on existing KubeVirt config", k))
@@ -538,14 +533,14 @@ func NewKubeVirtConfigForCR(cr *hcov1beta1.HyperConverged, namespace string) *co
 
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "kubevirt-config",
+			Name:      KubeVirtConfigMapName,
 			Labels:    getLabels(cr, hcoutil.AppComponentCompute),
 			Namespace: namespace,
 		},
 		// only virtconfig.SmbiosConfigKey, virtconfig.MachineTypeKey, virtconfig.SELinuxLauncherTypeKey,
 		// virtconfig.FeatureGatesKey and virtconfig.UseEmulationKey are going to be manipulated
 		// and only on HCO upgrades.
-		// virtconfig.MigrationsConfigKey is going to be removed if set in the past (only during upgrades).
+		// virtconfig.MigrationsConfigKey is removed by an upgrade migration, if it was set in the past.
 		// TODO: This is going to change in the next HCO release where the whole configMap is going
 		// to be continuously reconciled
 		Data: map[string]string{
@@ -569,17 +564,14 @@ func NewKubeVirtConfigForCR(cr *hcov1beta1.HyperConverged, namespace string) *co
 	return cm
 }
 
-// get list of feature gates or KV FG list
+// get list of feature gates or KV FG list. The list is sorted, so the same HyperConverged CR always produces the
+// same list, and no needless update is triggered.
 func getKvFeatureGateList(fgs *hcov1beta1.HyperConvergedFeatureGates) []string {
-	checks := getFeatureGateChecks(fgs)
-	res := make([]string, 0, len(checks)+len(hardCodeKvFgs))
+	enabled := fgs.GetEnabledOperandGates(hcov1beta1.FeatureGateOperandKubeVirt)
+	res := make([]string, 0, len(enabled)+len(hardCodeKvFgs))
 	res = append(res, hardCodeKvFgs...)
-
-	for gate, check := range checks {
-		if check() {
-			res = append(res, gate)
-		}
-	}
+	res = append(res, enabled...)
+	sort.Strings(res)
 
 	return res
 }
@@ -3,8 +3,9 @@ package operands
 import (
 	"context"
 	"fmt"
-
 	"os"
+	"sort"
+	"strings"
 
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
@@ -119,7 +120,6 @@ var _ = Describe("KubeVirt Operand", func() {
 		var req *common.HcoRequest
 
 		updatableKeys := [...]string{SmbiosConfigKey, MachineTypeKey, SELinuxLauncherTypeKey, FeatureGatesKey}
-		removeKeys := [...]string{MigrationsConfigKey}
 		unupdatableKeys := [...]string{NetworkInterfaceKey}
 
 		BeforeEach(func() {
@@ -182,8 +182,6 @@ Version: 1.2.3`)
 			outdatedResource.Data[MachineTypeKey] = "old-machinetype-value-that-we-have-to-update"
 			outdatedResource.Data[SELinuxLauncherTypeKey] = "old-selinuxlauncher-value-that-we-have-to-update"
 			outdatedResource.Data[FeatureGatesKey] = "old-featuregates-value-that-we-have-to-update"
-			// value that we should remove if configured
-			outdatedResource.Data[MigrationsConfigKey] = "old-migrationsconfig-value-that-we-should-remove"
 			// values we should preserve
 			outdatedResource.Data[NetworkInterfaceKey] = "old-defaultnetworkinterface-value-that-we-should-preserve"
 
@@ -211,11 +209,6 @@ Version: 1.2.3`)
 				Expect(foundResource.Data[k]).To(Equal(outdatedResource.Data[k]))
 				Expect(foundResource.Data[k]).To(Not(Equal(expectedResource.Data[k])))
 			}
-			for _, k := range removeKeys {
-				Expect(outdatedResource.Data).To(HaveKey(k))
-				Expect(expectedResource.Data).To(Not(HaveKey(k)))
-				Expect(foundResource.Data).To(Not(HaveKey(k)))
-			}
 		})
 
 		It("should not touch it when not in in upgrade mode", func() {
@@ -253,7 +246,7 @@ Version: 1.2.3`)
 		})
 
 		Context("Feature Gates", func() {
-			const cmFeatureGates = "DataVolumes,SRIOV,LiveMigration,CPUManager,CPUNodeDiscovery,Sidecar,Snapshot"
+			const cmFeatureGates = kvCmFeatureGates
 
 			var (
 				enabled  = true
@@ -276,7 +269,7 @@ Version: 1.2.3`)
 
 				existingResource := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
 				By("KV CR should contain the HotplugVolumesGate feature gate", func() {
-					Expect(existingResource.Data[FeatureGatesKey]).Should(Equal(cmFeatureGates + "," + HotplugVolumesGate))
+					Expect(existingResource.Data[FeatureGatesKey]).Should(Equal(withCmFeatureGates(HotplugVolumesGate)))
 				})
 			})
 
@@ -294,7 +287,7 @@ Version: 1.2.3`)
 				reconcileCm(hco, req, true, existingResource, foundResource)
 
 				By("KV CR should contain the enabled feature gate", func() {
-					Expect(foundResource.Data[FeatureGatesKey]).Should(Equal(cmFeatureGates + "," + HotplugVolumesGate))
+					Expect(foundResource.Data[FeatureGatesKey]).Should(Equal(withCmFeatureGates(HotplugVolumesGate)))
 				})
 			})
 
@@ -347,7 +340,7 @@ Version: 1.2.3`)
 				}
 
 				existingResource := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
-				Expect(existingResource.Data[FeatureGatesKey]).Should(Equal(cmFeatureGates + ",WithHostPassthroughCPU"))
+				Expect(existingResource.Data[FeatureGatesKey]).Should(Equal(withCmFeatureGates("WithHostPassthroughCPU")))
 			})
 
 			It("should add WithHostModelCPU if enabled", func() {
@@ -356,7 +349,7 @@ Version: 1.2.3`)
 				}
 
 				existingResource := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
-				Expect(existingResource.Data[FeatureGatesKey]).Should(Equal(cmFeatureGates + ",WithHostModelCPU"))
+				Expect(existingResource.Data[FeatureGatesKey]).Should(Equal(withCmFeatureGates("WithHostModelCPU")))
 			})
 
 			It("should not add feature gates if they are not exist", func() {
@@ -416,7 +409,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(Equal(cmFeatureGates + "," + kvWithHostModelCPU))
+					Expect(foundResource.Data[FeatureGatesKey]).Should(Equal(withCmFeatureGates(kvWithHostModelCPU)))
 				})
 
 				It("Should remove SRIOVLiveMigration from the CM when SRIOVLiveMigration FeatureGate is disabled", func() {
@@ -463,7 +456,7 @@ Version: 1.2.3`)
 
 				It("Should keep the HotplugVolumes gate from the CM if the HotplugVolumes FeatureGates is enabled", func() {
 					existingResource := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
-					existingResource.Data[FeatureGatesKey] = fmt.Sprintf("%s,%s", cmFeatureGates, HotplugVolumesGate)
+					existingResource.Data[FeatureGatesKey] = withCmFeatureGates(HotplugVolumesGate)
 
 					hco.Spec.FeatureGates = &hcov1beta1.HyperConvergedFeatureGates{
 						HotplugVolumes: &enabled,
@@ -472,12 +465,12 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, false, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(Equal(fmt.Sprintf("%s,%s", cmFeatureGates, HotplugVolumesGate)))
+					Expect(foundResource.Data[FeatureGatesKey]).Should(Equal(withCmFeatureGates(HotplugVolumesGate)))
 				})
 
 				It("Should keep SRIOVLiveMigration gate at the CM if the SRIOVLiveMigration FeatureGate is enabled", func() {
 					existingResource := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
-					existingResource.Data[FeatureGatesKey] = fmt.Sprintf("%s,%s", cmFeatureGates, SRIOVLiveMigrationGate)
+					existingResource.Data[FeatureGatesKey] = withCmFeatureGates(SRIOVLiveMigrationGate)
 
 					hco.Spec.FeatureGates = &hcov1beta1.HyperConvergedFeatureGates{
 						SRIOVLiveMigration: &enabled,
@@ -504,7 +497,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(HotplugVolumesGate))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(kvWithHostPassthroughCPU))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(kvWithHostModelCPU))
@@ -523,7 +516,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(SRIOVLiveMigrationGate))
 				})
 
@@ -538,7 +531,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(GPUGate))
 				})
 
@@ -553,7 +546,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(HostDevicesGate))
 				})
 
@@ -568,7 +561,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(HotplugVolumesGate))
 					Expect(foundResource.Data[FeatureGatesKey]).ShouldNot(ContainSubstring("userDefinedFG"))
 				})
@@ -584,7 +577,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(SRIOVLiveMigrationGate))
 					Expect(foundResource.Data[FeatureGatesKey]).ShouldNot(ContainSubstring("userDefinedFG"))
 				})
@@ -600,7 +593,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(GPUGate))
 					Expect(foundResource.Data[FeatureGatesKey]).ShouldNot(ContainSubstring("userDefinedFG"))
 				})
@@ -616,7 +609,7 @@ Version: 1.2.3`)
 					foundResource := &corev1.ConfigMap{}
 					reconcileCm(hco, req, true, existingResource, foundResource)
 
-					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(cmFeatureGates))
+					Expect(strings.Split(foundResource.Data[FeatureGatesKey], ",")).Should(ContainElements(strings.Split(cmFeatureGates, ",")))
 					Expect(foundResource.Data[FeatureGatesKey]).Should(ContainSubstring(HostDevicesGate))
 					Expect(foundResource.Data[FeatureGatesKey]).ShouldNot(ContainSubstring("userDefinedFG"))
 				})
@@ -1923,9 +1916,33 @@ Version: 1.2.3`)
 			Expect(fgList).Should(ContainElements(HotplugVolumesGate, kvWithHostModelCPU, SRIOVLiveMigrationGate))
 			Expect(fgList).ShouldNot(ContainElements(kvWithHostPassthroughCPU, kvHypervStrictCheck))
 		})
+
+		It("Should create a sorted slice", func() {
+			enabled := true
+			fgs := &hcov1beta1.HyperConvergedFeatureGates{
+				HotplugVolumes:         &enabled,
+				WithHostPassthroughCPU: &enabled,
+				WithHostModelCPU:       &enabled,
+				HypervStrictCheck:      &enabled,
+				SRIOVLiveMigration:     &enabled,
+				GPU:                    &enabled,
+				HostDevices:            &enabled,
+			}
+			fgList := getKvFeatureGateList(fgs)
+			Expect(sort.StringsAreSorted(fgList)).To(BeTrue(), "%v", fgList)
+		})
 	})
 })
 
+// withCmFeatureGates returns the expected kubevirt-config feature gates string, when the gates are enabled
+func withCmFeatureGates(gates ...string) string {
+	fgs := append(strings.Split(kvCmFeatureGates, ","), gates...)
+	sort.Strings(fgs)
+	return strings.Join(fgs, ",")
+}
+
+const kvCmFeatureGates = "CPUManager,CPUNodeDiscovery,DataVolumes,LiveMigration,SRIOV,Sidecar,Snapshot"
+
 func reconcileCm(hco *hcov1beta1.HyperConverged, req *common.HcoRequest, expectUpdate bool, existingCM, foundCm *corev1.ConfigMap) {
 	cl := commonTestUtils.InitClient([]runtime.Object{hco, existingCM})
 	handler := (*genericOperand)(newKvConfigHandler(cl, commonTestUtils.GetScheme()))
@@ -75,8 +75,6 @@ func (h *cnaHooks) updateCr(req *common.HcoRequest, Client client.Client, exists
 		return false, false, errors.New("can't convert to CNA")
 	}
 
-	h.setDeployOvsAnnotation(req, found)
-
 	changed := h.updateSpec(req, found, networkAddons)
 	changed = h.updateLabels(found, networkAddons) || changed
 
@@ -116,28 +114,6 @@ func (h *cnaHooks) updateSpec(req *common.HcoRequest, found *networkaddonsv1.Net
 	return false
 }
 
-// If deployOVS annotation doesn't exists prior the upgrade - set this annotation to true;
-// Otherwise - remain the value as it is.
-func (h *cnaHooks) setDeployOvsAnnotation(req *common.HcoRequest, found *networkaddonsv1.NetworkAddonsConfig) {
-	if req.UpgradeMode {
-		_, exists := req.Instance.Annotations["deployOVS"]
-		if !exists {
-			if req.Instance.Annotations == nil {
-				req.Instance.Annotations = map[string]string{}
-			}
-			if found.Spec.Ovs != nil {
-				req.Instance.Annotations["deployOVS"] = "true"
-				req.Logger.Info("deployOVS annotation is set to true.")
-			} else {
-				req.Instance.Annotations["deployOVS"] = "false"
-				req.Logger.Info("deployOVS annotation is set to false.")
-			}
-
-			req.Dirty = true
-		}
-	}
-}
-
 func NewNetworkAddons(hc *hcov1beta1.HyperConverged, opts ...string) (*networkaddonsv1.NetworkAddonsConfig, error) {
 
 	cnaoSpec := networkaddonsshared.NetworkAddonsConfigSpec{
@@ -160,7 +136,7 @@ func NewNetworkAddons(hc *hcov1beta1.HyperConverged, opts ...string) (*networkad
 	cna := NewNetworkAddonsWithNameOnly(hc, opts...)
 	cna.Spec = cnaoSpec
 
-	if err := applyPatchToSpec(hc, common.JSONPatchCNAOAnnotationName, cna); err != nil {
+	if err := applyPatchAnnotations(hc, cnaPatchTarget, cna); err != nil {
 		return nil, err
 	}
 
@@ -210,7 +186,7 @@ func hcoConfig2CnaoPlacement(hcoConf *sdkapi.NodePlacement) *networkaddonsshared
 }
 
 func hcoAnnotation2CnaoSpec(hcoAnnotations map[string]string) *networkaddonsshared.Ovs {
-	val, exists := hcoAnnotations["deployOVS"]
+	val, exists := hcoAnnotations[hcov1beta1.DeployOVSAnnotation]
 	if exists && val == "true" {
 		return &networkaddonsshared.Ovs{}
 	}
@@ -1,13 +1,9 @@
 package operands
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
-	"strings"
 
-	jsonpatch "github.com/evanphx/json-patch"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -25,6 +21,8 @@ import (
 
 type Operand interface {
 	ensure(req *common.HcoRequest) *EnsureResult
+	// compare the resource that would be deployed for the HyperConverged CR with the live resource, without modifying it
+	preview(req *common.HcoRequest, hc *hcov1beta1.HyperConverged) (*OperandPreview, error)
 	reset()
 }
 
@@ -339,41 +337,3 @@ func getLabels(hc *hcov1beta1.HyperConverged, component hcoutil.AppComponent) ma
 		hcoutil.AppLabelComponent: string(component),
 	}
 }
-
-func applyAnnotationPatch(obj runtime.Object, annotation string) error {
-	patches, err := jsonpatch.DecodePatch([]byte(annotation))
-	if err != nil {
-		return err
-	}
-
-	for _, patch := range patches {
-		path, err := patch.Path()
-		if err != nil {
-			return err
-		}
-
-		if !strings.HasPrefix(path, "/spec/") {
-			return errors.New("can only modify spec fields")
-		}
-	}
-
-	specBytes, err := json.Marshal(obj)
-	if err != nil {
-		return err
-	}
-	patchedBytes, err := patches.Apply(specBytes)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(patchedBytes, obj)
-}
-
-func applyPatchToSpec(hc *hcov1beta1.HyperConverged, annotationName string, obj runtime.Object) error {
-	if jsonpathAnnotation, ok := hc.Annotations[annotationName]; ok {
-		if err := applyAnnotationPatch(obj, jsonpathAnnotation); err != nil {
-			return fmt.Errorf("invalid jsonPatch in the %s annotation: %v", annotationName, err)
-		}
-	}
-
-	return nil
-}
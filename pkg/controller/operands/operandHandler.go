@@ -41,64 +41,121 @@ type OperandHandler struct {
 	operands []Operand
 	// save for deletions
 	quickStartObjects []*consolev1.ConsoleQuickStart
+	extraManifests    *extraManifestsLoader
 	eventEmitter      hcoutil.EventEmitter
+	clusterInfo       hcoutil.ClusterInfo
+	scheme            *runtime.Scheme
+	crds              *crdAvailability
+	// the quick starts are loaded once the console API is available
+	quickStartsLoaded bool
 }
 
-func NewOperandHandler(client client.Client, scheme *runtime.Scheme, isOpenshiftCluster bool, eventEmitter hcoutil.EventEmitter) *OperandHandler {
+func NewOperandHandler(client client.Client, scheme *runtime.Scheme, ci hcoutil.ClusterInfo, eventEmitter hcoutil.EventEmitter) *OperandHandler {
+	crds := newCRDAvailability()
 	operands := []Operand{
 		(*genericOperand)(newKvConfigHandler(client, scheme)),
 		(*genericOperand)(newKvPriorityClassHandler(client, scheme)),
 		(*genericOperand)(newKubevirtHandler(client, scheme)),
 		(*genericOperand)(newCdiHandler(client, scheme)),
 		(*genericOperand)(newStorageConfigHandler(client, scheme)),
-		(*genericOperand)(newCnaHandler(client, scheme)),
-		(*genericOperand)(newVmImportHandler(client, scheme)),
-		(*genericOperand)(newImsConfigHandler(client, scheme)),
-	}
-
-	if isOpenshiftCluster {
-		operands = append(operands, []Operand{
-			newSspHandler(client, scheme),
-			(*genericOperand)(newMetricsServiceHandler(client, scheme)),
-			(*genericOperand)(newMetricsServiceMonitorHandler(client, scheme)),
-			(*genericOperand)(newMonitoringPrometheusRuleHandler(client, scheme)),
-		}...)
+		newOptionalOperand((*genericOperand)(newCnaHandler(client, scheme)), client, scheme, isNetworkAddonsEnabled, getNetworkAddonsResource),
+		newCapableOperand(
+			newOptionalOperand((*genericOperand)(newVmImportHandler(client, scheme)), client, scheme, isVMImportEnabled, getVMImportResource),
+			ci, getVMImportResource).requireCRDs(crds, VMImportConfigCrdName),
+		newOptionalOperand((*genericOperand)(newImsConfigHandler(client, scheme)), client, scheme, isVMImportEnabled, getIMSConfigResource),
+		newHppHandler(client, scheme),
+		newHppStorageClassHandler(client, scheme),
+		// The operands below are deployed only if the cluster has the capabilities and the CRDs they require
+		newCapableOperand(
+			newOptionalOperand((*genericOperand)(newSspHandler(client, scheme)), client, scheme, isSSPEnabled, getSSPResource),
+			ci, getSSPResource, sspCapabilities...).requireCRDs(crds, SspCrdName),
+		newCapableOperand(
+			newOptionalOperand((*genericOperand)(newMetricsServiceHandler(client, scheme)), client, scheme, isMonitoringEnabled, getMetricsServiceResource),
+			ci, getMetricsServiceResource, monitoringCapabilities...).requireCRDs(crds, ServiceMonitorCrdName),
+		newCapableOperand(
+			newOptionalOperand((*genericOperand)(newMetricsServiceMonitorHandler(client, scheme)), client, scheme, isMonitoringEnabled, getServiceMonitorResource),
+			ci, getServiceMonitorResource, monitoringCapabilities...).requireCRDs(crds, ServiceMonitorCrdName),
+		newCapableOperand(
+			newOptionalOperand((*genericOperand)(newMonitoringPrometheusRuleHandler(client, scheme)), client, scheme, isMonitoringEnabled, getPrometheusRuleResource),
+			ci, getPrometheusRuleResource, monitoringCapabilities...).requireCRDs(crds, PrometheusRuleCrdName),
 	}
 
 	return &OperandHandler{
-		client:       client,
-		operands:     operands,
-		eventEmitter: eventEmitter,
+		client:         client,
+		operands:       operands,
+		extraManifests: newExtraManifestsLoader(client, scheme, eventEmitter),
+		eventEmitter:   eventEmitter,
+		clusterInfo:    ci,
+		scheme:         scheme,
+		crds:           crds,
 	}
 }
 
+// SetCRDsAvailability sets the availability of the optional CRDs. The operands of the unavailable CRDs are skipped.
+func (h *OperandHandler) SetCRDsAvailability(available map[string]bool) {
+	h.crds.set(available)
+}
+
 // The k8s client is not available when calling to NewOperandHandler.
 // Initial operations that need to read/write from the cluster can only be done when the client is already working.
-func (h *OperandHandler) FirstUseInitiation(scheme *runtime.Scheme, isOpenshiftCluster bool, hc *hcov1beta1.HyperConverged) {
-	if isOpenshiftCluster {
-		qsHandlers, err := getQuickStartHandlers(logger, h.client, scheme, hc)
-		if numQs := len(qsHandlers); numQs > 0 {
-			h.quickStartObjects = make([]*consolev1.ConsoleQuickStart, numQs)
-			for i, op := range qsHandlers {
-				qs, err := op.(*genericOperand).hooks.getFullCr(hc)
-				if err != nil {
-					logger.Error(err, "can't create ConsoleQuickStarts object")
-					continue
-				}
+func (h *OperandHandler) FirstUseInitiation(hc *hcov1beta1.HyperConverged) {
+	if h.extraManifests != nil {
+		if err := h.extraManifests.loadDir(logger); err != nil {
+			logger.Error(err, "can't read the extra manifests")
+		}
+	}
 
-				h.quickStartObjects[i] = qs.(*consolev1.ConsoleQuickStart)
+	h.initQuickStarts(hc)
+}
+
+// initQuickStarts loads the quick starts, once the console API is available. If the API is not available when HCO
+// starts, the quick starts are loaded in the first reconciliation after the API was deployed.
+func (h *OperandHandler) initQuickStarts(hc *hcov1beta1.HyperConverged) {
+	if h.quickStartsLoaded || h.clusterInfo == nil || !hasCapabilities(h.clusterInfo, quickStartCapabilities...) {
+		return
+	}
+	h.quickStartsLoaded = true
+
+	qsHandlers, err := getQuickStartHandlers(logger, h.client, h.scheme, hc)
+	if numQs := len(qsHandlers); numQs > 0 {
+		h.quickStartObjects = make([]*consolev1.ConsoleQuickStart, numQs)
+		for i, op := range qsHandlers {
+			qs, err := op.(*genericOperand).hooks.getFullCr(hc)
+			if err != nil {
+				logger.Error(err, "can't create ConsoleQuickStarts object")
+				continue
 			}
+
+			h.quickStartObjects[i] = qs.(*consolev1.ConsoleQuickStart)
 		}
-		if err != nil {
-			logger.Error(err, "can't create ConsoleQuickStarts objects")
-		} else if len(qsHandlers) > 0 {
-			h.operands = append(h.operands, qsHandlers...)
+	}
+	if err != nil {
+		logger.Error(err, "can't create ConsoleQuickStarts objects")
+	} else if len(qsHandlers) > 0 {
+		for i, op := range qsHandlers {
+			qs := h.quickStartObjects[i]
+			if qs == nil {
+				h.operands = append(h.operands, op)
+				continue
+			}
+			getQs := func(_ *hcov1beta1.HyperConverged) client.Object { return qs.DeepCopy() }
+			h.operands = append(h.operands, newCapableOperand(
+				newOptionalOperand(op, h.client, h.scheme, isQuickStartsEnabled, getQs),
+				h.clusterInfo, getQs, quickStartCapabilities...))
 		}
 	}
 }
 
-func (h OperandHandler) Ensure(req *common.HcoRequest) error {
-	for _, handler := range h.operands {
+func (h *OperandHandler) Ensure(req *common.HcoRequest) error {
+	h.initQuickStarts(req.Instance)
+
+	operands := h.operands
+	if h.extraManifests != nil {
+		operands = append(operands[:len(operands):len(operands)], h.extraManifests.getHandlers(req)...)
+	}
+
+	rendered := renderedResources{}
+	for _, handler := range operands {
 		res := handler.ensure(req)
 		if res.Err != nil {
 			req.ComponentUpgradeInProgress = false
@@ -120,10 +177,27 @@ func (h OperandHandler) Ensure(req *common.HcoRequest) error {
 				h.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeWarning, "Overwritten", fmt.Sprintf("Overwritten %s %s", res.Type, res.Name))
 				metrics.HcoMetrics.IncOverwrittenModifications(res.Name)
 			}
+		} else if res.Deleted {
+			h.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeNormal, "Killing", fmt.Sprintf("Removed %s %s", res.Type, res.Name))
 		}
 
 		req.ComponentUpgradeInProgress = req.ComponentUpgradeInProgress && res.UpgradeDone
+		rendered.add(res)
+	}
+
+	updateAppliedPatches(req, rendered)
+
+	if err := h.updatePreview(req, operands, rendered); err != nil {
+		req.Logger.Error(err, "failed to update the preview")
 	}
+
+	// The resources of the previous version may still be in use until the upgrade is completed
+	if !req.UpgradeMode || req.ComponentUpgradeInProgress {
+		if err := h.sweepOrphans(req, rendered); err != nil {
+			req.Logger.Error(err, "failed to remove the orphan resources")
+		}
+	}
+
 	return nil
 
 }
@@ -141,15 +215,23 @@ func (h OperandHandler) EnsureDeleted(req *common.HcoRequest) error {
 		NewKubeVirtWithNameOnly(req.Instance),
 		NewCDIWithNameOnly(req.Instance),
 		NewNetworkAddonsWithNameOnly(req.Instance),
-		NewSSP(req.Instance),
+		NewSSPWithNameOnly(req.Instance),
 		NewConsoleCLIDownload(req.Instance),
-		NewVMImportForCR(req.Instance),
+		NewVMImportWithNameOnly(req.Instance),
+		NewHostPathProvisionerWithNameOnly(req.Instance),
+		NewHppStorageClass(req.Instance),
 	}
 
 	for _, qs := range h.quickStartObjects {
 		resources = append(resources, qs)
 	}
 
+	if h.extraManifests != nil {
+		for _, manifest := range h.extraManifests.getManifests(req) {
+			resources = append(resources, manifest)
+		}
+	}
+
 	wg.Add(len(resources))
 
 	go func() {
@@ -3,9 +3,11 @@ package operands
 import (
 	"context"
 	"fmt"
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	networkaddonsv1 "github.com/kubevirt/cluster-network-addons-operator/pkg/apis/networkaddonsoperator/v1"
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	"github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -14,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
@@ -34,8 +37,8 @@ var _ = Describe("Test operandHandler", func() {
 
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
 
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 
@@ -161,6 +164,105 @@ var _ = Describe("Test operandHandler", func() {
 			})
 		})
 
+		It("should deploy only the operands that the cluster capabilities allow", func() {
+			err := os.Setenv(manifestLocationVarName, testFileLocation)
+			Expect(err).ToNot(HaveOccurred())
+			hco := commonTestUtils.NewHco()
+			cli := commonTestUtils.InitClient([]runtime.Object{qsCrd, hco})
+
+			eventEmitter := commonTestUtils.NewEventEmitterMock()
+			ci := commonTestUtils.NewClusterInfoMock()
+
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), ci, eventEmitter)
+			handler.FirstUseInitiation(hco)
+
+			req := commonTestUtils.NewReq(hco)
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			Expect(handler.quickStartObjects).To(BeEmpty())
+
+			sspList := sspv1beta1.SSPList{}
+			Expect(cli.List(req.Ctx, &sspList)).To(Succeed())
+			Expect(sspList.Items).To(BeEmpty())
+
+			smList := monitoringv1.ServiceMonitorList{}
+			Expect(cli.List(req.Ctx, &smList)).To(Succeed())
+			Expect(smList.Items).To(BeEmpty())
+
+			By("deploy the monitoring resources once prometheus-operator is installed", func() {
+				ci.SetCapabilities(hcoutil.CapabilityMonitoring)
+				req = commonTestUtils.NewReq(hco)
+				Expect(handler.Ensure(req)).To(Succeed())
+
+				Expect(cli.List(req.Ctx, &smList)).To(Succeed())
+				Expect(smList.Items).To(HaveLen(1))
+
+				ruleList := monitoringv1.PrometheusRuleList{}
+				Expect(cli.List(req.Ctx, &ruleList)).To(Succeed())
+				Expect(ruleList.Items).To(HaveLen(1))
+
+				Expect(cli.List(req.Ctx, &sspList)).To(Succeed())
+				Expect(sspList.Items).To(BeEmpty())
+			})
+		})
+
+		It("should skip the operands of the unavailable CRDs", func() {
+			hco := commonTestUtils.NewHco()
+			cli := commonTestUtils.InitClient([]runtime.Object{qsCrd, hco})
+
+			eventEmitter := commonTestUtils.NewEventEmitterMock()
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.SetCRDsAvailability(map[string]bool{SspCrdName: false, VMImportConfigCrdName: false, ServiceMonitorCrdName: true})
+
+			req := commonTestUtils.NewReq(hco)
+			Expect(handler.Ensure(req)).To(Succeed())
+
+			sspList := sspv1beta1.SSPList{}
+			Expect(cli.List(req.Ctx, &sspList)).To(Succeed())
+			Expect(sspList.Items).To(BeEmpty())
+
+			vmImportList := v1beta1.VMImportConfigList{}
+			Expect(cli.List(req.Ctx, &vmImportList)).To(Succeed())
+			Expect(vmImportList.Items).To(BeEmpty())
+
+			smList := monitoringv1.ServiceMonitorList{}
+			Expect(cli.List(req.Ctx, &smList)).To(Succeed())
+			Expect(smList.Items).To(HaveLen(1))
+
+			By("deploy the operands once their CRDs are available", func() {
+				handler.SetCRDsAvailability(map[string]bool{SspCrdName: true, VMImportConfigCrdName: true})
+				req = commonTestUtils.NewReq(hco)
+				Expect(handler.Ensure(req)).To(Succeed())
+
+				Expect(cli.List(req.Ctx, &sspList)).To(Succeed())
+				Expect(sspList.Items).To(HaveLen(1))
+				Expect(cli.List(req.Ctx, &vmImportList)).To(Succeed())
+				Expect(vmImportList.Items).To(HaveLen(1))
+			})
+		})
+
+		It("should load the quick starts once the console API is available", func() {
+			err := os.Setenv(manifestLocationVarName, testFileLocation)
+			Expect(err).ToNot(HaveOccurred())
+			hco := commonTestUtils.NewHco()
+			cli := commonTestUtils.InitClient([]runtime.Object{qsCrd, hco})
+
+			eventEmitter := commonTestUtils.NewEventEmitterMock()
+			ci := commonTestUtils.NewClusterInfoMock()
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), ci, eventEmitter)
+			handler.FirstUseInitiation(hco)
+			Expect(handler.quickStartObjects).To(BeEmpty())
+
+			ci.SetCapabilities(hcoutil.CapabilityConsole)
+			req := commonTestUtils.NewReq(hco)
+			Expect(handler.Ensure(req)).To(Succeed())
+			Expect(handler.quickStartObjects).To(HaveLen(1))
+
+			qsList := consolev1.ConsoleQuickStartList{}
+			Expect(cli.List(req.Ctx, &qsList)).To(Succeed())
+			Expect(qsList.Items).To(HaveLen(1))
+		})
+
 		It("should handle errors on ensure loop", func() {
 			err := os.Setenv(manifestLocationVarName, testFileLocation)
 			Expect(err).ToNot(HaveOccurred())
@@ -169,8 +271,8 @@ var _ = Describe("Test operandHandler", func() {
 
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
 
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 
@@ -212,8 +314,8 @@ var _ = Describe("Test operandHandler", func() {
 			cli := commonTestUtils.InitClient([]runtime.Object{qsCrd, hco})
 
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 			err = handler.Ensure(req)
@@ -316,8 +418,8 @@ var _ = Describe("Test operandHandler", func() {
 
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
 
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 			err = handler.Ensure(req)
@@ -367,8 +469,8 @@ var _ = Describe("Test operandHandler", func() {
 			cli := commonTestUtils.InitClient([]runtime.Object{qsCrd, hco})
 
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 			err = handler.Ensure(req)
@@ -421,8 +523,8 @@ var _ = Describe("Test operandHandler", func() {
 			fakeError := fmt.Errorf("fake CNA deletion error")
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
 
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 			err = handler.Ensure(req)
@@ -473,8 +575,8 @@ var _ = Describe("Test operandHandler", func() {
 
 			eventEmitter := commonTestUtils.NewEventEmitterMock()
 
-			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), true, eventEmitter)
-			handler.FirstUseInitiation(commonTestUtils.GetScheme(), true, hco)
+			handler := NewOperandHandler(cli, commonTestUtils.GetScheme(), commonTestUtils.NewOpenShiftClusterInfoMock(), eventEmitter)
+			handler.FirstUseInitiation(hco)
 
 			req := commonTestUtils.NewReq(hco)
 			err = handler.Ensure(req)
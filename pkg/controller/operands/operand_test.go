@@ -12,7 +12,7 @@ var _ = Describe("Test operator.go", func() {
 		It("Should fail for bad json", func() {
 			obj := &cdiv1beta1.CDI{}
 
-			err := applyAnnotationPatch(obj, `{]`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `{]`, "spec")
 			Expect(err).To(HaveOccurred())
 			fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
 		})
@@ -20,7 +20,7 @@ var _ = Describe("Test operator.go", func() {
 		It("Should fail for single patch object (instead of an array)", func() {
 			obj := &cdiv1beta1.CDI{}
 
-			err := applyAnnotationPatch(obj, `{"op": "add", "path": "/spec/config/featureGates/-", "value": "fg1"}`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `{"op": "add", "path": "/spec/config/featureGates/-", "value": "fg1"}`, "spec")
 			Expect(err).To(HaveOccurred())
 			fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
 		})
@@ -28,7 +28,7 @@ var _ = Describe("Test operator.go", func() {
 		It("Should fail for unknown op in a patch object", func() {
 			obj := &cdiv1beta1.CDI{}
 
-			err := applyAnnotationPatch(obj, `[{"op": "unknown", "path": "/spec/config/featureGates/-", "value": "fg1"}]`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `[{"op": "unknown", "path": "/spec/config/featureGates/-", "value": "fg1"}]`, "spec")
 			Expect(err).To(HaveOccurred())
 			fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
 		})
@@ -36,7 +36,7 @@ var _ = Describe("Test operator.go", func() {
 		It("Should fail for wrong path - not starts with '/spec/' - patch object", func() {
 			obj := &cdiv1beta1.CDI{}
 
-			err := applyAnnotationPatch(obj, `[{"op": "add", "path": "/config/featureGates/-", "value": "fg1"}]`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `[{"op": "add", "path": "/config/featureGates/-", "value": "fg1"}]`, "spec")
 			Expect(err).To(HaveOccurred())
 			fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
 		})
@@ -44,7 +44,7 @@ var _ = Describe("Test operator.go", func() {
 		It("Should fail for adding to a not exist object", func() {
 			obj := &cdiv1beta1.CDI{}
 
-			err := applyAnnotationPatch(obj, `[{"op": "add", "path": "/spec/config/filesystemOverhead/global", "value": "65"}]`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `[{"op": "add", "path": "/spec/config/filesystemOverhead/global", "value": "65"}]`, "spec")
 			Expect(err).To(HaveOccurred())
 			fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
 		})
@@ -58,7 +58,7 @@ var _ = Describe("Test operator.go", func() {
 				},
 			}
 
-			err := applyAnnotationPatch(obj, `[{"op": "remove", "path": "/spec/config/filesystemOverhead/global"}]`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `[{"op": "remove", "path": "/spec/config/filesystemOverhead/global"}]`, "spec")
 			Expect(err).To(HaveOccurred())
 			fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
 		})
@@ -72,7 +72,7 @@ var _ = Describe("Test operator.go", func() {
 				},
 			}
 
-			err := applyAnnotationPatch(obj, `[{"op": "add", "path": "/spec/config/filesystemOverhead/global", "value": "55"}]`)
+			err := applyAnnotationPatch(obj, JSONPatchType, `[{"op": "add", "path": "/spec/config/filesystemOverhead/global", "value": "55"}]`, "spec")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(obj.Spec.Config).NotTo(BeNil())
 			Expect(obj.Spec.Config.FilesystemOverhead).NotTo(BeNil())
@@ -0,0 +1,135 @@
+package operands
+
+import (
+	"fmt"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	objectreferencesv1 "github.com/openshift/custom-resource-status/objectreferences/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/reference"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// optionalOperand deploys an operand only if its component is enabled in the HyperConverged CR. If the component is
+// disabled, the resource of the operand is removed from the cluster and from the related objects.
+type optionalOperand struct {
+	Operand
+	client client.Client
+	scheme *runtime.Scheme
+	// check if the component of the operand is enabled
+	isEnabled func(hc *hcov1beta1.HyperConverged) bool
+	// get the resource to remove if the component is disabled
+	getResource func(hc *hcov1beta1.HyperConverged) client.Object
+}
+
+func newOptionalOperand(operand Operand, client client.Client, scheme *runtime.Scheme,
+	isEnabled func(hc *hcov1beta1.HyperConverged) bool, getResource func(hc *hcov1beta1.HyperConverged) client.Object) *optionalOperand {
+
+	return &optionalOperand{
+		Operand:     operand,
+		client:      client,
+		scheme:      scheme,
+		isEnabled:   isEnabled,
+		getResource: getResource,
+	}
+}
+
+func (o *optionalOperand) ensure(req *common.HcoRequest) *EnsureResult {
+	if o.isEnabled(req.Instance) {
+		return o.Operand.ensure(req)
+	}
+
+	resource := o.getResource(req.Instance)
+	res := NewEnsureResult(resource).SetName(resource.GetName()).SetUpgradeDone(req.ComponentUpgradeInProgress)
+
+	removed, err := removeResource(o.client, o.scheme, req, resource)
+	if err != nil {
+		return res.Error(err)
+	}
+
+	return res.SetDeleted(removed)
+}
+
+func (o *optionalOperand) preview(req *common.HcoRequest, hc *hcov1beta1.HyperConverged) (*OperandPreview, error) {
+	if o.isEnabled(hc) {
+		return o.Operand.preview(req, hc)
+	}
+
+	return previewRemoval(req, o.client, o.getResource(hc))
+}
+
+// removeResource deletes a resource that is no longer required, and removes it from the related objects. Returns
+// true if the resource was in the related objects, meaning it was deployed by HCO and is now removed.
+func removeResource(cl client.Client, scheme *runtime.Scheme, req *common.HcoRequest, resource client.Object) (bool, error) {
+	err := hcoutil.EnsureDeleted(req.Ctx, cl, resource, req.Instance.Name, req.Logger, false, false)
+	if err != nil {
+		return false, err
+	}
+
+	objectRef, err := reference.GetReference(scheme, resource)
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := objectreferencesv1.FindObjectReference(req.Instance.Status.RelatedObjects, *objectRef)
+	if err != nil || existing == nil {
+		return false, err
+	}
+
+	if err = objectreferencesv1.RemoveObjectReference(&req.Instance.Status.RelatedObjects, *objectRef); err != nil {
+		return false, fmt.Errorf("failed to remove %s %s from the related objects; %w", objectRef.Kind, objectRef.Name, err)
+	}
+	req.StatusDirty = true
+
+	return true, nil
+}
+
+func isNetworkAddonsEnabled(hc *hcov1beta1.HyperConverged) bool {
+	return hc.Spec.Components.IsNetworkAddonsEnabled()
+}
+
+func isVMImportEnabled(hc *hcov1beta1.HyperConverged) bool {
+	return hc.Spec.Components.IsVMImportEnabled()
+}
+
+func isSSPEnabled(hc *hcov1beta1.HyperConverged) bool {
+	return hc.Spec.Components.IsSSPEnabled()
+}
+
+func isMonitoringEnabled(hc *hcov1beta1.HyperConverged) bool {
+	return hc.Spec.Components.IsMonitoringEnabled()
+}
+
+func isQuickStartsEnabled(hc *hcov1beta1.HyperConverged) bool {
+	return hc.Spec.Components.IsQuickStartsEnabled()
+}
+
+func getNetworkAddonsResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewNetworkAddonsWithNameOnly(hc)
+}
+
+func getVMImportResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewVMImportWithNameOnly(hc)
+}
+
+func getIMSConfigResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewIMSConfigForCR(hc, hc.Namespace)
+}
+
+func getSSPResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewSSPWithNameOnly(hc)
+}
+
+func getMetricsServiceResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewMetricsService(hc, hc.Namespace)
+}
+
+func getServiceMonitorResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewServiceMonitor(hc, hc.Namespace)
+}
+
+func getPrometheusRuleResource(hc *hcov1beta1.HyperConverged) client.Object {
+	return NewPrometheusRule(hc, hc.Namespace)
+}
@@ -0,0 +1,113 @@
+package operands
+
+import (
+	"context"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	objectreferencesv1 "github.com/openshift/custom-resource-status/objectreferences/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/reference"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Optional Operand", func() {
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+
+	disabled := false
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		req = commonTestUtils.NewReq(hco)
+	})
+
+	newHandler := func(cl client.Client) *optionalOperand {
+		return newOptionalOperand((*genericOperand)(newVmImportHandler(cl, commonTestUtils.GetScheme())), cl,
+			commonTestUtils.GetScheme(), isVMImportEnabled, getVMImportResource)
+	}
+
+	It("should deploy the operand if the component is not set", func() {
+		cl := commonTestUtils.InitClient([]runtime.Object{})
+		res := newHandler(cl).ensure(req)
+		Expect(res.Err).To(BeNil())
+		Expect(res.Created).To(BeTrue())
+		Expect(res.Deleted).To(BeFalse())
+
+		foundResource := &vmimportv1beta1.VMImportConfig{}
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(NewVMImportWithNameOnly(hco)), foundResource)).To(BeNil())
+	})
+
+	It("should remove the operand if the component is disabled", func() {
+		existing, err := NewVMImportForCR(hco)
+		Expect(err).ToNot(HaveOccurred())
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+
+		objectRef, err := reference.GetReference(commonTestUtils.GetScheme(), existing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objectreferencesv1.SetObjectReference(&hco.Status.RelatedObjects, *objectRef)).To(Succeed())
+
+		hco.Spec.Components = &hcov1beta1.HyperConvergedComponents{VMImport: &disabled}
+
+		res := newHandler(cl).ensure(req)
+		Expect(res.Err).To(BeNil())
+		Expect(res.Deleted).To(BeTrue())
+		Expect(res.Created).To(BeFalse())
+		Expect(res.Type).To(Equal("VMImportConfig"))
+		Expect(res.Name).To(Equal(existing.Name))
+		Expect(req.StatusDirty).To(BeTrue())
+
+		foundResource := &vmimportv1beta1.VMImportConfig{}
+		err = cl.Get(context.TODO(), client.ObjectKeyFromObject(existing), foundResource)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		ref, err := objectreferencesv1.FindObjectReference(hco.Status.RelatedObjects, *objectRef)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ref).To(BeNil())
+	})
+
+	It("should not report a removal if the disabled operand was not deployed", func() {
+		cl := commonTestUtils.InitClient([]runtime.Object{hco})
+		hco.Spec.Components = &hcov1beta1.HyperConvergedComponents{VMImport: &disabled}
+
+		res := newHandler(cl).ensure(req)
+		Expect(res.Err).To(BeNil())
+		Expect(res.Deleted).To(BeFalse())
+		Expect(res.Created).To(BeFalse())
+		Expect(req.StatusDirty).To(BeFalse())
+	})
+
+	It("should emit an event when the operand handler removes a disabled component", func() {
+		existing, err := NewVMImportForCR(hco)
+		Expect(err).ToNot(HaveOccurred())
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+
+		objectRef, err := reference.GetReference(commonTestUtils.GetScheme(), existing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objectreferencesv1.SetObjectReference(&hco.Status.RelatedObjects, *objectRef)).To(Succeed())
+
+		hco.Spec.Components = &hcov1beta1.HyperConvergedComponents{VMImport: &disabled}
+
+		eventEmitter := commonTestUtils.NewEventEmitterMock()
+		handler := &OperandHandler{
+			client:       cl,
+			operands:     []Operand{newHandler(cl)},
+			eventEmitter: eventEmitter,
+		}
+
+		Expect(handler.Ensure(req)).To(Succeed())
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{
+			{
+				EventType: corev1.EventTypeNormal,
+				Reason:    "Killing",
+				Msg:       "Removed VMImportConfig " + existing.Name,
+			},
+		})).To(BeTrue())
+	})
+})
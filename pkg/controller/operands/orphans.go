@@ -0,0 +1,170 @@
+package operands
+
+import (
+	"fmt"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const orphanSweeperDryRunValue = "true"
+
+// managedResourceType is a type of resource that HCO deploys and labels. The orphan sweeper looks for stale resources
+// of these types.
+type managedResourceType struct {
+	gvk schema.GroupVersionKind
+	// namespaced resources are looked for in the namespace of the HyperConverged CR, and in the other namespaces
+	// where extra manifests of their kind may be deployed
+	namespaced bool
+}
+
+var managedResourceTypes = []managedResourceType{
+	{gvk: schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}, namespaced: true},
+	{gvk: schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Service"}, namespaced: true},
+	{gvk: schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}},
+	{gvk: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}},
+	{gvk: schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}, namespaced: true},
+	{gvk: schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}, namespaced: true},
+	{gvk: schema.GroupVersionKind{Group: "console.openshift.io", Version: "v1", Kind: "ConsoleQuickStart"}},
+	{gvk: schema.GroupVersionKind{Group: "console.openshift.io", Version: "v1", Kind: "ConsoleYAMLSample"}},
+	{gvk: schema.GroupVersionKind{Group: "template.openshift.io", Version: "v1", Kind: "Template"}, namespaced: true},
+	{gvk: schema.GroupVersionKind{Group: "kubevirt.io", Version: "v1", Kind: "KubeVirt"}, namespaced: true},
+	{gvk: schema.GroupVersionKind{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "CDI"}},
+	{gvk: schema.GroupVersionKind{Group: "networkaddonsoperator.network.kubevirt.io", Version: "v1", Kind: "NetworkAddonsConfig"}},
+	{gvk: schema.GroupVersionKind{Group: "v2v.kubevirt.io", Version: "v1beta1", Kind: "VMImportConfig"}},
+	{gvk: schema.GroupVersionKind{Group: "ssp.kubevirt.io", Version: "v1beta1", Kind: "SSP"}, namespaced: true},
+	{gvk: hppGroupVersionKind},
+}
+
+// renderedResources is the set of the resources that the operands rendered in the current reconciliation, keyed by
+// kind and name. The value is true if the resource was deployed, and false if its operand was skipped; the resources
+// of skipped operands are not removed as orphans.
+type renderedResources map[string]bool
+
+func renderedResourceKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+func (r renderedResources) add(res *EnsureResult) {
+	if res.Name != "" && !res.Deleted {
+		key := renderedResourceKey(res.Type, res.Name)
+		r[key] = r[key] || !res.Skipped
+	}
+}
+
+// isDeployed returns true if the resource was deployed in the current reconciliation
+func (r renderedResources) isDeployed(kind, name string) bool {
+	return r[renderedResourceKey(kind, name)]
+}
+
+func (r renderedResources) has(obj client.Object) bool {
+	_, found := r[renderedResourceKey(obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())]
+	return found
+}
+
+// sweepOrphans removes the resources that were deployed by HCO but are no longer rendered by any operand; for
+// example, quick starts that were removed from the image, renamed ConfigMaps or the resources of disabled components.
+// The orphans are found by the HCO labels, and are removed from the cluster and from the related objects.
+//
+// If the HyperConverged CR is annotated with the orphan sweeper dry-run annotation, the orphans are only reported.
+func (h OperandHandler) sweepOrphans(req *common.HcoRequest, rendered renderedResources) error {
+	dryRun := req.Instance.Annotations[common.OrphanSweeperDryRunAnnotationName] == orphanSweeperDryRunValue
+
+	orphans, err := h.findOrphans(req, rendered)
+	if err != nil {
+		return err
+	}
+
+	for _, orphan := range orphans {
+		kind := orphan.GetObjectKind().GroupVersionKind().Kind
+		if dryRun {
+			req.Logger.Info("found an orphan resource; not removing it in dry-run mode", "kind", kind, "namespace", orphan.GetNamespace(), "name", orphan.GetName())
+			h.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeNormal, "OrphanDetected", fmt.Sprintf("Found orphan %s %s", kind, orphan.GetName()))
+			continue
+		}
+
+		req.Logger.Info("removing an orphan resource", "kind", kind, "namespace", orphan.GetNamespace(), "name", orphan.GetName())
+		if err := hcoutil.EnsureDeleted(req.Ctx, h.client, orphan, req.Instance.Name, req.Logger, false, false); err != nil {
+			return err
+		}
+		h.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeNormal, "Killing", fmt.Sprintf("Removed orphan %s %s", kind, orphan.GetName()))
+
+		removeOrphanFromRelatedObjects(req, orphan)
+	}
+
+	return nil
+}
+
+// findOrphans lists the resources with the HCO labels, of all the managed resource types, and returns the ones that
+// were not rendered in the current reconciliation.
+func (h OperandHandler) findOrphans(req *common.HcoRequest, rendered renderedResources) ([]*unstructured.Unstructured, error) {
+	selector := client.MatchingLabels{
+		hcoutil.AppLabel:          req.Instance.Name,
+		hcoutil.AppLabelManagedBy: hcoutil.OperatorName,
+	}
+
+	var orphans []*unstructured.Unstructured
+	for _, resourceType := range managedResourceTypes {
+		namespaces := []string{""}
+		if resourceType.namespaced {
+			namespaces = append([]string{req.Namespace}, extraManifestKinds[resourceType.gvk.GroupKind()].namespaces...)
+		}
+
+		for _, namespace := range namespaces {
+			typeOrphans, err := h.findOrphansOfType(req, resourceType.gvk, namespace, selector, rendered)
+			if err != nil {
+				return nil, err
+			}
+			orphans = append(orphans, typeOrphans...)
+		}
+	}
+
+	return orphans, nil
+}
+
+// findOrphansOfType returns the orphans of a single resource type, in a single namespace; an empty namespace means all
+// the namespaces.
+func (h OperandHandler) findOrphansOfType(req *common.HcoRequest, gvk schema.GroupVersionKind, namespace string, selector client.MatchingLabels, rendered renderedResources) ([]*unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := h.client.List(req.Ctx, list, selector, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			// the CRD of this type is not deployed; there is nothing to sweep
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list the %s resources; %w", gvk.Kind, err)
+	}
+
+	var orphans []*unstructured.Unstructured
+	for i := range list.Items {
+		obj := &list.Items[i]
+		obj.SetGroupVersionKind(gvk)
+		if obj.GetDeletionTimestamp() == nil && !rendered.has(obj) {
+			orphans = append(orphans, obj)
+		}
+	}
+
+	return orphans, nil
+}
+
+// removeOrphanFromRelatedObjects removes the references to the orphan, in any API version, from the related objects
+func removeOrphanFromRelatedObjects(req *common.HcoRequest, orphan client.Object) {
+	gk := orphan.GetObjectKind().GroupVersionKind().GroupKind()
+
+	relatedObjects := make([]corev1.ObjectReference, 0, len(req.Instance.Status.RelatedObjects))
+	for _, ref := range req.Instance.Status.RelatedObjects {
+		if ref.GroupVersionKind().GroupKind() == gk && ref.Name == orphan.GetName() && ref.Namespace == orphan.GetNamespace() {
+			req.StatusDirty = true
+			continue
+		}
+		relatedObjects = append(relatedObjects, ref)
+	}
+
+	req.Instance.Status.RelatedObjects = relatedObjects
+}
@@ -0,0 +1,162 @@
+package operands
+
+import (
+	"context"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	consolev1 "github.com/openshift/api/console/v1"
+	objectreferencesv1 "github.com/openshift/custom-resource-status/objectreferences/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/reference"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Orphan sweeper", func() {
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+	var eventEmitter *commonTestUtils.EventEmitterMock
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		req = commonTestUtils.NewReq(hco)
+		eventEmitter = commonTestUtils.NewEventEmitterMock()
+	})
+
+	newStaleQuickStart := func(name string) *consolev1.ConsoleQuickStart {
+		return &consolev1.ConsoleQuickStart{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: getLabels(hco, hcoutil.AppComponentCompute),
+			},
+		}
+	}
+
+	newStaleConfigMap := func(name, namespace string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    getLabels(hco, hcoutil.AppComponentCompute),
+			},
+		}
+	}
+
+	addToRelatedObjects := func(obj client.Object) {
+		objectRef, err := reference.GetReference(commonTestUtils.GetScheme(), obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objectreferencesv1.SetObjectReference(&hco.Status.RelatedObjects, *objectRef)).To(Succeed())
+	}
+
+	newHandler := func(cl client.Client) *OperandHandler {
+		return &OperandHandler{
+			client: cl,
+			operands: []Operand{
+				(*genericOperand)(newKvPriorityClassHandler(cl, commonTestUtils.GetScheme())),
+			},
+			eventEmitter: eventEmitter,
+		}
+	}
+
+	isFound := func(cl client.Client, obj client.Object) bool {
+		err := cl.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	It("should remove the stale resources and drop them from the related objects", func() {
+		staleQs := newStaleQuickStart("removed-quick-start")
+		staleCm := newStaleConfigMap("renamed-config-map", hco.Namespace)
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, NewKubeVirtPriorityClass(hco), staleQs, staleCm})
+		addToRelatedObjects(staleQs)
+		addToRelatedObjects(staleCm)
+
+		Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+		Expect(isFound(cl, staleQs)).To(BeFalse())
+		Expect(isFound(cl, staleCm)).To(BeFalse())
+		Expect(isFound(cl, NewKubeVirtPriorityClass(hco))).To(BeTrue())
+
+		Expect(req.StatusDirty).To(BeTrue())
+		Expect(hco.Status.RelatedObjects).To(HaveLen(1))
+		Expect(hco.Status.RelatedObjects[0].Kind).To(Equal("PriorityClass"))
+
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{
+			{
+				EventType: corev1.EventTypeNormal,
+				Reason:    "Killing",
+				Msg:       "Removed orphan ConfigMap renamed-config-map",
+			},
+			{
+				EventType: corev1.EventTypeNormal,
+				Reason:    "Killing",
+				Msg:       "Removed orphan ConsoleQuickStart removed-quick-start",
+			},
+		})).To(BeTrue())
+	})
+
+	It("should not remove resources that are not managed by HCO", func() {
+		notManagedCm := newStaleConfigMap("not-managed", hco.Namespace)
+		notManagedCm.Labels = map[string]string{hcoutil.AppLabel: hco.Name}
+
+		otherHcoCm := newStaleConfigMap("other-hco", hco.Namespace)
+		otherHcoCm.Labels[hcoutil.AppLabel] = "other-hyperconverged"
+
+		otherNamespaceCm := newStaleConfigMap("other-namespace", "other-namespace")
+
+		notManagedPc := &schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "not-managed"}}
+
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, notManagedCm, otherHcoCm, otherNamespaceCm, notManagedPc})
+
+		Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+		Expect(isFound(cl, notManagedCm)).To(BeTrue())
+		Expect(isFound(cl, otherHcoCm)).To(BeTrue())
+		Expect(isFound(cl, otherNamespaceCm)).To(BeTrue())
+		Expect(isFound(cl, notManagedPc)).To(BeTrue())
+	})
+
+	It("should only report the stale resources in dry-run mode", func() {
+		hco.Annotations = map[string]string{common.OrphanSweeperDryRunAnnotationName: "true"}
+		staleQs := newStaleQuickStart("removed-quick-start")
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, NewKubeVirtPriorityClass(hco), staleQs})
+		addToRelatedObjects(staleQs)
+
+		Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+		Expect(isFound(cl, staleQs)).To(BeTrue())
+		Expect(hco.Status.RelatedObjects).To(HaveLen(2))
+
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{
+			{
+				EventType: corev1.EventTypeNormal,
+				Reason:    "OrphanDetected",
+				Msg:       "Found orphan ConsoleQuickStart removed-quick-start",
+			},
+		})).To(BeTrue())
+	})
+
+	It("should not remove the stale resources while the upgrade is in progress", func() {
+		staleCm := newStaleConfigMap("renamed-config-map", hco.Namespace)
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, staleCm})
+
+		req.SetUpgradeMode(true)
+		handler := newHandler(cl)
+		handler.operands = append(handler.operands, (*genericOperand)(newCdiHandler(cl, commonTestUtils.GetScheme())))
+
+		Expect(handler.Ensure(req)).To(Succeed())
+		Expect(req.ComponentUpgradeInProgress).To(BeFalse())
+		Expect(isFound(cl, staleCm)).To(BeTrue())
+	})
+})
@@ -0,0 +1,428 @@
+package operands
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+)
+
+// The types of the patch annotations
+const (
+	JSONPatchType           = "json"
+	MergePatchType          = "merge"
+	StrategicMergePatchType = "strategic"
+)
+
+var patchTypeNames = map[string]string{
+	JSONPatchType:           "jsonPatch",
+	MergePatchType:          "mergePatch",
+	StrategicMergePatchType: "strategicPatch",
+}
+
+// patchTarget is an operand resource that can be patched by the patch annotations of the HyperConverged CR, with
+// unsupported/unofficial/hidden configurations
+type patchTarget struct {
+	// the kind of the patched resource
+	kind string
+	// the top level field that the patches may modify; spec for the CRs, and data for the ConfigMaps
+	field string
+	// the name of the patched resource
+	getName func(hc *hcov1beta1.HyperConverged) string
+	// the names of the patch annotations, one for each patch type
+	jsonPatch      string
+	mergePatch     string
+	strategicPatch string
+}
+
+type patchAnnotation struct {
+	name      string
+	patchType string
+}
+
+// annotations returns the patch annotations of the target, in the order they are applied
+func (t patchTarget) annotations() []patchAnnotation {
+	return []patchAnnotation{
+		{name: t.jsonPatch, patchType: JSONPatchType},
+		{name: t.mergePatch, patchType: MergePatchType},
+		{name: t.strategicPatch, patchType: StrategicMergePatchType},
+	}
+}
+
+var (
+	kubeVirtPatchTarget = patchTarget{
+		kind:           "KubeVirt",
+		field:          "spec",
+		getName:        func(hc *hcov1beta1.HyperConverged) string { return NewKubeVirtWithNameOnly(hc).Name },
+		jsonPatch:      common.JSONPatchKVAnnotationName,
+		mergePatch:     common.MergePatchKVAnnotationName,
+		strategicPatch: common.StrategicPatchKVAnnotationName,
+	}
+
+	cdiPatchTarget = patchTarget{
+		kind:           "CDI",
+		field:          "spec",
+		getName:        func(hc *hcov1beta1.HyperConverged) string { return NewCDIWithNameOnly(hc).Name },
+		jsonPatch:      common.JSONPatchCDIAnnotationName,
+		mergePatch:     common.MergePatchCDIAnnotationName,
+		strategicPatch: common.StrategicPatchCDIAnnotationName,
+	}
+
+	cnaPatchTarget = patchTarget{
+		kind:           "NetworkAddonsConfig",
+		field:          "spec",
+		getName:        func(hc *hcov1beta1.HyperConverged) string { return NewNetworkAddonsWithNameOnly(hc).Name },
+		jsonPatch:      common.JSONPatchCNAOAnnotationName,
+		mergePatch:     common.MergePatchCNAOAnnotationName,
+		strategicPatch: common.StrategicPatchCNAOAnnotationName,
+	}
+
+	sspPatchTarget = patchTarget{
+		kind:           "SSP",
+		field:          "spec",
+		getName:        func(hc *hcov1beta1.HyperConverged) string { return NewSSPWithNameOnly(hc).Name },
+		jsonPatch:      common.JSONPatchSSPAnnotationName,
+		mergePatch:     common.MergePatchSSPAnnotationName,
+		strategicPatch: common.StrategicPatchSSPAnnotationName,
+	}
+
+	vmImportPatchTarget = patchTarget{
+		kind:           "VMImportConfig",
+		field:          "spec",
+		getName:        func(hc *hcov1beta1.HyperConverged) string { return NewVMImportWithNameOnly(hc).Name },
+		jsonPatch:      common.JSONPatchVMImportAnnotationName,
+		mergePatch:     common.MergePatchVMImportAnnotationName,
+		strategicPatch: common.StrategicPatchVMImportAnnotationName,
+	}
+
+	kvConfigPatchTarget = patchTarget{
+		kind:           "ConfigMap",
+		field:          "data",
+		getName:        func(*hcov1beta1.HyperConverged) string { return KubeVirtConfigMapName },
+		jsonPatch:      common.JSONPatchKVConfigAnnotationName,
+		mergePatch:     common.MergePatchKVConfigAnnotationName,
+		strategicPatch: common.StrategicPatchKVConfigAnnotationName,
+	}
+
+	storageConfigPatchTarget = patchTarget{
+		kind:           "ConfigMap",
+		field:          "data",
+		getName:        func(*hcov1beta1.HyperConverged) string { return storageConfigMapName },
+		jsonPatch:      common.JSONPatchStorageConfigAnnotationName,
+		mergePatch:     common.MergePatchStorageConfigAnnotationName,
+		strategicPatch: common.StrategicPatchStorageConfigAnnotationName,
+	}
+
+	imsConfigPatchTarget = patchTarget{
+		kind:           "ConfigMap",
+		field:          "data",
+		getName:        func(*hcov1beta1.HyperConverged) string { return imsConfigMapName },
+		jsonPatch:      common.JSONPatchIMSConfigAnnotationName,
+		mergePatch:     common.MergePatchIMSConfigAnnotationName,
+		strategicPatch: common.StrategicPatchIMSConfigAnnotationName,
+	}
+)
+
+// patchTargets is the list of all the patch targets, with the function that renders each patched resource
+var patchTargets = []struct {
+	patchTarget
+	render func(hc *hcov1beta1.HyperConverged) (runtime.Object, error)
+}{
+	{kubeVirtPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) { return NewKubeVirt(hc) }},
+	{cdiPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) { return NewCDI(hc) }},
+	{cnaPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) { return NewNetworkAddons(hc) }},
+	{sspPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) { return NewSSP(hc) }},
+	{vmImportPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) { return NewVMImportForCR(hc) }},
+	{kvConfigPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+		return newPatchedConfigMap(hc, kvConfigPatchTarget, NewKubeVirtConfigForCR(hc, hc.Namespace))
+	}},
+	{storageConfigPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+		return newPatchedConfigMap(hc, storageConfigPatchTarget, NewKubeVirtStorageConfigForCR(hc, hc.Namespace))
+	}},
+	{imsConfigPatchTarget, func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+		return newPatchedConfigMap(hc, imsConfigPatchTarget, NewIMSConfigForCR(hc, hc.Namespace))
+	}},
+}
+
+// GetPatchAnnotationNames returns the names of all the patch annotations, in a stable order
+func GetPatchAnnotationNames() []string {
+	names := make([]string, 0, len(patchTargets)*3)
+	for _, target := range patchTargets {
+		for _, annotation := range target.annotations() {
+			names = append(names, annotation.name)
+		}
+	}
+	return names
+}
+
+// GetPatchAnnotationNamesOfKind returns the names of the patch annotations of the resources of the given kind, in a
+// stable order
+func GetPatchAnnotationNamesOfKind(kind string) []string {
+	var names []string
+	for _, target := range patchTargets {
+		if target.kind != kind {
+			continue
+		}
+		for _, annotation := range target.annotations() {
+			names = append(names, annotation.name)
+		}
+	}
+	return names
+}
+
+// GetActivePatchAnnotations returns the names of the patch annotations that are set in the HyperConverged CR, in a
+// stable order
+func GetActivePatchAnnotations(hc *hcov1beta1.HyperConverged) []string {
+	var active []string
+	for _, name := range GetPatchAnnotationNames() {
+		if _, exists := hc.Annotations[name]; exists {
+			active = append(active, name)
+		}
+	}
+	return active
+}
+
+// ValidatePatchAnnotation applies a patch annotation of the HyperConverged CR to the resource that it patches, without
+// the other patch annotations, and returns an error if the patch can't be applied
+func ValidatePatchAnnotation(hc *hcov1beta1.HyperConverged, name string) error {
+	for _, target := range patchTargets {
+		for _, annotation := range target.annotations() {
+			if annotation.name != name {
+				continue
+			}
+
+			patched := hc.DeepCopy()
+			patched.Annotations = map[string]string{name: hc.Annotations[name]}
+			_, err := target.render(patched)
+			return err
+		}
+	}
+
+	return fmt.Errorf("unknown patch annotation %s", name)
+}
+
+// ValidatePatchAnnotations validates all the patch annotations of the HyperConverged CR, and returns the errors of all
+// the patches that can't be applied
+func ValidatePatchAnnotations(hc *hcov1beta1.HyperConverged) error {
+	var errs []error
+	for _, name := range GetActivePatchAnnotations(hc) {
+		if err := ValidatePatchAnnotation(hc, name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// applyPatchAnnotations applies the patch annotations of the HyperConverged CR to the rendered resource of the
+// target; first the JSON patch, then the JSON merge patch, and then the strategic merge patch
+func applyPatchAnnotations(hc *hcov1beta1.HyperConverged, target patchTarget, obj runtime.Object) error {
+	for _, annotation := range target.annotations() {
+		patch, ok := hc.Annotations[annotation.name]
+		if !ok {
+			continue
+		}
+
+		if err := applyAnnotationPatch(obj, annotation.patchType, patch, target.field); err != nil {
+			return fmt.Errorf("invalid %s in the %s annotation: %v", patchTypeNames[annotation.patchType], annotation.name, err)
+		}
+	}
+
+	return nil
+}
+
+// newPatchedConfigMap applies the patch annotations of the target to a rendered ConfigMap
+func newPatchedConfigMap(hc *hcov1beta1.HyperConverged, target patchTarget, cm *corev1.ConfigMap) (client.Object, error) {
+	if err := applyPatchAnnotations(hc, target, cm); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// applyAnnotationPatch applies a patch of the given type to the object. The patch may only modify the fields under
+// the given top level field.
+func applyAnnotationPatch(obj runtime.Object, patchType, patch, field string) error {
+	objBytes, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	var patchedBytes []byte
+	switch patchType {
+	case JSONPatchType:
+		patchedBytes, err = applyJSONPatch(objBytes, patch, field)
+	case MergePatchType:
+		if err = validatePatchFields(patch, field); err == nil {
+			patchedBytes, err = jsonpatch.MergePatch(objBytes, []byte(patch))
+		}
+	case StrategicMergePatchType:
+		if err = validatePatchFields(patch, field); err == nil {
+			patchedBytes, err = strategicpatch.StrategicMergePatch(objBytes, []byte(patch), obj)
+		}
+	default:
+		err = fmt.Errorf("unknown patch type %s", patchType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// reset the object first, so the fields that were removed by the patch are removed from the object as well
+	value := reflect.ValueOf(obj).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(patchedBytes, obj)
+}
+
+func applyJSONPatch(objBytes []byte, patch string, field string) ([]byte, error) {
+	patches, err := jsonpatch.DecodePatch([]byte(patch))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, patch := range patches {
+		path, err := patch.Path()
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(path, "/"+field+"/") {
+			return nil, fmt.Errorf("can only modify %s fields", field)
+		}
+	}
+
+	return patches.Apply(objBytes)
+}
+
+// validatePatchFields checks that a merge patch only modifies the given top level field
+func validatePatchFields(patch string, field string) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(patch), &fields); err != nil {
+		return err
+	}
+
+	for key := range fields {
+		if key != field {
+			return fmt.Errorf("can only modify %s fields", field)
+		}
+	}
+
+	return nil
+}
+
+// updatePatchedConfigMapData updates the data keys of the found ConfigMap that the patch annotations add, modify or
+// remove, by comparing the required (patched) and the unpatched renderings of the ConfigMap. The patched keys are
+// recorded in an annotation of the ConfigMap, so the keys that are no longer patched, because the patch or the whole
+// annotation was removed, are reverted to their unpatched value, or removed. It returns true if found was modified.
+func updatePatchedConfigMapData(found, required, unpatched *corev1.ConfigMap) bool {
+	patched := make(map[string]bool)
+	for key, value := range required.Data {
+		if orig, ok := unpatched.Data[key]; !ok || orig != value {
+			patched[key] = true
+		}
+	}
+	for key := range unpatched.Data {
+		if _, ok := required.Data[key]; !ok {
+			patched[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(patched))
+	for key := range patched {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changed := false
+	toReconcile := append(getPatchedKeys(found), keys...)
+	for _, key := range toReconcile {
+		value, isRequired := required.Data[key]
+		current, exists := found.Data[key]
+		switch {
+		case isRequired && (!exists || current != value):
+			if found.Data == nil {
+				found.Data = make(map[string]string)
+			}
+			found.Data[key] = value
+			changed = true
+		case !isRequired && exists:
+			delete(found.Data, key)
+			changed = true
+		}
+	}
+
+	if setPatchedKeys(found, keys) {
+		changed = true
+	}
+
+	return changed
+}
+
+func getPatchedKeys(cm *corev1.ConfigMap) []string {
+	value := cm.Annotations[common.PatchedKeysAnnotationName]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// setPatchedKeys records the patched keys in the ConfigMap, and returns true if they were changed
+func setPatchedKeys(cm *corev1.ConfigMap, keys []string) bool {
+	value := strings.Join(keys, ",")
+	if cm.Annotations[common.PatchedKeysAnnotationName] == value {
+		return false
+	}
+
+	if value == "" {
+		delete(cm.Annotations, common.PatchedKeysAnnotationName)
+		return true
+	}
+
+	if cm.Annotations == nil {
+		cm.Annotations = make(map[string]string)
+	}
+	cm.Annotations[common.PatchedKeysAnnotationName] = value
+	return true
+}
+
+// getAppliedPatches returns the patch annotations of the HyperConverged CR that were applied to the resources that
+// were deployed in the current reconciliation
+func getAppliedPatches(hc *hcov1beta1.HyperConverged, rendered renderedResources) []hcov1beta1.AppliedPatchStatus {
+	var applied []hcov1beta1.AppliedPatchStatus
+	for _, target := range patchTargets {
+		name := target.getName(hc)
+		if !rendered.isDeployed(target.kind, name) {
+			continue
+		}
+
+		for _, annotation := range target.annotations() {
+			if _, exists := hc.Annotations[annotation.name]; exists {
+				applied = append(applied, hcov1beta1.AppliedPatchStatus{
+					Annotation: annotation.name,
+					Type:       annotation.patchType,
+					Kind:       target.kind,
+					Name:       name,
+				})
+			}
+		}
+	}
+	return applied
+}
+
+// updateAppliedPatches records the applied patch annotations in the status of the HyperConverged CR
+func updateAppliedPatches(req *common.HcoRequest, rendered renderedResources) {
+	applied := getAppliedPatches(req.Instance, rendered)
+	if !reflect.DeepEqual(applied, req.Instance.Status.AppliedPatches) {
+		req.Instance.Status.AppliedPatches = applied
+		req.StatusDirty = true
+	}
+}
@@ -0,0 +1,285 @@
+package operands
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+)
+
+var _ = Describe("Patch annotations", func() {
+	var hco *hcov1beta1.HyperConverged
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+	})
+
+	Context("applyAnnotationPatch", func() {
+		It("should apply a JSON merge patch", func() {
+			hco.Annotations = map[string]string{
+				common.MergePatchSSPAnnotationName: `{"spec": {"templateValidator": {"replicas": 3}}}`,
+			}
+
+			ssp, err := NewSSP(hco)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*ssp.Spec.TemplateValidator.Replicas).To(BeEquivalentTo(3))
+			Expect(ssp.Spec.CommonTemplates.Namespace).To(Equal(defaultCommonTemplatesNamespace))
+		})
+
+		It("should apply a strategic merge patch", func() {
+			hco.Annotations = map[string]string{
+				common.StrategicPatchIMSConfigAnnotationName: `{"data": {"kubevirt-vmware-image-pull-policy": "Always"}}`,
+			}
+
+			cm, err := newPatchedConfigMap(hco, imsConfigPatchTarget, NewIMSConfigForCR(hco, hco.Namespace))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cm.(*corev1.ConfigMap).Data).To(HaveKeyWithValue("kubevirt-vmware-image-pull-policy", "Always"))
+			Expect(cm.(*corev1.ConfigMap).Data).To(HaveKey("v2v-conversion-image"))
+		})
+
+		It("should apply a JSON patch to the newly supported operands", func() {
+			hco.Annotations = map[string]string{
+				common.JSONPatchVMImportAnnotationName: `[{"op": "add", "path": "/spec/imagePullPolicy", "value": "Always"}]`,
+			}
+
+			vmImport, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vmImport.Spec.ImagePullPolicy).To(Equal(corev1.PullAlways))
+		})
+
+		It("should remove the fields that are removed by the patch", func() {
+			hco.Annotations = map[string]string{
+				common.MergePatchKVConfigAnnotationName: `{"data": {"selinuxLauncherType": null}}`,
+			}
+
+			cm, err := newPatchedConfigMap(hco, kvConfigPatchTarget, NewKubeVirtConfigForCR(hco, hco.Namespace))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cm.(*corev1.ConfigMap).Data).ToNot(HaveKey(SELinuxLauncherTypeKey))
+			Expect(cm.(*corev1.ConfigMap).Data).To(HaveKey(FeatureGatesKey))
+		})
+
+		It("should apply the JSON patch, then the merge patch and then the strategic merge patch", func() {
+			hco.Annotations = map[string]string{
+				common.JSONPatchStorageConfigAnnotationName:      `[{"op": "replace", "path": "/data/accessMode", "value": "json"}]`,
+				common.MergePatchStorageConfigAnnotationName:     `{"data": {"accessMode": "merge", "volumeMode": "merge"}}`,
+				common.StrategicPatchStorageConfigAnnotationName: `{"data": {"volumeMode": "strategic"}}`,
+			}
+
+			cm, err := newPatchedConfigMap(hco, storageConfigPatchTarget, NewKubeVirtStorageConfigForCR(hco, hco.Namespace))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cm.(*corev1.ConfigMap).Data).To(HaveKeyWithValue("accessMode", "merge"))
+			Expect(cm.(*corev1.ConfigMap).Data).To(HaveKeyWithValue("volumeMode", "strategic"))
+		})
+
+		DescribeTable("should only allow modifying the spec of the CRs and the data of the ConfigMaps",
+			func(annotation, patch, message string) {
+				hco.Annotations = map[string]string{annotation: patch}
+
+				err := ValidatePatchAnnotation(hco, annotation)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("in the " + annotation + " annotation"))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("merge patch of a CR", common.MergePatchKVAnnotationName, `{"metadata": {"name": "other"}}`, "can only modify spec fields"),
+			Entry("strategic merge patch of a CR", common.StrategicPatchCDIAnnotationName, `{"status": {}}`, "can only modify spec fields"),
+			Entry("JSON patch of a ConfigMap", common.JSONPatchKVConfigAnnotationName, `[{"op": "add", "path": "/metadata/labels/a", "value": "b"}]`, "can only modify data fields"),
+			Entry("merge patch of a ConfigMap", common.MergePatchIMSConfigAnnotationName, `{"binaryData": {}}`, "can only modify data fields"),
+		)
+
+		DescribeTable("should name the patch type in the error",
+			func(annotation, patchType string) {
+				hco.Annotations = map[string]string{annotation: "not a json"}
+
+				err := ValidatePatchAnnotation(hco, annotation)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("invalid " + patchType + " in the " + annotation + " annotation"))
+			},
+			Entry("JSON patch", common.JSONPatchSSPAnnotationName, "jsonPatch"),
+			Entry("merge patch", common.MergePatchSSPAnnotationName, "mergePatch"),
+			Entry("strategic merge patch", common.StrategicPatchSSPAnnotationName, "strategicPatch"),
+		)
+	})
+
+	Context("ValidatePatchAnnotations", func() {
+		It("should return the errors of all the invalid annotations", func() {
+			hco.Annotations = map[string]string{
+				common.JSONPatchKVAnnotationName:                 "[]",
+				common.MergePatchVMImportAnnotationName:          "not a json",
+				common.StrategicPatchKVConfigAnnotationName:      `{"metadata": {}}`,
+				common.StrategicPatchStorageConfigAnnotationName: `{"data": {"accessMode": "ReadWriteMany"}}`,
+			}
+
+			err := ValidatePatchAnnotations(hco)
+			Expect(err).To(HaveOccurred())
+			aggregated, ok := err.(utilerrors.Aggregate)
+			Expect(ok).To(BeTrue())
+			Expect(aggregated.Errors()).To(HaveLen(2))
+			Expect(aggregated.Errors()[0].Error()).To(ContainSubstring(common.MergePatchVMImportAnnotationName))
+			Expect(aggregated.Errors()[1].Error()).To(ContainSubstring(common.StrategicPatchKVConfigAnnotationName))
+		})
+
+		It("should list the active annotations in a stable order", func() {
+			hco.Annotations = map[string]string{
+				common.StrategicPatchIMSConfigAnnotationName: "{}",
+				common.MergePatchKVAnnotationName:            "{}",
+				common.JSONPatchKVAnnotationName:             "[]",
+				"another.annotation":                         "true",
+			}
+
+			Expect(GetActivePatchAnnotations(hco)).To(Equal([]string{
+				common.JSONPatchKVAnnotationName,
+				common.MergePatchKVAnnotationName,
+				common.StrategicPatchIMSConfigAnnotationName,
+			}))
+		})
+	})
+
+	Context("applied patches", func() {
+		It("should only record the patches of the deployed resources", func() {
+			hco.Annotations = map[string]string{
+				common.JSONPatchCDIAnnotationName:   "[]",
+				common.MergePatchCNAOAnnotationName: "{}",
+			}
+
+			rendered := renderedResources{}
+			rendered.add(&EnsureResult{Type: "CDI", Name: NewCDIWithNameOnly(hco).Name})
+
+			Expect(getAppliedPatches(hco, rendered)).To(Equal([]hcov1beta1.AppliedPatchStatus{
+				{Annotation: common.JSONPatchCDIAnnotationName, Type: JSONPatchType, Kind: "CDI", Name: NewCDIWithNameOnly(hco).Name},
+			}))
+		})
+
+		It("should not record the patches of the skipped resources", func() {
+			hco.Annotations = map[string]string{common.JSONPatchSSPAnnotationName: "[]"}
+
+			rendered := renderedResources{}
+			rendered.add(&EnsureResult{Type: "SSP", Name: NewSSPWithNameOnly(hco).Name, Skipped: true})
+
+			Expect(getAppliedPatches(hco, rendered)).To(BeEmpty())
+			// but the resource is not an orphan
+			Expect(rendered).To(HaveKey(renderedResourceKey("SSP", NewSSPWithNameOnly(hco).Name)))
+		})
+
+		It("should update the status only if the applied patches were changed", func() {
+			hco.Annotations = map[string]string{common.JSONPatchCDIAnnotationName: "[]"}
+			req := commonTestUtils.NewReq(hco)
+
+			rendered := renderedResources{}
+			rendered.add(&EnsureResult{Type: "CDI", Name: NewCDIWithNameOnly(hco).Name})
+
+			updateAppliedPatches(req, rendered)
+			Expect(req.StatusDirty).To(BeTrue())
+			Expect(hco.Status.AppliedPatches).To(HaveLen(1))
+
+			req.StatusDirty = false
+			updateAppliedPatches(req, rendered)
+			Expect(req.StatusDirty).To(BeFalse())
+
+			hco.Annotations = nil
+			updateAppliedPatches(req, rendered)
+			Expect(req.StatusDirty).To(BeTrue())
+			Expect(hco.Status.AppliedPatches).To(BeNil())
+		})
+	})
+
+	It("should reconcile the keys of the kubevirt-config ConfigMap that are set by a patch", func() {
+		existing := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
+		existing.Data[NetworkInterfaceKey] = "bridge"
+
+		hco.Annotations = map[string]string{
+			common.MergePatchKVConfigAnnotationName: `{"data": {"default-network-interface": "slirp", "debug.useEmulation": "true"}}`,
+		}
+
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+		handler := (*genericOperand)(newKvConfigHandler(cl, commonTestUtils.GetScheme()))
+		res := handler.ensure(commonTestUtils.NewReq(hco))
+		Expect(res.Err).ToNot(HaveOccurred())
+		Expect(res.Updated).To(BeTrue())
+
+		found := &corev1.ConfigMap{}
+		Expect(cl.Get(context.TODO(), types.NamespacedName{Name: existing.Name, Namespace: existing.Namespace}, found)).To(Succeed())
+		Expect(found.Data).To(HaveKeyWithValue(NetworkInterfaceKey, "slirp"))
+		Expect(found.Data).To(HaveKeyWithValue(UseEmulationKey, "true"))
+	})
+
+	Context("patched ConfigMap keys", func() {
+		getConfigMap := func(cl client.Client, name string) *corev1.ConfigMap {
+			found := &corev1.ConfigMap{}
+			Expect(cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: commonTestUtils.Namespace}, found)).To(Succeed())
+			return found
+		}
+
+		It("should remove the keys that are removed by a JSON patch, and restore them once the patch is removed", func() {
+			existing := NewKubeVirtConfigForCR(hco, commonTestUtils.Namespace)
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := (*genericOperand)(newKvConfigHandler(cl, commonTestUtils.GetScheme()))
+
+			hco.Annotations = map[string]string{
+				common.JSONPatchKVConfigAnnotationName: `[{"op": "remove", "path": "/data/` + SELinuxLauncherTypeKey + `"}]`,
+			}
+			res := handler.ensure(commonTestUtils.NewReq(hco))
+			Expect(res.Err).ToNot(HaveOccurred())
+
+			found := getConfigMap(cl, existing.Name)
+			Expect(found.Data).ToNot(HaveKey(SELinuxLauncherTypeKey))
+			Expect(found.Annotations).To(HaveKeyWithValue(common.PatchedKeysAnnotationName, SELinuxLauncherTypeKey))
+
+			hco.Annotations = nil
+			res = handler.ensure(commonTestUtils.NewReq(hco))
+			Expect(res.Err).ToNot(HaveOccurred())
+
+			found = getConfigMap(cl, existing.Name)
+			Expect(found.Data).To(HaveKeyWithValue(SELinuxLauncherTypeKey, existing.Data[SELinuxLauncherTypeKey]))
+			Expect(found.Annotations).ToNot(HaveKey(common.PatchedKeysAnnotationName))
+		})
+
+		It("should remove the keys that are set to null by a merge patch", func() {
+			existing := NewKubeVirtStorageConfigForCR(hco, commonTestUtils.Namespace)
+			existing.Data["local-sc.accessMode"] = "ReadWriteOnce"
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := (*genericOperand)(newStorageConfigHandler(cl, commonTestUtils.GetScheme()))
+
+			hco.Annotations = map[string]string{
+				common.MergePatchStorageConfigAnnotationName: `{"data": {"accessMode": null}}`,
+			}
+			res := handler.ensure(commonTestUtils.NewReq(hco))
+			Expect(res.Err).ToNot(HaveOccurred())
+
+			found := getConfigMap(cl, existing.Name)
+			Expect(found.Data).ToNot(HaveKey("accessMode"))
+			// a key that was not set by HCO is kept
+			Expect(found.Data).To(HaveKeyWithValue("local-sc.accessMode", "ReadWriteOnce"))
+		})
+
+		It("should remove the keys that were added by a removed patch annotation", func() {
+			existing := NewIMSConfigForCR(hco, commonTestUtils.Namespace)
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, existing})
+			handler := (*genericOperand)(newImsConfigHandler(cl, commonTestUtils.GetScheme()))
+
+			hco.Annotations = map[string]string{
+				common.StrategicPatchIMSConfigAnnotationName: `{"data": {"added-key": "value"}}`,
+			}
+			res := handler.ensure(commonTestUtils.NewReq(hco))
+			Expect(res.Err).ToNot(HaveOccurred())
+			Expect(getConfigMap(cl, existing.Name).Data).To(HaveKeyWithValue("added-key", "value"))
+
+			hco.Annotations = nil
+			res = handler.ensure(commonTestUtils.NewReq(hco))
+			Expect(res.Err).ToNot(HaveOccurred())
+
+			found := getConfigMap(cl, existing.Name)
+			Expect(found.Data).ToNot(HaveKey("added-key"))
+			Expect(found.Annotations).ToNot(HaveKey(common.PatchedKeysAnnotationName))
+		})
+	})
+})
@@ -0,0 +1,276 @@
+package operands
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+const (
+	// PreviewConfigMapName is the name of the ConfigMap, in the namespace of the HyperConverged CR, that holds the
+	// result of the preview
+	PreviewConfigMapName = "hco-preview"
+
+	previewErrorKey = "error"
+
+	previewActionCreate = "create"
+	previewActionUpdate = "update"
+	previewActionDelete = "delete"
+)
+
+// the top level fields of a resource that are not compared by the preview
+var previewUnmanagedFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+	"status":     true,
+}
+
+// OperandPreview is the difference between a resource that an operand would deploy for the previewed HyperConverged
+// CR, and the resource that is currently deployed.
+type OperandPreview struct {
+	Kind    string          `json:"kind"`
+	Name    string          `json:"name"`
+	Action  string          `json:"action"`
+	Changes []PreviewChange `json:"changes,omitempty"`
+}
+
+// PreviewChange is a field that would be changed. A missing value means that the field is missing in the live
+// resource, or that it would be removed.
+type PreviewChange struct {
+	Path    string      `json:"path"`
+	Live    interface{} `json:"live,omitempty"`
+	Preview interface{} `json:"preview,omitempty"`
+}
+
+// getPreviewHyperConverged returns a copy of the HyperConverged CR, with the JSON merge patch of the preview annotation
+func getPreviewHyperConverged(hc *hcov1beta1.HyperConverged, patch string) (*hcov1beta1.HyperConverged, error) {
+	hcBytes, err := json.Marshal(hc)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedBytes, err := jsonpatch.MergePatch(hcBytes, []byte(patch))
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge patch in the %s annotation: %v", common.PreviewAnnotationName, err)
+	}
+
+	preview := &hcov1beta1.HyperConverged{}
+	if err = json.Unmarshal(patchedBytes, preview); err != nil {
+		return nil, fmt.Errorf("invalid merge patch in the %s annotation: %v", common.PreviewAnnotationName, err)
+	}
+
+	// the identity of the CR can't be previewed
+	preview.Name = hc.Name
+	preview.Namespace = hc.Namespace
+	delete(preview.Annotations, common.PreviewAnnotationName)
+
+	return preview, nil
+}
+
+// updatePreview renders the operands for the HyperConverged CR of the preview annotation, compares them with the live
+// resources and writes the differences to the preview ConfigMap. Nothing else is modified. Once the annotation is
+// removed, the ConfigMap is removed by the orphan sweeper.
+func (h OperandHandler) updatePreview(req *common.HcoRequest, operands []Operand, rendered renderedResources) error {
+	patch, ok := req.Instance.Annotations[common.PreviewAnnotationName]
+	if !ok {
+		return nil
+	}
+
+	data := make(map[string]string)
+	hc, err := getPreviewHyperConverged(req.Instance, patch)
+	if err != nil {
+		data[previewErrorKey] = err.Error()
+	} else {
+		var previewErrors []string
+		for _, operand := range operands {
+			p, err := operand.preview(req, hc)
+			if err != nil {
+				previewErrors = append(previewErrors, err.Error())
+				continue
+			}
+			if p == nil {
+				continue
+			}
+
+			content, err := yaml.Marshal(p)
+			if err != nil {
+				return err
+			}
+			data[p.Kind+"."+p.Name] = string(content)
+		}
+
+		if len(previewErrors) > 0 {
+			sort.Strings(previewErrors)
+			content, err := yaml.Marshal(previewErrors)
+			if err != nil {
+				return err
+			}
+			data[previewErrorKey] = string(content)
+		}
+	}
+
+	cm := newPreviewConfigMap(req.Instance, data)
+	rendered[renderedResourceKey("ConfigMap", cm.Name)] = true
+
+	found := &corev1.ConfigMap{}
+	err = h.client.Get(req.Ctx, client.ObjectKeyFromObject(cm), found)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err = h.client.Create(req.Ctx, cm); err != nil {
+			return err
+		}
+	} else {
+		if reflect.DeepEqual(found.Data, cm.Data) {
+			return nil
+		}
+		found.Data = cm.Data
+		hcoutil.DeepCopyLabels(&cm.ObjectMeta, &found.ObjectMeta)
+		if err = h.client.Update(req.Ctx, found); err != nil {
+			return err
+		}
+	}
+
+	req.Logger.Info("the preview was updated", "ConfigMap", cm.Name)
+	h.eventEmitter.EmitEvent(req.Instance, corev1.EventTypeNormal, "PreviewUpdated", fmt.Sprintf("Updated the preview in the %s ConfigMap", cm.Name))
+
+	return nil
+}
+
+func newPreviewConfigMap(hc *hcov1beta1.HyperConverged, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PreviewConfigMapName,
+			Namespace: hc.Namespace,
+			Labels:    getLabels(hc, hcoutil.AppComponentDeployment),
+		},
+		Data: data,
+	}
+}
+
+// preview compares the resource that the operand would deploy for the HyperConverged CR with the live resource.
+// Returns nil if there is no difference.
+func (h *genericOperand) preview(req *common.HcoRequest, hc *hcov1beta1.HyperConverged) (*OperandPreview, error) {
+	// getFullCr may be cached; make sure that neither the current reconciliation nor the next one would use the
+	// previewed resource
+	h.hooks.reset()
+	defer h.hooks.reset()
+
+	required, err := h.hooks.getFullCr(hc)
+	if err != nil {
+		return nil, fmt.Errorf("can't render %s: %v", h.crType, err)
+	}
+
+	p := &OperandPreview{Kind: NewEnsureResult(required).Type, Name: required.GetName()}
+
+	found := h.hooks.getEmptyCr()
+	err = h.Client.Get(req.Ctx, client.ObjectKeyFromObject(required), found)
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			p.Action = previewActionCreate
+			return p, nil
+		}
+		return nil, fmt.Errorf("can't read %s %s: %v", p.Kind, p.Name, err)
+	}
+
+	p.Changes, err = getPreviewChanges(found, required)
+	if err != nil {
+		return nil, fmt.Errorf("can't compare %s %s: %v", p.Kind, p.Name, err)
+	}
+	if len(p.Changes) == 0 {
+		return nil, nil
+	}
+
+	p.Action = previewActionUpdate
+	return p, nil
+}
+
+// previewRemoval reports a resource that would be removed, if it exists
+func previewRemoval(req *common.HcoRequest, cl client.Client, resource client.Object) (*OperandPreview, error) {
+	p := &OperandPreview{Kind: NewEnsureResult(resource).Type, Name: resource.GetName(), Action: previewActionDelete}
+
+	err := cl.Get(req.Ctx, client.ObjectKeyFromObject(resource), resource)
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("can't read %s %s: %v", p.Kind, p.Name, err)
+	}
+
+	return p, nil
+}
+
+// getPreviewChanges compares the content of the live and the required resources; that is, all the top level fields
+// but the metadata and the status.
+func getPreviewChanges(live, required runtime.Object) ([]PreviewChange, error) {
+	liveContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return nil, err
+	}
+
+	requiredContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(required)
+	if err != nil {
+		return nil, err
+	}
+
+	for field := range previewUnmanagedFields {
+		delete(liveContent, field)
+		delete(requiredContent, field)
+	}
+
+	var changes []PreviewChange
+	diffPreviewFields("", liveContent, requiredContent, &changes)
+	return changes, nil
+}
+
+// diffPreviewFields recursively compares two values, and adds a change for each different leaf field. Lists are
+// compared as a whole.
+func diffPreviewFields(path string, live, required interface{}, changes *[]PreviewChange) {
+	liveMap, liveIsMap := live.(map[string]interface{})
+	requiredMap, requiredIsMap := required.(map[string]interface{})
+
+	if !liveIsMap || !requiredIsMap {
+		if !reflect.DeepEqual(live, required) {
+			*changes = append(*changes, PreviewChange{Path: path, Live: live, Preview: required})
+		}
+		return
+	}
+
+	keys := make(map[string]bool, len(liveMap)+len(requiredMap))
+	for key := range liveMap {
+		keys[key] = true
+	}
+	for key := range requiredMap {
+		keys[key] = true
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		diffPreviewFields(fieldPath, liveMap[key], requiredMap[key], changes)
+	}
+}
@@ -0,0 +1,210 @@
+package operands
+
+import (
+	"context"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Preview", func() {
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+	var eventEmitter *commonTestUtils.EventEmitterMock
+
+	const nodeSelectorPatch = `{"spec":{"infra":{"nodePlacement":{"nodeSelector":{"preview":"true"}}}}}`
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		req = commonTestUtils.NewReq(hco)
+		eventEmitter = commonTestUtils.NewEventEmitterMock()
+	})
+
+	newHandler := func(cl client.Client, operands ...Operand) *OperandHandler {
+		return &OperandHandler{
+			client:       cl,
+			operands:     operands,
+			eventEmitter: eventEmitter,
+		}
+	}
+
+	getPreview := func(cl client.Client) (*corev1.ConfigMap, error) {
+		cm := &corev1.ConfigMap{}
+		err := cl.Get(context.TODO(), client.ObjectKey{Namespace: hco.Namespace, Name: PreviewConfigMapName}, cm)
+		return cm, err
+	}
+
+	readEntry := func(cm *corev1.ConfigMap, key string) *OperandPreview {
+		Expect(cm.Data).To(HaveKey(key))
+		p := &OperandPreview{}
+		Expect(yaml.Unmarshal([]byte(cm.Data[key]), p)).To(Succeed())
+		return p
+	}
+
+	It("should not create the preview ConfigMap if the annotation is missing", func() {
+		cl := commonTestUtils.InitClient([]runtime.Object{hco})
+		Expect(newHandler(cl, (*genericOperand)(newCdiHandler(cl, commonTestUtils.GetScheme()))).Ensure(req)).To(Succeed())
+
+		_, err := getPreview(cl)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report the changes without applying them", func() {
+		cl := commonTestUtils.InitClient([]runtime.Object{hco})
+		handler := newHandler(cl, (*genericOperand)(newCdiHandler(cl, commonTestUtils.GetScheme())))
+
+		By("deploying the current CR")
+		Expect(handler.Ensure(req)).To(Succeed())
+		_, err := getPreview(cl)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("previewing a change of the node placement")
+		hco.Annotations = map[string]string{common.PreviewAnnotationName: nodeSelectorPatch}
+		Expect(handler.Ensure(req)).To(Succeed())
+
+		cm, err := getPreview(cl)
+		Expect(err).ToNot(HaveOccurred())
+
+		p := readEntry(cm, "CDI.cdi-"+hco.Name)
+		Expect(p.Action).To(Equal(previewActionUpdate))
+		Expect(p.Changes).To(HaveLen(1))
+		Expect(p.Changes[0].Path).To(Equal("spec.infra.nodeSelector"))
+		Expect(p.Changes[0].Live).To(BeNil())
+		Expect(p.Changes[0].Preview).To(Equal(map[string]interface{}{"preview": "true"}))
+
+		cdi := &cdiv1beta1.CDI{}
+		Expect(cl.Get(context.TODO(), client.ObjectKey{Name: "cdi-" + hco.Name}, cdi)).To(Succeed())
+		Expect(cdi.Spec.Infra.NodeSelector).To(BeEmpty())
+
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{
+			{EventType: corev1.EventTypeNormal, Reason: "PreviewUpdated", Msg: "Updated the preview in the hco-preview ConfigMap"},
+		})).To(BeTrue())
+
+		By("removing the preview ConfigMap once the annotation is removed")
+		delete(hco.Annotations, common.PreviewAnnotationName)
+		Expect(handler.Ensure(req)).To(Succeed())
+		_, err = getPreview(cl)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report the resources that would be created", func() {
+		hco.Annotations = map[string]string{common.PreviewAnnotationName: "{}"}
+		cl := commonTestUtils.InitClient([]runtime.Object{hco})
+		cdiHandler := (*genericOperand)(newCdiHandler(cl, commonTestUtils.GetScheme()))
+
+		p, err := cdiHandler.preview(req, hco)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p).ToNot(BeNil())
+		Expect(p.Action).To(Equal(previewActionCreate))
+
+		cdi := &cdiv1beta1.CDI{}
+		err = cl.Get(context.TODO(), client.ObjectKey{Name: "cdi-" + hco.Name}, cdi)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report the resources of disabled components as removed", func() {
+		hco.Annotations = map[string]string{common.PreviewAnnotationName: `{"spec":{"components":{"networkAddons":false}}}`}
+		cna := NewNetworkAddonsWithNameOnly(hco)
+		cl := commonTestUtils.InitClient([]runtime.Object{hco, cna})
+		handler := newOptionalOperand((*genericOperand)(newCnaHandler(cl, commonTestUtils.GetScheme())), cl,
+			commonTestUtils.GetScheme(), isNetworkAddonsEnabled, getNetworkAddonsResource)
+
+		previewHco, err := getPreviewHyperConverged(hco, hco.Annotations[common.PreviewAnnotationName])
+		Expect(err).ToNot(HaveOccurred())
+
+		p, err := handler.preview(req, previewHco)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p).ToNot(BeNil())
+		Expect(p.Action).To(Equal(previewActionDelete))
+		Expect(p.Name).To(Equal(cna.Name))
+
+		Expect(cl.Get(context.TODO(), client.ObjectKeyFromObject(cna), cna)).To(Succeed())
+	})
+
+	It("should not leave the previewed resource in the cache", func() {
+		cl := commonTestUtils.InitClient([]runtime.Object{hco})
+		cdiHandler := (*genericOperand)(newCdiHandler(cl, commonTestUtils.GetScheme()))
+
+		previewHco, err := getPreviewHyperConverged(hco, nodeSelectorPatch)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = cdiHandler.preview(req, previewHco)
+		Expect(err).ToNot(HaveOccurred())
+
+		cdi, err := cdiHandler.hooks.getFullCr(hco)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cdi.(*cdiv1beta1.CDI).Spec.Infra.NodeSelector).To(BeEmpty())
+	})
+
+	It("should report a wrong patch", func() {
+		hco.Annotations = map[string]string{common.PreviewAnnotationName: "not a json"}
+		cl := commonTestUtils.InitClient([]runtime.Object{hco})
+
+		Expect(newHandler(cl).Ensure(req)).To(Succeed())
+
+		cm, err := getPreview(cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue(previewErrorKey, ContainSubstring("invalid merge patch")))
+	})
+
+	Context("getPreviewHyperConverged", func() {
+		It("should not modify the HyperConverged CR", func() {
+			hco.Annotations = map[string]string{common.PreviewAnnotationName: nodeSelectorPatch}
+
+			previewHco, err := getPreviewHyperConverged(hco, nodeSelectorPatch)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(previewHco.Spec.Infra.NodePlacement).ToNot(BeNil())
+			Expect(previewHco.Spec.Infra.NodePlacement.NodeSelector).To(HaveKeyWithValue("preview", "true"))
+			Expect(previewHco.Annotations).ToNot(HaveKey(common.PreviewAnnotationName))
+
+			Expect(hco.Spec.Infra.NodePlacement).To(BeNil())
+			Expect(hco.Annotations).To(HaveKey(common.PreviewAnnotationName))
+		})
+
+		It("should keep the name and the namespace", func() {
+			previewHco, err := getPreviewHyperConverged(hco, `{"metadata":{"name":"other","namespace":"other"}}`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(previewHco.Name).To(Equal(hco.Name))
+			Expect(previewHco.Namespace).To(Equal(hco.Namespace))
+		})
+	})
+
+	Context("diffPreviewFields", func() {
+		It("should report the added, the changed and the removed fields", func() {
+			live := map[string]interface{}{
+				"spec": map[string]interface{}{
+					"same":    "value",
+					"changed": "old",
+					"removed": "value",
+					"list":    []interface{}{"a", "b"},
+				},
+			}
+			required := map[string]interface{}{
+				"spec": map[string]interface{}{
+					"same":    "value",
+					"changed": "new",
+					"added":   int64(1),
+					"list":    []interface{}{"a"},
+				},
+			}
+
+			var changes []PreviewChange
+			diffPreviewFields("", live, required, &changes)
+			Expect(changes).To(Equal([]PreviewChange{
+				{Path: "spec.added", Preview: int64(1)},
+				{Path: "spec.changed", Live: "old", Preview: "new"},
+				{Path: "spec.list", Live: []interface{}{"a", "b"}, Preview: []interface{}{"a"}},
+				{Path: "spec.removed", Live: "value"},
+			}))
+		})
+	})
+})
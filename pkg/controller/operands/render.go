@@ -0,0 +1,190 @@
+package operands
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// RenderOperands returns the resources that HCO deploys for the HyperConverged CR, in the order of deployment, without
+// accessing the cluster. The resources depend on the environment variables of HCO, as in the HCO deployment. The
+// quick starts and the extra manifests of the HCO image are not included. The resources of the operands that require
+// a capability are rendered only if the capability is in the given capabilities.
+func RenderOperands(hc *hcov1beta1.HyperConverged, scheme *runtime.Scheme, capabilities []hcoutil.Capability) ([]*unstructured.Unstructured, error) {
+	kv, err := NewKubeVirt(hc)
+	if err != nil {
+		return nil, fmt.Errorf("can't render the KubeVirt CR; %w", err)
+	}
+
+	cdi, err := NewCDI(hc)
+	if err != nil {
+		return nil, fmt.Errorf("can't render the CDI CR; %w", err)
+	}
+
+	kvConfig, err := newPatchedConfigMap(hc, kvConfigPatchTarget, NewKubeVirtConfigForCR(hc, hc.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("can't render the kubevirt-config ConfigMap; %w", err)
+	}
+
+	storageConfig, err := newPatchedConfigMap(hc, storageConfigPatchTarget, NewKubeVirtStorageConfigForCR(hc, hc.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("can't render the kubevirt-storage-class-defaults ConfigMap; %w", err)
+	}
+
+	resources := []client.Object{
+		kvConfig,
+		NewKubeVirtPriorityClass(hc),
+		kv,
+		cdi,
+		storageConfig,
+	}
+
+	if isNetworkAddonsEnabled(hc) {
+		cna, err := NewNetworkAddons(hc)
+		if err != nil {
+			return nil, fmt.Errorf("can't render the NetworkAddonsConfig CR; %w", err)
+		}
+		resources = append(resources, cna)
+	}
+
+	if isVMImportEnabled(hc) {
+		vmImport, err := NewVMImportForCR(hc)
+		if err != nil {
+			return nil, fmt.Errorf("can't render the VMImportConfig CR; %w", err)
+		}
+
+		imsConfig, err := newPatchedConfigMap(hc, imsConfigPatchTarget, NewIMSConfigForCR(hc, hc.Namespace))
+		if err != nil {
+			return nil, fmt.Errorf("can't render the v2v-vmware ConfigMap; %w", err)
+		}
+		resources = append(resources, vmImport, imsConfig)
+	}
+
+	if hc.Spec.HostPathProvisioner != nil {
+		hpp, err := NewHostPathProvisioner(hc)
+		if err != nil {
+			return nil, fmt.Errorf("can't render the HostPathProvisioner CR; %w", err)
+		}
+		resources = append(resources, hpp, NewHppStorageClass(hc))
+	}
+
+	ci := staticCapabilities(capabilities)
+	if isSSPEnabled(hc) && hasCapabilities(ci, sspCapabilities...) {
+		ssp, err := NewSSP(hc)
+		if err != nil {
+			return nil, fmt.Errorf("can't render the SSP CR; %w", err)
+		}
+		resources = append(resources, ssp)
+	}
+	if isMonitoringEnabled(hc) && hasCapabilities(ci, monitoringCapabilities...) {
+		resources = append(resources,
+			NewMetricsService(hc, hc.Namespace),
+			NewServiceMonitor(hc, hc.Namespace),
+			NewPrometheusRule(hc, hc.Namespace),
+		)
+	}
+	if hc.Spec.Components.IsCLIDownloadsEnabled() && hasCapabilities(ci, cliDownloadCapabilities...) {
+		resources = append(resources, NewConsoleCLIDownload(hc))
+	}
+
+	rendered := make([]*unstructured.Unstructured, 0, len(resources))
+	for _, resource := range resources {
+		u, err := toRenderedUnstructured(resource, scheme)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, u)
+	}
+
+	return rendered, nil
+}
+
+// toRenderedUnstructured converts a resource to an unstructured object with its apiVersion and kind, and without the
+// fields that are set by the cluster.
+func toRenderedUnstructured(resource client.Object, scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	if u, ok := resource.(*unstructured.Unstructured); ok {
+		return u.DeepCopy(), nil
+	}
+
+	// the KubeVirt types are registered in several versions
+	gvk := kubevirtv1.KubeVirtGroupVersionKind
+	if _, isKv := resource.(*kubevirtv1.KubeVirt); !isKv {
+		var err error
+		if gvk, err = apiutil.GVKForObject(resource, scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+
+	return u, nil
+}
+
+// DiffRenderedOperands compares the rendered resources with the resources that were exported from a cluster. It
+// reports the rendered resources that are missing or different, and the exported resources that are managed by HCO but
+// are not rendered. Returns nil if there is no difference.
+func DiffRenderedOperands(rendered, exported []*unstructured.Unstructured) ([]OperandPreview, error) {
+	key := func(u *unstructured.Unstructured) string {
+		return u.GroupVersionKind().GroupKind().String() + "/" + u.GetNamespace() + "/" + u.GetName()
+	}
+
+	exportedByKey := make(map[string]*unstructured.Unstructured, len(exported))
+	for _, u := range exported {
+		exportedByKey[key(u)] = u
+	}
+
+	var diffs []OperandPreview
+	renderedKeys := make(map[string]bool, len(rendered))
+	for _, required := range rendered {
+		renderedKeys[key(required)] = true
+		p := OperandPreview{Kind: required.GetKind(), Name: required.GetName()}
+
+		live, found := exportedByKey[key(required)]
+		if !found {
+			p.Action = previewActionCreate
+			diffs = append(diffs, p)
+			continue
+		}
+
+		changes, err := getPreviewChanges(live, required)
+		if err != nil {
+			return nil, fmt.Errorf("can't compare %s %s: %v", p.Kind, p.Name, err)
+		}
+		if len(changes) > 0 {
+			p.Action = previewActionUpdate
+			p.Changes = changes
+			diffs = append(diffs, p)
+		}
+	}
+
+	var removed []OperandPreview
+	for _, u := range exported {
+		if !renderedKeys[key(u)] && u.GetLabels()[hcoutil.AppLabelManagedBy] == hcoutil.OperatorName {
+			removed = append(removed, OperandPreview{Kind: u.GetKind(), Name: u.GetName(), Action: previewActionDelete})
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].Kind != removed[j].Kind {
+			return removed[i].Kind < removed[j].Kind
+		}
+		return removed[i].Name < removed[j].Name
+	})
+
+	return append(diffs, removed...), nil
+}
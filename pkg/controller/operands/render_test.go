@@ -0,0 +1,160 @@
+package operands
+
+import (
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+var _ = Describe("Render", func() {
+	var hco *hcov1beta1.HyperConverged
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+	})
+
+	kinds := func(resources []*unstructured.Unstructured) []string {
+		result := make([]string, 0, len(resources))
+		for _, res := range resources {
+			result = append(result, res.GetKind())
+		}
+		return result
+	}
+
+	findKind := func(resources []*unstructured.Unstructured, kind string) *unstructured.Unstructured {
+		for _, res := range resources {
+			if res.GetKind() == kind {
+				return res
+			}
+		}
+		return nil
+	}
+
+	Context("RenderOperands", func() {
+		It("should render all the operands on OpenShift", func() {
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(rendered)).To(Equal([]string{
+				"ConfigMap", "PriorityClass", "KubeVirt", "CDI", "ConfigMap", "NetworkAddonsConfig", "VMImportConfig",
+				"ConfigMap", "SSP", "Service", "ServiceMonitor", "PrometheusRule", "ConsoleCLIDownload",
+			}))
+
+			kv := findKind(rendered, "KubeVirt")
+			Expect(kv.GetAPIVersion()).To(Equal("kubevirt.io/v1"))
+			Expect(kv.GetNamespace()).To(Equal(hco.Namespace))
+			Expect(kv.Object).ToNot(HaveKey("status"))
+			Expect(kv.Object["metadata"]).ToNot(HaveKey("creationTimestamp"))
+		})
+
+		It("should not render the OpenShift resources on Kubernetes", func() {
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(rendered)).ToNot(ContainElement("SSP"))
+			Expect(kinds(rendered)).ToNot(ContainElement("ConsoleCLIDownload"))
+			Expect(kinds(rendered)).ToNot(ContainElement("ServiceMonitor"))
+		})
+
+		It("should render the monitoring resources on Kubernetes with prometheus-operator", func() {
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), []hcoutil.Capability{hcoutil.CapabilityMonitoring})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(rendered)).To(ContainElements("Service", "ServiceMonitor", "PrometheusRule"))
+			Expect(kinds(rendered)).ToNot(ContainElement("SSP"))
+			Expect(kinds(rendered)).ToNot(ContainElement("ConsoleCLIDownload"))
+		})
+
+		It("should not render the disabled components", func() {
+			disabled := false
+			hco.Spec.Components = &hcov1beta1.HyperConvergedComponents{NetworkAddons: &disabled, Monitoring: &disabled}
+
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(rendered)).ToNot(ContainElement("NetworkAddonsConfig"))
+			Expect(kinds(rendered)).ToNot(ContainElement("PrometheusRule"))
+			Expect(kinds(rendered)).To(ContainElement("VMImportConfig"))
+		})
+
+		It("should render the HostPath Provisioner if it is configured", func() {
+			hco.Spec.HostPathProvisioner = &hcov1beta1.HostPathProvisionerConfig{Path: "/var/hpvolumes"}
+
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(rendered)).To(ContainElement(hppKind))
+			Expect(kinds(rendered)).To(ContainElement("StorageClass"))
+		})
+
+		It("should fail for a wrong jsonpatch annotation", func() {
+			hco.Annotations = map[string]string{"kubevirt.kubevirt.io/jsonpatch": "not a json"}
+
+			_, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("DiffRenderedOperands", func() {
+		It("should not report differences from the same resources", func() {
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).ToNot(HaveOccurred())
+
+			exported, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).ToNot(HaveOccurred())
+			// fields that are set by the cluster are ignored
+			for _, res := range exported {
+				res.SetResourceVersion("1234")
+				res.Object["status"] = map[string]interface{}{"phase": "Deployed"}
+			}
+
+			diffs, err := DiffRenderedOperands(rendered, exported)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diffs).To(BeEmpty())
+		})
+
+		It("should report the created, the updated and the removed resources", func() {
+			rendered, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).ToNot(HaveOccurred())
+
+			exported, err := RenderOperands(hco, commonTestUtils.GetScheme(), hcoutil.Capabilities)
+			Expect(err).ToNot(HaveOccurred())
+
+			var filtered []*unstructured.Unstructured
+			for _, res := range exported {
+				switch res.GetKind() {
+				case "SSP":
+					continue
+				case "CDI":
+					Expect(unstructured.SetNestedField(res.Object, "Retain", "spec", "uninstallStrategy")).To(Succeed())
+				}
+				filtered = append(filtered, res)
+			}
+
+			stale := &unstructured.Unstructured{}
+			stale.SetAPIVersion("v1")
+			stale.SetKind("ConfigMap")
+			stale.SetName("stale")
+			stale.SetNamespace(hco.Namespace)
+			stale.SetLabels(getLabels(hco, "compute"))
+			notManaged := stale.DeepCopy()
+			notManaged.SetName("not-managed")
+			notManaged.SetLabels(nil)
+			filtered = append(filtered, stale, notManaged)
+
+			diffs, err := DiffRenderedOperands(rendered, filtered)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diffs).To(HaveLen(3))
+
+			Expect(diffs[0].Kind).To(Equal("CDI"))
+			Expect(diffs[0].Action).To(Equal(previewActionUpdate))
+			Expect(diffs[0].Changes).To(Equal([]PreviewChange{
+				{Path: "spec.uninstallStrategy", Live: "Retain", Preview: "BlockUninstallIfWorkloadsExist"},
+			}))
+
+			Expect(diffs[1].Kind).To(Equal("SSP"))
+			Expect(diffs[1].Action).To(Equal(previewActionCreate))
+
+			Expect(diffs[2]).To(Equal(OperandPreview{Kind: "ConfigMap", Name: "stale", Action: previewActionDelete}))
+		})
+	})
+})
@@ -0,0 +1,116 @@
+package operands
+
+import (
+	"encoding/json"
+
+	fuzz "github.com/google/gofuzz"
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	renderingInputs      = 50
+	renderingRepetitions = 20
+)
+
+type operandRenderer func(hc *hcov1beta1.HyperConverged) (runtime.Object, error)
+
+var _ = Describe("Operand rendering", func() {
+	renderers := map[string]operandRenderer{
+		"KubeVirt": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewKubeVirt(hc)
+		},
+		"kubevirt-config ConfigMap": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewKubeVirtConfigForCR(hc, commonTestUtils.Namespace), nil
+		},
+		"KubeVirt PriorityClass": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewKubeVirtPriorityClass(hc), nil
+		},
+		"CDI": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewCDI(hc)
+		},
+		"kubevirt-storage-class-defaults ConfigMap": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewKubeVirtStorageConfigForCR(hc, commonTestUtils.Namespace), nil
+		},
+		"KubeVirt storage Role": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewKubeVirtStorageRoleForCR(hc, commonTestUtils.Namespace, commonTestUtils.GetScheme()), nil
+		},
+		"KubeVirt storage RoleBinding": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewKubeVirtStorageRoleBindingForCR(hc, commonTestUtils.Namespace, commonTestUtils.GetScheme()), nil
+		},
+		"NetworkAddonsConfig": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewNetworkAddons(hc)
+		},
+		"SSP": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewSSP(hc)
+		},
+		"VMImportConfig": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewVMImportForCR(hc)
+		},
+		"v2v-vmware ConfigMap": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewIMSConfigForCR(hc, commonTestUtils.Namespace), nil
+		},
+		"ConsoleCLIDownload": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewConsoleCLIDownload(hc), nil
+		},
+		"metrics Service": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewMetricsService(hc, commonTestUtils.Namespace), nil
+		},
+		"ServiceMonitor": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewServiceMonitor(hc, commonTestUtils.Namespace), nil
+		},
+		"PrometheusRule": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewPrometheusRule(hc, commonTestUtils.Namespace), nil
+		},
+		"HostPathProvisioner": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			if hc.Spec.HostPathProvisioner == nil {
+				hc.Spec.HostPathProvisioner = &hcov1beta1.HostPathProvisionerConfig{Path: "/var/hpvolumes"}
+			}
+			return NewHostPathProvisioner(hc)
+		},
+		"HostPath Provisioner StorageClass": func(hc *hcov1beta1.HyperConverged) (runtime.Object, error) {
+			return NewHppStorageClass(hc), nil
+		},
+	}
+
+	// randomHcos generates HyperConverged CRs with random specs. The seed is the ginkgo random seed, so a failure can
+	// be reproduced by running the suite with the same --seed
+	randomHcos := func() []*hcov1beta1.HyperConverged {
+		f := fuzz.NewWithSeed(GinkgoRandomSeed()).NilChance(0.3).NumElements(0, 5)
+
+		hcos := make([]*hcov1beta1.HyperConverged, renderingInputs)
+		for i := range hcos {
+			hco := commonTestUtils.NewHco()
+			f.Fuzz(&hco.Spec)
+			hcos[i] = hco
+		}
+		return hcos
+	}
+
+	for name, render := range renderers {
+		operand := name
+		renderer := render
+
+		It("should render "+operand+" deterministically", func() {
+			for _, hco := range randomHcos() {
+				expected := renderToJSON(renderer, hco)
+				for i := 1; i < renderingRepetitions; i++ {
+					Expect(renderToJSON(renderer, hco)).To(Equal(expected), "rendering #%d differs", i)
+				}
+			}
+		})
+	}
+})
+
+func renderToJSON(render operandRenderer, hco *hcov1beta1.HyperConverged) string {
+	obj, err := render(hco.DeepCopy())
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	out, err := json.Marshal(obj)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+
+	return string(out)
+}
@@ -3,20 +3,15 @@ package operands
 import (
 	"context"
 	"fmt"
-	"os"
 
 	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
-	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
-	v1 "github.com/openshift/custom-resource-status/objectreferences/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	lifecycleapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
 	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
 
 	corev1 "k8s.io/api/core/v1"
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/reference"
@@ -37,9 +32,10 @@ var _ = Describe("SSP Operands", func() {
 		})
 
 		It("should create if not present", func() {
-			expectedResource := NewSSP(hco)
+			expectedResource, err := NewSSP(hco)
+			Expect(err).ToNot(HaveOccurred())
 			cl := commonTestUtils.InitClient([]runtime.Object{})
-			handler := newSspHandler(cl, commonTestUtils.GetScheme())
+			handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 			res := handler.ensure(req)
 			Expect(res.Created).To(BeTrue())
 			Expect(res.Updated).To(BeFalse())
@@ -59,10 +55,11 @@ var _ = Describe("SSP Operands", func() {
 		})
 
 		It("should find if present", func() {
-			expectedResource := NewSSP(hco)
+			expectedResource, err := NewSSP(hco)
+			Expect(err).ToNot(HaveOccurred())
 			expectedResource.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/dummies/%s", expectedResource.Namespace, expectedResource.Name)
 			cl := commonTestUtils.InitClient([]runtime.Object{hco, expectedResource})
-			handler := newSspHandler(cl, commonTestUtils.GetScheme())
+			handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 			res := handler.ensure(req)
 			Expect(res.Created).To(BeFalse())
 			Expect(res.Updated).To(BeFalse())
@@ -79,7 +76,8 @@ var _ = Describe("SSP Operands", func() {
 		})
 
 		It("should reconcile to default", func() {
-			expectedResource := NewSSP(hco)
+			expectedResource, err := NewSSP(hco)
+			Expect(err).ToNot(HaveOccurred())
 			existingResource := expectedResource.DeepCopy()
 			existingResource.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/dummies/%s", existingResource.Namespace, existingResource.Name)
 
@@ -93,7 +91,7 @@ var _ = Describe("SSP Operands", func() {
 			req.HCOTriggered = false // mock a reconciliation triggered by a change in NewKubeVirtCommonTemplateBundle CR
 
 			cl := commonTestUtils.InitClient([]runtime.Object{hco, existingResource})
-			handler := newSspHandler(cl, commonTestUtils.GetScheme())
+			handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 			res := handler.ensure(req)
 			Expect(res.Created).To(BeFalse())
 			Expect(res.Updated).To(BeTrue())
@@ -113,13 +111,14 @@ var _ = Describe("SSP Operands", func() {
 		Context("Node placement", func() {
 
 			It("should add node placement if missing", func() {
-				existingResource := NewSSP(hco, commonTestUtils.Namespace)
+				existingResource, err := NewSSP(hco, commonTestUtils.Namespace)
+				Expect(err).ToNot(HaveOccurred())
 
 				hco.Spec.Workloads.NodePlacement = commonTestUtils.NewNodePlacement()
 				hco.Spec.Infra.NodePlacement = commonTestUtils.NewOtherNodePlacement()
 
 				cl := commonTestUtils.InitClient([]runtime.Object{hco, existingResource})
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
+				handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 				res := handler.ensure(req)
 				Expect(res.Created).To(BeFalse())
 				Expect(res.Updated).To(BeTrue())
@@ -146,10 +145,11 @@ var _ = Describe("SSP Operands", func() {
 				hcoNodePlacement := commonTestUtils.NewHco()
 				hcoNodePlacement.Spec.Workloads.NodePlacement = commonTestUtils.NewNodePlacement()
 				hcoNodePlacement.Spec.Infra.NodePlacement = commonTestUtils.NewOtherNodePlacement()
-				existingResource := NewSSP(hcoNodePlacement, commonTestUtils.Namespace)
+				existingResource, err := NewSSP(hcoNodePlacement, commonTestUtils.Namespace)
+				Expect(err).ToNot(HaveOccurred())
 
 				cl := commonTestUtils.InitClient([]runtime.Object{hco, existingResource})
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
+				handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 				res := handler.ensure(req)
 				Expect(res.Created).To(BeFalse())
 				Expect(res.Updated).To(BeTrue())
@@ -175,7 +175,8 @@ var _ = Describe("SSP Operands", func() {
 
 				hco.Spec.Workloads.NodePlacement = commonTestUtils.NewNodePlacement()
 				hco.Spec.Infra.NodePlacement = commonTestUtils.NewOtherNodePlacement()
-				existingResource := NewSSP(hco, commonTestUtils.Namespace)
+				existingResource, err := NewSSP(hco, commonTestUtils.Namespace)
+				Expect(err).ToNot(HaveOccurred())
 
 				// now, modify HCO's node placement
 				seconds12 := int64(12)
@@ -191,7 +192,7 @@ var _ = Describe("SSP Operands", func() {
 				hco.Spec.Infra.NodePlacement.NodeSelector["key3"] = "something entirely else"
 
 				cl := commonTestUtils.InitClient([]runtime.Object{hco, existingResource})
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
+				handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 				res := handler.ensure(req)
 				Expect(res.Created).To(BeFalse())
 				Expect(res.Updated).To(BeTrue())
@@ -226,7 +227,8 @@ var _ = Describe("SSP Operands", func() {
 			It("should overwrite node placement if directly set on SSP CR", func() {
 				hco.Spec.Workloads = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
 				hco.Spec.Infra = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewOtherNodePlacement()}
-				existingResource := NewSSP(hco, commonTestUtils.Namespace)
+				existingResource, err := NewSSP(hco, commonTestUtils.Namespace)
+				Expect(err).ToNot(HaveOccurred())
 
 				// mock a reconciliation triggered by a change in NewKubeVirtNodeLabellerBundle CR
 				req.HCOTriggered = false
@@ -246,7 +248,7 @@ var _ = Describe("SSP Operands", func() {
 				existingResource.Spec.TemplateValidator.Placement.NodeSelector["key3"] = "BADvalue3"
 
 				cl := commonTestUtils.InitClient([]runtime.Object{hco, existingResource})
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
+				handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 				res := handler.ensure(req)
 				Expect(res.UpgradeDone).To(BeFalse())
 				Expect(res.Updated).To(BeTrue())
@@ -274,172 +276,9 @@ var _ = Describe("SSP Operands", func() {
 			})
 		})
 
-		Context("SSP Upgrade", func() {
-
-			It("shouldn't remove old CRDs if upgrade isn't done", func() {
-				oldCrds := oldSSPCrdsAsObjects()
-				cl := commonTestUtils.InitClient(oldCrds)
-
-				// Simulate ongoing upgrade
-				req.SetUpgradeMode(true)
-
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
-				res := handler.ensure(req)
-
-				Expect(res.Created).To(BeTrue())
-				Expect(res.Updated).To(BeFalse())
-				Expect(res.Overwritten).To(BeFalse())
-				Expect(res.UpgradeDone).To(BeFalse())
-				Expect(res.Err).To(BeNil())
-
-				foundCrds := apiextensionsv1.CustomResourceDefinitionList{}
-				Expect(cl.List(context.TODO(), &foundCrds)).To(BeNil())
-				Expect(foundCrds.Items).To(HaveLen(len(oldCrds)))
-			})
-
-			It("should remove old CRDs if general upgrade is done", func() {
-				oldCrds := oldSSPCrdsAsObjects()
-				cl := commonTestUtils.InitClient(oldCrds)
-
-				// Simulate no upgrade
-				req.SetUpgradeMode(false)
-
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
-				res := handler.ensure(req)
-
-				Expect(res.Created).To(BeTrue())
-				Expect(res.Updated).To(BeFalse())
-				Expect(res.Overwritten).To(BeFalse())
-				Expect(res.UpgradeDone).To(BeFalse())
-				Expect(res.Err).To(BeNil())
-
-				foundCrds := apiextensionsv1.CustomResourceDefinitionList{}
-				Expect(cl.List(context.TODO(), &foundCrds)).To(BeNil())
-				Expect(foundCrds.Items).To(BeEmpty())
-			})
-
-			It("should remove old CRDs if SSP upgrade is done", func() {
-				existingResource := NewSSP(hco, commonTestUtils.Namespace)
-				existingResource.Status.Conditions = []conditionsv1.Condition{
-					{
-						Type:   conditionsv1.ConditionAvailable,
-						Status: corev1.ConditionTrue,
-					},
-					{
-						Type:   conditionsv1.ConditionDegraded,
-						Status: corev1.ConditionFalse,
-					},
-					{
-						Type:   conditionsv1.ConditionProgressing,
-						Status: corev1.ConditionFalse,
-					},
-				}
-
-				// Set the expected SSP version that indicates upgrade complete.
-				// Note: the value doesn't really matter, even when we move beyond 2.6
-				const expectedSSPVersion = "2.6"
-				os.Setenv(hcoutil.SspVersionEnvV, expectedSSPVersion)
-				existingResource.Status.ObservedVersion = expectedSSPVersion
-
-				oldCrds := oldSSPCrdsAsObjects()
-				objects := append(oldCrds, existingResource)
-				cl := commonTestUtils.InitClient(objects)
-
-				// Simulate ongoing upgrade
-				req.SetUpgradeMode(true)
-
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
-				res := handler.ensure(req)
-
-				Expect(res.Created).To(BeFalse())
-				Expect(res.Updated).To(BeFalse())
-				Expect(res.Overwritten).To(BeFalse())
-				Expect(res.UpgradeDone).To(BeTrue())
-				Expect(res.Err).To(BeNil())
-
-				foundCrds := apiextensionsv1.CustomResourceDefinitionList{}
-				Expect(cl.List(context.TODO(), &foundCrds)).To(BeNil())
-				Expect(foundCrds.Items).To(BeEmpty())
-			})
-
-			It("should remove old related objects if upgrade is done", func() {
-				// Simulate no upgrade
-				req.SetUpgradeMode(false)
-
-				// Initialize RelatedObjects with a bunch of objects
-				// including old SSP ones.
-				for _, objRef := range oldSSPRelatedObjects() {
-					v1.SetObjectReference(&hco.Status.RelatedObjects, objRef)
-				}
-				for _, objRef := range otherRelatedObjects() {
-					v1.SetObjectReference(&hco.Status.RelatedObjects, objRef)
-				}
-
-				cl := commonTestUtils.InitClient(nil)
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
-				res := handler.ensure(req)
-
-				Expect(res.Created).To(BeTrue())
-				Expect(res.Updated).To(BeFalse())
-				Expect(res.Overwritten).To(BeFalse())
-				Expect(res.UpgradeDone).To(BeFalse())
-				Expect(res.Err).To(BeNil())
-
-				Expect(hco.Status.RelatedObjects).To(HaveLen(len(otherRelatedObjects())))
-				for _, objRef := range oldSSPRelatedObjects() {
-					Expect(hco.Status.RelatedObjects).ToNot(ContainElement(objRef))
-				}
-			})
-
-			It("should retry removing old related objects when they fail to be removed from the status", func() {
-				// Simulate no upgrade
-				req.SetUpgradeMode(false)
-
-				// Initialize RelatedObjects with a bunch of objects
-				// including old SSP ones.
-				for _, objRef := range oldSSPRelatedObjects() {
-					v1.SetObjectReference(&hco.Status.RelatedObjects, objRef)
-				}
-				for _, objRef := range otherRelatedObjects() {
-					v1.SetObjectReference(&hco.Status.RelatedObjects, objRef)
-				}
-
-				cl := commonTestUtils.InitClient(nil)
-				handler := newSspHandler(cl, commonTestUtils.GetScheme())
-				res := handler.ensure(req)
-
-				Expect(res.Created).To(BeTrue())
-				Expect(res.Updated).To(BeFalse())
-				Expect(res.Overwritten).To(BeFalse())
-				Expect(res.UpgradeDone).To(BeFalse())
-				Expect(res.Err).To(BeNil())
-
-				// Now simulate "status update failure",
-				// i.e. related objects aren't removed.
-				for _, objRef := range oldSSPRelatedObjects() {
-					v1.SetObjectReference(&hco.Status.RelatedObjects, objRef)
-				}
-
-				// Simulate another reconciliation cycle
-				res = handler.ensure(req)
-
-				Expect(res.Created).To(BeFalse())
-				Expect(res.Updated).To(BeFalse())
-				Expect(res.Overwritten).To(BeFalse())
-				Expect(res.UpgradeDone).To(BeFalse())
-				Expect(res.Err).To(BeNil())
-
-				// len+1 because the (new) SSP object is now added to RelatedObjects
-				Expect(hco.Status.RelatedObjects).To(HaveLen(len(otherRelatedObjects()) + 1))
-				for _, objRef := range oldSSPRelatedObjects() {
-					Expect(hco.Status.RelatedObjects).ToNot(ContainElement(objRef))
-				}
-			})
-		})
-
 		Context("Cache", func() {
 			cl := commonTestUtils.InitClient([]runtime.Object{})
-			handler := newSspHandler(cl, commonTestUtils.GetScheme())
+			handler := (*genericOperand)(newSspHandler(cl, commonTestUtils.GetScheme()))
 
 			It("should start with empty cache", func() {
 				Expect(handler.hooks.(*sspHooks).cache).To(BeNil())
@@ -487,84 +326,3 @@ var _ = Describe("SSP Operands", func() {
 		})
 	})
 })
-
-func oldSSPCrds() []*apiextensionsv1.CustomResourceDefinition {
-	names := []string{
-		"kubevirtcommontemplatesbundles.ssp.kubevirt.io",
-		"kubevirtmetricsaggregations.ssp.kubevirt.io",
-		"kubevirtnodelabellerbundles.ssp.kubevirt.io",
-		"kubevirttemplatevalidators.ssp.kubevirt.io",
-		"kubevirtcommontemplatesbundles.kubevirt.io",
-		"kubevirtmetricsaggregations.kubevirt.io",
-		"kubevirtnodelabellerbundles.kubevirt.io",
-		"kubevirttemplatevalidators.kubevirt.io",
-	}
-
-	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(names))
-	for _, name := range names {
-		crd := &apiextensionsv1.CustomResourceDefinition{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
-			},
-		}
-		crds = append(crds, crd)
-	}
-
-	return crds
-}
-
-func oldSSPCrdsAsObjects() []runtime.Object {
-	crds := oldSSPCrds()
-	objs := make([]runtime.Object, 0, len(crds))
-	for _, crd := range crds {
-		objs = append(objs, crd)
-	}
-
-	return objs
-}
-
-func oldSSPRelatedObjects() []corev1.ObjectReference {
-	return []corev1.ObjectReference{
-		{
-			APIVersion: "ssp.kubevirt.io/v1",
-			Kind:       "KubevirtCommonTemplatesBundle",
-			Name:       "common-templates-kubevirt-hyperconverged",
-			Namespace:  "openshift",
-		},
-		{
-			APIVersion: "ssp.kubevirt.io/v1",
-			Kind:       "KubevirtNodeLabellerBundle",
-			Name:       "node-labeller-kubevirt-hyperconverged",
-			Namespace:  "kubevirt-hyperconverged",
-		},
-		{
-			APIVersion: "ssp.kubevirt.io/v1",
-			Kind:       "KubevirtTemplateValidator",
-			Name:       "template-validator-kubevirt-hyperconverged",
-			Namespace:  "kubevirt-hyperconverged",
-		},
-		{
-			APIVersion: "ssp.kubevirt.io/v1",
-			Kind:       "KubevirtMetricsAggregation",
-			Name:       "metrics-aggregation-kubevirt-hyperconverged",
-			Namespace:  "kubevirt-hyperconverged",
-		},
-	}
-}
-
-func otherRelatedObjects() []corev1.ObjectReference {
-	return []corev1.ObjectReference{
-		{
-			APIVersion: "kubevirt.io/v1alpha3",
-			Kind:       "Kubevirt",
-			Name:       "kubevirt-kubevirt-hyperconverged",
-			Namespace:  "openshift",
-		},
-		{
-			APIVersion: "cdi.kubevirt.io/v1beta1",
-			Kind:       "CDI",
-			Name:       "cdi-kubevirt-hyperconverged",
-			Namespace:  "kubevirt-hyperconverged",
-		},
-	}
-}
@@ -17,6 +17,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const imsConfigMapName = "v2v-vmware"
+
 // ***********  VM Import Handler  ***********
 type vmImportHandler genericOperand
 
@@ -40,7 +42,11 @@ type vmImportHooks struct {
 
 func (h *vmImportHooks) getFullCr(hc *hcov1beta1.HyperConverged) (client.Object, error) {
 	if h.cache == nil {
-		h.cache = NewVMImportForCR(hc)
+		vmImport, err := NewVMImportForCR(hc)
+		if err != nil {
+			return nil, err
+		}
+		h.cache = vmImport
 	}
 	return h.cache, nil
 }
@@ -89,17 +95,28 @@ func (h *vmImportHooks) updateCr(req *common.HcoRequest, Client client.Client, e
 }
 
 // NewVMImportForCR returns a VM import CR
-func NewVMImportForCR(cr *hcov1beta1.HyperConverged) *vmimportv1beta1.VMImportConfig {
+func NewVMImportForCR(cr *hcov1beta1.HyperConverged) (*vmimportv1beta1.VMImportConfig, error) {
 	spec := vmimportv1beta1.VMImportConfigSpec{}
 	if cr.Spec.Infra.NodePlacement != nil {
 		cr.Spec.Infra.NodePlacement.DeepCopyInto(&spec.Infra)
 	}
+
+	vmImport := NewVMImportWithNameOnly(cr)
+	vmImport.Spec = spec
+
+	if err := applyPatchAnnotations(cr, vmImportPatchTarget, vmImport); err != nil {
+		return nil, err
+	}
+
+	return vmImport, nil
+}
+
+func NewVMImportWithNameOnly(cr *hcov1beta1.HyperConverged) *vmimportv1beta1.VMImportConfig {
 	return &vmimportv1beta1.VMImportConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   "vmimport-" + cr.Name,
 			Labels: getLabels(cr, hcoutil.AppComponentImport),
 		},
-		Spec: spec,
 	}
 }
 
@@ -121,7 +138,7 @@ func newImsConfigHandler(Client client.Client, Scheme *runtime.Scheme) *imsConfi
 type imsConfigHooks struct{}
 
 func (h imsConfigHooks) getFullCr(hc *hcov1beta1.HyperConverged) (client.Object, error) {
-	return NewIMSConfigForCR(hc, hc.Namespace), nil
+	return newPatchedConfigMap(hc, imsConfigPatchTarget, NewIMSConfigForCR(hc, hc.Namespace))
 }
 func (h imsConfigHooks) getEmptyCr() client.Object { return &corev1.ConfigMap{} }
 func (h imsConfigHooks) validate() error {
@@ -161,6 +178,10 @@ func (h *imsConfigHooks) updateCr(req *common.HcoRequest, Client client.Client,
 		}
 	}
 
+	if updatePatchedConfigMapData(found, imsConfig, NewIMSConfigForCR(req.Instance, found.Namespace)) {
+		needsUpdate = true
+	}
+
 	if !reflect.DeepEqual(found.Labels, imsConfig.Labels) {
 		util.DeepCopyLabels(&imsConfig.ObjectMeta, &found.ObjectMeta)
 		needsUpdate = true
@@ -181,7 +202,7 @@ func (h *imsConfigHooks) updateCr(req *common.HcoRequest, Client client.Client,
 func NewIMSConfigForCR(cr *hcov1beta1.HyperConverged, namespace string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "v2v-vmware",
+			Name:      imsConfigMapName,
 			Labels:    getLabels(cr, hcoutil.AppComponentImport),
 			Namespace: namespace,
 		},
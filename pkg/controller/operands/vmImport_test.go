@@ -30,7 +30,8 @@ var _ = Describe("VM-Import", func() {
 		})
 
 		It("should create if not present", func() {
-			expectedResource := NewVMImportForCR(hco)
+			expectedResource, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
 			cl := commonTestUtils.InitClient([]runtime.Object{})
 			handler := (*genericOperand)(newVmImportHandler(cl, commonTestUtils.GetScheme()))
 
@@ -54,7 +55,8 @@ var _ = Describe("VM-Import", func() {
 		})
 
 		It("should find if present", func() {
-			expectedResource := NewVMImportForCR(hco)
+			expectedResource, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
 			expectedResource.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/vmimportconfigs/%s", expectedResource.Namespace, expectedResource.Name)
 			cl := commonTestUtils.InitClient([]runtime.Object{hco, expectedResource})
 			handler := (*genericOperand)(newVmImportHandler(cl, commonTestUtils.GetScheme()))
@@ -74,7 +76,8 @@ var _ = Describe("VM-Import", func() {
 		})
 
 		It("should reconcile to default", func() {
-			existingResource := NewVMImportForCR(hco)
+			existingResource, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
 			existingResource.ObjectMeta.SelfLink = fmt.Sprintf("/apis/v1/namespaces/%s/dummies/%s", existingResource.Namespace, existingResource.Name)
 
 			existingResource.Spec.ImagePullPolicy = corev1.PullAlways // set non-default value
@@ -99,7 +102,8 @@ var _ = Describe("VM-Import", func() {
 		})
 
 		It("should add node placement if missing in VM-Import", func() {
-			existingResource := NewVMImportForCR(hco)
+			existingResource, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
 
 			hco.Spec.Infra = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
 			hco.Spec.Workloads = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
@@ -143,7 +147,8 @@ var _ = Describe("VM-Import", func() {
 			hcoNodePlacement := commonTestUtils.NewHco()
 			hcoNodePlacement.Spec.Infra = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
 			hcoNodePlacement.Spec.Workloads = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
-			existingResource := NewVMImportForCR(hcoNodePlacement)
+			existingResource, err := NewVMImportForCR(hcoNodePlacement)
+			Expect(err).ToNot(HaveOccurred())
 
 			cl := commonTestUtils.InitClient([]runtime.Object{hco, existingResource})
 			handler := (*genericOperand)(newVmImportHandler(cl, commonTestUtils.GetScheme()))
@@ -176,7 +181,8 @@ var _ = Describe("VM-Import", func() {
 
 			hco.Spec.Infra = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
 			hco.Spec.Workloads = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
-			existingResource := NewVMImportForCR(hco)
+			existingResource, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
 
 			// now, modify HCO's node placement
 			seconds3 := int64(3)
@@ -216,7 +222,8 @@ var _ = Describe("VM-Import", func() {
 		It("should overwrite node placement if directly set on VMImport CR", func() {
 			hco.Spec.Infra = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
 			hco.Spec.Workloads = hcov1beta1.HyperConvergedConfig{NodePlacement: commonTestUtils.NewNodePlacement()}
-			existingResource := NewVMImportForCR(hco)
+			existingResource, err := NewVMImportForCR(hco)
+			Expect(err).ToNot(HaveOccurred())
 
 			// mock a reconciliation triggered by a change in VMImport CR
 			req.HCOTriggered = false
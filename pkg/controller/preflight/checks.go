@@ -0,0 +1,82 @@
+package preflight
+
+import (
+	"fmt"
+
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+)
+
+// upgradeChecks is the list of the registered checks. New checks are appended to the end of the list.
+var upgradeChecks = []Check{
+	{
+		Name:     "deprecated-operand-apis",
+		Blocking: false,
+		Run:      checkDeprecatedOperandAPIs,
+	},
+	{
+		Name:     "patch-annotations",
+		Blocking: false,
+		Run:      checkPatchAnnotations,
+	},
+	{
+		Name:     "required-storage-classes",
+		Blocking: true,
+		Run:      checkRequiredStorageClasses,
+	},
+}
+
+// deprecatedAPIVersions are the API versions that were used by the operand CRs of previous HCO versions, and are
+// removed from the current operands
+var deprecatedAPIVersions = map[string]bool{
+	"kubevirt.io/v1alpha3":                               true,
+	"cdi.kubevirt.io/v1alpha1":                           true,
+	"networkaddonsoperator.network.kubevirt.io/v1alpha1": true,
+	"ssp.kubevirt.io/v1":                                 true,
+	"v2v.kubevirt.io/v1alpha1":                           true,
+}
+
+// checkDeprecatedOperandAPIs reports the related objects that were deployed with a deprecated API version
+func checkDeprecatedOperandAPIs(req *common.HcoRequest, _ client.Reader) ([]string, error) {
+	var issues []string
+	for _, ref := range req.Instance.Status.RelatedObjects {
+		if deprecatedAPIVersions[ref.APIVersion] {
+			issues = append(issues, fmt.Sprintf("%s %s uses the deprecated %s API", ref.Kind, ref.Name, ref.APIVersion))
+		}
+	}
+
+	return issues, nil
+}
+
+// checkPatchAnnotations reports the patch annotations that can't be applied to the operand CRs of the new version, as
+// rendered by the new HCO version
+func checkPatchAnnotations(req *common.HcoRequest, _ client.Reader) ([]string, error) {
+	var issues []string
+	for _, annotation := range operands.GetActivePatchAnnotations(req.Instance) {
+		if err := operands.ValidatePatchAnnotation(req.Instance, annotation); err != nil {
+			issues = append(issues, fmt.Sprintf("the %s annotation can't be applied: %v", annotation, err))
+		}
+	}
+
+	return issues, nil
+}
+
+// checkRequiredStorageClasses reports the storage classes that are referenced by the HyperConverged CR, and are missing
+func checkRequiredStorageClasses(req *common.HcoRequest, reader client.Reader) ([]string, error) {
+	name := req.Instance.Spec.LocalStorageClassName
+	if name == "" {
+		return nil, nil
+	}
+
+	sc := &storagev1.StorageClass{}
+	err := reader.Get(req.Ctx, client.ObjectKey{Name: name}, sc)
+	if apierrors.IsNotFound(err) {
+		return []string{fmt.Sprintf("the %s local storage class does not exist", name)}, nil
+	}
+
+	return nil, err
+}
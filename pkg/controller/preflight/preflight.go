@@ -0,0 +1,101 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+)
+
+// Check is an upgrade pre-flight check, that runs when HCO detects a version change, before the upgrade starts.
+type Check struct {
+	// Name is the unique name of the check. It is reported in the HyperConverged status.
+	Name string
+	// Blocking checks prevent the upgrade when they fail, unless the upgrade is forced by the
+	// common.ForceUpgradeAnnotationName annotation. Failures of the other checks are only reported.
+	Blocking bool
+	// Run returns the issues that the check found; the check passes if there are none. The reader is not limited to
+	// the namespace of the HyperConverged CR.
+	Run func(req *common.HcoRequest, reader client.Reader) ([]string, error)
+}
+
+// Registry is the ordered list of the upgrade pre-flight checks
+type Registry struct {
+	checks []Check
+}
+
+// NewRegistry validates the checks and returns a registry that runs them in the given order.
+func NewRegistry(checks ...Check) (*Registry, error) {
+	names := make(map[string]bool, len(checks))
+	registry := &Registry{checks: make([]Check, 0, len(checks))}
+
+	for _, c := range checks {
+		if c.Name == "" {
+			return nil, errors.New("missing check name")
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicated check name %s", c.Name)
+		}
+		names[c.Name] = true
+
+		if c.Run == nil {
+			return nil, fmt.Errorf("check %s must define the Run function", c.Name)
+		}
+
+		registry.checks = append(registry.checks, c)
+	}
+
+	return registry, nil
+}
+
+// Run runs all the checks, reports their results in the HyperConverged status, and returns the names of the failed
+// blocking checks. A check that can't run is reported as failed.
+func (r Registry) Run(req *common.HcoRequest, reader client.Reader) []string {
+	var failedBlocking []string
+	results := make([]hcov1beta1.UpgradePreflightCheckStatus, 0, len(r.checks))
+
+	for _, c := range r.checks {
+		result := hcov1beta1.UpgradePreflightCheckStatus{Name: c.Name, Blocking: c.Blocking}
+
+		issues, err := c.Run(req, reader)
+		if err != nil {
+			req.Logger.Error(err, "failed to run the upgrade pre-flight check", "check", c.Name)
+			issues = []string{fmt.Sprintf("failed to run the check: %v", err)}
+		}
+
+		result.Passed = len(issues) == 0
+		if !result.Passed {
+			result.Message = strings.Join(issues, "; ")
+			req.Logger.Info("upgrade pre-flight check failed", "check", c.Name, "blocking", c.Blocking, "issues", result.Message)
+			if c.Blocking {
+				failedBlocking = append(failedBlocking, c.Name)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	req.Instance.Status.UpgradePreflightChecks = results
+	req.StatusDirty = true
+
+	return failedBlocking
+}
+
+var registry *Registry
+
+// GetRegistry returns the registry of the HCO upgrade pre-flight checks
+func GetRegistry() *Registry {
+	return registry
+}
+
+func init() {
+	var err error
+	registry, err = NewRegistry(upgradeChecks...)
+	if err != nil {
+		panic(err)
+	}
+}
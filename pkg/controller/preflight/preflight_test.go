@@ -0,0 +1,163 @@
+package preflight
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+)
+
+var _ = Describe("Upgrade pre-flight checks", func() {
+	var hco *hcov1beta1.HyperConverged
+	var req *common.HcoRequest
+
+	BeforeEach(func() {
+		hco = commonTestUtils.NewHco()
+		req = commonTestUtils.NewReq(hco)
+	})
+
+	newCheck := func(name string, blocking bool, issues ...string) Check {
+		return Check{
+			Name:     name,
+			Blocking: blocking,
+			Run: func(_ *common.HcoRequest, _ client.Reader) ([]string, error) {
+				return issues, nil
+			},
+		}
+	}
+
+	Context("NewRegistry", func() {
+		table.DescribeTable("should reject wrong checks", func(checks []Check, errMsg string) {
+			registry, err := NewRegistry(checks...)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(errMsg))
+			Expect(registry).To(BeNil())
+		},
+			table.Entry("missing name",
+				[]Check{newCheck("", true)},
+				"missing check name"),
+			table.Entry("duplicated name",
+				[]Check{newCheck("a", true), newCheck("a", false)},
+				"duplicated check name a"),
+			table.Entry("missing run function",
+				[]Check{{Name: "a"}},
+				"check a must define the Run function"),
+		)
+
+		It("should validate the registered checks", func() {
+			Expect(GetRegistry()).ToNot(BeNil())
+			Expect(GetRegistry().checks).To(HaveLen(len(upgradeChecks)))
+		})
+	})
+
+	Context("Run", func() {
+		It("should report the results of all the checks, and return the failed blocking checks", func() {
+			failing := newCheck("failing", false)
+			failing.Run = func(_ *common.HcoRequest, _ client.Reader) ([]string, error) {
+				return nil, errors.New("fake error")
+			}
+
+			registry, err := NewRegistry(
+				newCheck("passed", true),
+				newCheck("warning", false, "first issue", "second issue"),
+				newCheck("blocking", true, "an issue"),
+				failing,
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil)).To(Equal([]string{"blocking"}))
+			Expect(hco.Status.UpgradePreflightChecks).To(Equal([]hcov1beta1.UpgradePreflightCheckStatus{
+				{Name: "passed", Blocking: true, Passed: true},
+				{Name: "warning", Blocking: false, Passed: false, Message: "first issue; second issue"},
+				{Name: "blocking", Blocking: true, Passed: false, Message: "an issue"},
+				{Name: "failing", Blocking: false, Passed: false, Message: "failed to run the check: fake error"},
+			}))
+			Expect(req.StatusDirty).To(BeTrue())
+		})
+
+		It("should replace the results of the previous run", func() {
+			hco.Status.UpgradePreflightChecks = []hcov1beta1.UpgradePreflightCheckStatus{{Name: "old"}}
+			registry, err := NewRegistry(newCheck("passed", true))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(registry.Run(req, nil)).To(BeEmpty())
+			Expect(hco.Status.UpgradePreflightChecks).To(Equal([]hcov1beta1.UpgradePreflightCheckStatus{
+				{Name: "passed", Blocking: true, Passed: true},
+			}))
+		})
+	})
+
+	Context("deprecated-operand-apis", func() {
+		It("should report the related objects with deprecated API versions", func() {
+			hco.Status.RelatedObjects = []corev1.ObjectReference{
+				{APIVersion: "kubevirt.io/v1", Kind: "KubeVirt", Name: "kubevirt-kubevirt-hyperconverged"},
+				{APIVersion: "ssp.kubevirt.io/v1", Kind: "KubevirtCommonTemplatesBundle", Name: "common-templates-kubevirt-hyperconverged"},
+			}
+
+			issues, err := checkDeprecatedOperandAPIs(req, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issues).To(Equal([]string{
+				"KubevirtCommonTemplatesBundle common-templates-kubevirt-hyperconverged uses the deprecated ssp.kubevirt.io/v1 API",
+			}))
+		})
+	})
+
+	Context("patch-annotations", func() {
+		It("should pass without patch annotations", func() {
+			issues, err := checkPatchAnnotations(req, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issues).To(BeEmpty())
+		})
+
+		It("should report only the patch annotations that can't be applied", func() {
+			hco.Annotations = map[string]string{
+				common.JSONPatchKVAnnotationName:        `[{"op": "add", "path": "/spec/configuration/cpuRequest", "value": "12m"}]`,
+				common.JSONPatchCDIAnnotationName:       "not a json",
+				common.MergePatchKVConfigAnnotationName: `{"data": {"debug.useEmulation": "true"}}`,
+			}
+
+			issues, err := checkPatchAnnotations(req, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issues).To(HaveLen(1))
+			Expect(issues[0]).To(HavePrefix("the containerizeddataimporter.kubevirt.io/jsonpatch annotation can't be applied: "))
+		})
+	})
+
+	Context("required-storage-classes", func() {
+		It("should pass if the local storage class is not set", func() {
+			cl := commonTestUtils.InitClient([]runtime.Object{hco})
+
+			issues, err := checkRequiredStorageClasses(req, cl)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issues).To(BeEmpty())
+		})
+
+		It("should report a missing local storage class", func() {
+			hco.Spec.LocalStorageClassName = "local"
+			cl := commonTestUtils.InitClient([]runtime.Object{hco})
+
+			issues, err := checkRequiredStorageClasses(req, cl)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issues).To(Equal([]string{"the local local storage class does not exist"}))
+		})
+
+		It("should pass if the local storage class exists", func() {
+			hco.Spec.LocalStorageClassName = "local"
+			cl := commonTestUtils.InitClient([]runtime.Object{hco, &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "local"}}})
+
+			issues, err := checkRequiredStorageClasses(req, cl)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(issues).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,373 @@
+package webhookconfig
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+var log = logf.Log.WithName("controller_webhookconfig")
+
+const (
+	webhookConfigurationDriftReason = "WebhookConfigurationDrift"
+	caBundleMismatchReason          = "WebhookCABundleMismatch"
+)
+
+// all the webhook configurations are reconciled together, by a single request
+var webhookConfigurationsRequest = reconcile.Request{
+	NamespacedName: types.NamespacedName{Name: "hco-webhook-configurations"},
+}
+
+// the webhooks that are served by the HCO webhook Pod. Their caBundles are signed by the same CA, so a valid caBundle
+// of one of them is used to restore the caBundle of the others.
+var hcoWebhookNames = map[string]bool{
+	hcoutil.HcoValidatingWebhook: true,
+	hcoutil.HcoMutatingWebhook:   true,
+	hcoutil.HcoMutatingWebhookNS: true,
+	hcoutil.HcoOperandWebhook:    true,
+}
+
+// webhookSettings are the settings of a webhook that are enforced by the controller. A nil selector is not enforced.
+type webhookSettings struct {
+	namespaceSelector *metav1.LabelSelector
+	objectSelector    *metav1.LabelSelector
+	failurePolicy     admissionregistrationv1.FailurePolicyType
+	timeoutSeconds    int32
+}
+
+// getWebhookSettings returns the enforced settings of each webhook, by its name
+func getWebhookSettings(namespace string) map[string]webhookSettings {
+	return map[string]webhookSettings{
+		// OLM limits the webhook to the namespaces of the OperatorGroup. HCO must intercept the requests of all the
+		// namespaces, to reject a HyperConverged CR that is not created in the HCO namespace.
+		hcoutil.HcoValidatingWebhook: {
+			namespaceSelector: &metav1.LabelSelector{},
+			failurePolicy:     admissionregistrationv1.Fail,
+			timeoutSeconds:    hcoutil.WebhookTimeoutSeconds,
+		},
+		// blocks the deletion of the HCO namespace while the HyperConverged CR exists
+		hcoutil.HcoMutatingWebhookNS: {
+			objectSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"name": namespace},
+			},
+			failurePolicy:  admissionregistrationv1.Fail,
+			timeoutSeconds: hcoutil.WebhookTimeoutSeconds,
+		},
+		// sets the defaults of the HyperConverged CR
+		hcoutil.HcoMutatingWebhook: {
+			failurePolicy:  admissionregistrationv1.Fail,
+			timeoutSeconds: hcoutil.WebhookTimeoutSeconds,
+		},
+		// the operand guard is optional, and must not block the operand CRs if the HCO webhook is not available
+		hcoutil.HcoOperandWebhook: {
+			failurePolicy:  admissionregistrationv1.Ignore,
+			timeoutSeconds: hcoutil.OperandWebhookTimeoutSeconds,
+		},
+	}
+}
+
+// RegisterReconciler creates a new webhook configuration Reconciler and registers it into manager. The reconciler
+// restores the HCO webhooks in the ValidatingWebhookConfigurations and in the MutatingWebhookConfigurations, when they
+// are changed or regenerated, e.g. by OLM.
+func RegisterReconciler(mgr manager.Manager, namespace string) error {
+	return add(mgr, newReconciler(mgr, namespace))
+}
+
+func newReconciler(mgr manager.Manager, namespace string) *ReconcileWebhookConfigurations {
+	return &ReconcileWebhookConfigurations{
+		client:       mgr.GetClient(),
+		settings:     getWebhookSettings(namespace),
+		certFile:     filepath.Join(hcov1beta1.GetWebhookCertDir(), hcov1beta1.WebhookCertName),
+		eventEmitter: hcoutil.GetEventEmitter(),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("webhook-configuration-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	enqueue := handler.EnqueueRequestsFromMapFunc(func(_ client.Object) []reconcile.Request {
+		return []reconcile.Request{webhookConfigurationsRequest}
+	})
+
+	for _, obj := range []client.Object{
+		&admissionregistrationv1.ValidatingWebhookConfiguration{},
+		&admissionregistrationv1.MutatingWebhookConfiguration{},
+	} {
+		if err := c.Watch(&source.Kind{Type: obj}, enqueue, predicate.NewPredicateFuncs(hasHcoWebhook)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasHcoWebhook(obj client.Object) bool {
+	switch config := obj.(type) {
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		for _, wh := range config.Webhooks {
+			if hcoWebhookNames[wh.Name] {
+				return true
+			}
+		}
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		for _, wh := range config.Webhooks {
+			if hcoWebhookNames[wh.Name] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReconcileWebhookConfigurations enforces the settings of the HCO webhooks
+type ReconcileWebhookConfigurations struct {
+	client   client.Client
+	settings map[string]webhookSettings
+	// the serving certificate of the webhook, that the caBundle of the webhooks must verify
+	certFile     string
+	eventEmitter hcoutil.EventEmitter
+}
+
+// Reconcile restores the settings of the HCO webhooks in all the webhook configurations, and emits an event for each
+// webhook that was changed
+func (r *ReconcileWebhookConfigurations) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	vwcList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := r.client.List(ctx, vwcList); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	mwcList := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := r.client.List(ctx, mwcList); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	caBundles := newCABundleChecker(r.certFile, vwcList, mwcList)
+
+	var errs []error
+	for i := range vwcList.Items {
+		config := &vwcList.Items[i]
+
+		changed := false
+		for j := range config.Webhooks {
+			wh := &config.Webhooks[j]
+			fields := webhookFields{
+				namespaceSelector: &wh.NamespaceSelector,
+				objectSelector:    &wh.ObjectSelector,
+				failurePolicy:     &wh.FailurePolicy,
+				timeoutSeconds:    &wh.TimeoutSeconds,
+				caBundle:          &wh.ClientConfig.CABundle,
+			}
+			if r.enforce(config, wh.Name, fields, caBundles) {
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := r.client.Update(ctx, config); err != nil {
+				log.Error(err, "failed to update the webhook configuration", "name", config.Name)
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for i := range mwcList.Items {
+		config := &mwcList.Items[i]
+
+		changed := false
+		for j := range config.Webhooks {
+			wh := &config.Webhooks[j]
+			fields := webhookFields{
+				namespaceSelector: &wh.NamespaceSelector,
+				objectSelector:    &wh.ObjectSelector,
+				failurePolicy:     &wh.FailurePolicy,
+				timeoutSeconds:    &wh.TimeoutSeconds,
+				caBundle:          &wh.ClientConfig.CABundle,
+			}
+			if r.enforce(config, wh.Name, fields, caBundles) {
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := r.client.Update(ctx, config); err != nil {
+				log.Error(err, "failed to update the webhook configuration", "name", config.Name)
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return reconcile.Result{}, utilerrors.NewAggregate(errs)
+}
+
+// webhookFields points to the enforced fields of a ValidatingWebhook or of a MutatingWebhook
+type webhookFields struct {
+	namespaceSelector **metav1.LabelSelector
+	objectSelector    **metav1.LabelSelector
+	failurePolicy     **admissionregistrationv1.FailurePolicyType
+	timeoutSeconds    **int32
+	caBundle          *[]byte
+}
+
+// enforce restores the settings of one webhook, and returns true if the webhook was changed
+func (r *ReconcileWebhookConfigurations) enforce(config client.Object, name string, fields webhookFields, caBundles *caBundleChecker) bool {
+	settings, found := r.settings[name]
+	if !found {
+		return false
+	}
+
+	var drifts []string
+
+	if settings.namespaceSelector != nil && !equality.Semantic.DeepEqual(*fields.namespaceSelector, settings.namespaceSelector) {
+		drifts = append(drifts, "namespaceSelector")
+		*fields.namespaceSelector = settings.namespaceSelector.DeepCopy()
+	}
+
+	if settings.objectSelector != nil && !equality.Semantic.DeepEqual(*fields.objectSelector, settings.objectSelector) {
+		drifts = append(drifts, "objectSelector")
+		*fields.objectSelector = settings.objectSelector.DeepCopy()
+	}
+
+	if *fields.failurePolicy == nil || **fields.failurePolicy != settings.failurePolicy {
+		drifts = append(drifts, "failurePolicy")
+		failurePolicy := settings.failurePolicy
+		*fields.failurePolicy = &failurePolicy
+	}
+
+	if *fields.timeoutSeconds == nil || **fields.timeoutSeconds != settings.timeoutSeconds {
+		drifts = append(drifts, "timeoutSeconds")
+		timeoutSeconds := settings.timeoutSeconds
+		*fields.timeoutSeconds = &timeoutSeconds
+	}
+
+	if caBundle, ok := caBundles.check(*fields.caBundle); !ok {
+		if caBundle == nil {
+			msg := fmt.Sprintf("The caBundle of the %s webhook in %s does not verify the serving certificate of the HCO webhook, and no valid caBundle was found", name, config.GetName())
+			log.Info(msg)
+			r.eventEmitter.EmitEvent(config, corev1.EventTypeWarning, caBundleMismatchReason, msg)
+		} else {
+			drifts = append(drifts, "caBundle")
+			*fields.caBundle = caBundle
+		}
+	}
+
+	if len(drifts) == 0 {
+		return false
+	}
+
+	msg := fmt.Sprintf("Restored %s of the %s webhook in %s", strings.Join(drifts, ", "), name, config.GetName())
+	log.Info(msg)
+	r.eventEmitter.EmitEvent(config, corev1.EventTypeWarning, webhookConfigurationDriftReason, msg)
+
+	return true
+}
+
+// caBundleChecker checks the caBundles of the webhooks against the serving certificate of the HCO webhook
+type caBundleChecker struct {
+	// nil if the certificate can't be read, e.g. when running locally; then the caBundles are not enforced
+	cert *x509.Certificate
+	// a caBundle of the HCO webhooks that verifies the certificate; nil if there is none
+	valid []byte
+}
+
+func newCABundleChecker(certFile string, vwcList *admissionregistrationv1.ValidatingWebhookConfigurationList, mwcList *admissionregistrationv1.MutatingWebhookConfigurationList) *caBundleChecker {
+	cert, err := readCertificate(certFile)
+	if err != nil {
+		log.Error(err, "can't read the serving certificate; the caBundles of the webhooks are not checked", "file", certFile)
+		return &caBundleChecker{}
+	}
+
+	checker := &caBundleChecker{cert: cert}
+
+	var bundles [][]byte
+	for _, config := range vwcList.Items {
+		for _, wh := range config.Webhooks {
+			if hcoWebhookNames[wh.Name] {
+				bundles = append(bundles, wh.ClientConfig.CABundle)
+			}
+		}
+	}
+	for _, config := range mwcList.Items {
+		for _, wh := range config.Webhooks {
+			if hcoWebhookNames[wh.Name] {
+				bundles = append(bundles, wh.ClientConfig.CABundle)
+			}
+		}
+	}
+
+	for _, bundle := range bundles {
+		if checker.verifies(bundle) {
+			checker.valid = bundle
+			break
+		}
+	}
+
+	return checker
+}
+
+// check returns true if the caBundle verifies the serving certificate, or if the certificate is not known. Otherwise,
+// it returns a valid caBundle to replace it, if any.
+func (c *caBundleChecker) check(caBundle []byte) ([]byte, bool) {
+	if c.cert == nil || c.verifies(caBundle) {
+		return nil, true
+	}
+
+	if c.valid == nil {
+		return nil, false
+	}
+
+	return append([]byte(nil), c.valid...), false
+}
+
+func (c *caBundleChecker) verifies(caBundle []byte) bool {
+	roots := x509.NewCertPool()
+	if len(caBundle) == 0 || !roots.AppendCertsFromPEM(caBundle) {
+		return false
+	}
+
+	_, err := c.cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+func readCertificate(certFile string) (*x509.Certificate, error) {
+	content, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate was found in %s", certFile)
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
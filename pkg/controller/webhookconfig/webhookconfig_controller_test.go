@@ -0,0 +1,288 @@
+package webhookconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// newCertificate returns a new self-signed CA certificate, in the PEM format
+func newCertificate() []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "hyperconverged-cluster-webhook-service.kubevirt-hyperconverged.svc"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = Describe("webhook configuration controller", func() {
+	var (
+		certDir      string
+		servingCert  []byte
+		otherCert    []byte
+		eventEmitter *commonTestUtils.EventEmitterMock
+	)
+
+	failurePolicy := func(policy admissionregistrationv1.FailurePolicyType) *admissionregistrationv1.FailurePolicyType {
+		return &policy
+	}
+
+	timeout := func(seconds int32) *int32 {
+		return &seconds
+	}
+
+	newValidatingConfig := func(caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+		return &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "validate-hco.kubevirt.io-abcde"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{
+					Name:              hcoutil.HcoValidatingWebhook,
+					ClientConfig:      admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+					NamespaceSelector: &metav1.LabelSelector{},
+					FailurePolicy:     failurePolicy(admissionregistrationv1.Fail),
+					TimeoutSeconds:    timeout(hcoutil.WebhookTimeoutSeconds),
+				},
+			},
+		}
+	}
+
+	newMutatingConfig := func(caBundle []byte) *admissionregistrationv1.MutatingWebhookConfiguration {
+		return &admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "mutate-ns-hco.kubevirt.io-abcde"},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{
+					Name:         hcoutil.HcoMutatingWebhookNS,
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+					ObjectSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"name": commonTestUtils.Namespace},
+					},
+					FailurePolicy:  failurePolicy(admissionregistrationv1.Fail),
+					TimeoutSeconds: timeout(hcoutil.WebhookTimeoutSeconds),
+				},
+			},
+		}
+	}
+
+	newReconciler := func(cl client.Client) *ReconcileWebhookConfigurations {
+		return &ReconcileWebhookConfigurations{
+			client:       cl,
+			settings:     getWebhookSettings(commonTestUtils.Namespace),
+			certFile:     filepath.Join(certDir, "apiserver.crt"),
+			eventEmitter: eventEmitter,
+		}
+	}
+
+	reconcileObjects := func(objects ...runtime.Object) *commonTestUtils.HcoTestClient {
+		cl := commonTestUtils.InitClient(objects)
+		_, err := newReconciler(cl).Reconcile(context.TODO(), webhookConfigurationsRequest)
+		Expect(err).ToNot(HaveOccurred())
+		return cl
+	}
+
+	// fails if any webhook configuration is updated
+	reconcileWithoutUpdates := func(objects ...runtime.Object) {
+		cl := commonTestUtils.InitClient(objects)
+		cl.InitiateUpdateErrors(func(obj client.Object) error {
+			return fmt.Errorf("unexpected update of %s", obj.GetName())
+		})
+
+		_, err := newReconciler(cl).Reconcile(context.TODO(), webhookConfigurationsRequest)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	getValidatingConfig := func(cl client.Client, name string) *admissionregistrationv1.ValidatingWebhookConfiguration {
+		config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(cl.Get(context.TODO(), client.ObjectKey{Name: name}, config)).To(Succeed())
+		return config
+	}
+
+	getMutatingConfig := func(cl client.Client, name string) *admissionregistrationv1.MutatingWebhookConfiguration {
+		config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		Expect(cl.Get(context.TODO(), client.ObjectKey{Name: name}, config)).To(Succeed())
+		return config
+	}
+
+	BeforeEach(func() {
+		var err error
+		certDir, err = ioutil.TempDir("", "webhook-certs")
+		Expect(err).ToNot(HaveOccurred())
+
+		servingCert = newCertificate()
+		otherCert = newCertificate()
+		Expect(ioutil.WriteFile(filepath.Join(certDir, "apiserver.crt"), servingCert, 0600)).To(Succeed())
+
+		eventEmitter = commonTestUtils.NewEventEmitterMock()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(certDir)).To(Succeed())
+	})
+
+	It("should not change the webhook configurations if they are as expected", func() {
+		reconcileWithoutUpdates(newValidatingConfig(servingCert), newMutatingConfig(servingCert))
+	})
+
+	It("should restore the namespaceSelector of the validating webhook, and report the drift", func() {
+		vwc := newValidatingConfig(servingCert)
+		vwc.Webhooks[0].NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"olm.operatorgroup.uid/1234": ""},
+		}
+		cl := reconcileObjects(vwc)
+
+		Expect(getValidatingConfig(cl, vwc.Name).Webhooks[0].NamespaceSelector).To(Equal(&metav1.LabelSelector{}))
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+			EventType: corev1.EventTypeWarning,
+			Reason:    webhookConfigurationDriftReason,
+			Msg:       "Restored namespaceSelector of the " + hcoutil.HcoValidatingWebhook + " webhook in " + vwc.Name,
+		}})).To(BeTrue())
+	})
+
+	It("should restore the objectSelector, the failure policy and the timeout of the namespace webhook", func() {
+		mwc := newMutatingConfig(servingCert)
+		mwc.Webhooks[0].ObjectSelector = nil
+		mwc.Webhooks[0].FailurePolicy = failurePolicy(admissionregistrationv1.Ignore)
+		mwc.Webhooks[0].TimeoutSeconds = timeout(10)
+		cl := reconcileObjects(mwc)
+
+		wh := getMutatingConfig(cl, mwc.Name).Webhooks[0]
+		Expect(wh.ObjectSelector.MatchLabels).To(Equal(map[string]string{"name": commonTestUtils.Namespace}))
+		Expect(*wh.FailurePolicy).To(Equal(admissionregistrationv1.Fail))
+		Expect(*wh.TimeoutSeconds).To(Equal(hcoutil.WebhookTimeoutSeconds))
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+			EventType: corev1.EventTypeWarning,
+			Reason:    webhookConfigurationDriftReason,
+			Msg:       "Restored objectSelector, failurePolicy, timeoutSeconds of the " + hcoutil.HcoMutatingWebhookNS + " webhook in " + mwc.Name,
+		}})).To(BeTrue())
+	})
+
+	It("should restore the failure policy and the timeout of the operand webhook", func() {
+		vwc := newValidatingConfig(servingCert)
+		vwc.Name = "validate-operands-hco.kubevirt.io-abcde"
+		vwc.Webhooks[0].Name = hcoutil.HcoOperandWebhook
+		vwc.Webhooks[0].NamespaceSelector = nil
+		vwc.Webhooks[0].FailurePolicy = failurePolicy(admissionregistrationv1.Fail)
+		vwc.Webhooks[0].TimeoutSeconds = timeout(hcoutil.WebhookTimeoutSeconds)
+		cl := reconcileObjects(vwc)
+
+		wh := getValidatingConfig(cl, vwc.Name).Webhooks[0]
+		Expect(wh.NamespaceSelector).To(BeNil())
+		Expect(*wh.FailurePolicy).To(Equal(admissionregistrationv1.Ignore))
+		Expect(*wh.TimeoutSeconds).To(Equal(hcoutil.OperandWebhookTimeoutSeconds))
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+			EventType: corev1.EventTypeWarning,
+			Reason:    webhookConfigurationDriftReason,
+			Msg:       "Restored failurePolicy, timeoutSeconds of the " + hcoutil.HcoOperandWebhook + " webhook in " + vwc.Name,
+		}})).To(BeTrue())
+	})
+
+	It("should restore the caBundle of the HyperConverged mutating webhook", func() {
+		vwc := newValidatingConfig(servingCert)
+		mwc := newMutatingConfig(otherCert)
+		mwc.Name = "mutate-hco.kubevirt.io-abcde"
+		mwc.Webhooks[0].Name = hcoutil.HcoMutatingWebhook
+		mwc.Webhooks[0].ObjectSelector = nil
+		cl := reconcileObjects(vwc, mwc)
+
+		Expect(getMutatingConfig(cl, mwc.Name).Webhooks[0].ClientConfig.CABundle).To(Equal(servingCert))
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+			EventType: corev1.EventTypeWarning,
+			Reason:    webhookConfigurationDriftReason,
+			Msg:       "Restored caBundle of the " + hcoutil.HcoMutatingWebhook + " webhook in " + mwc.Name,
+		}})).To(BeTrue())
+	})
+
+	It("should restore the caBundle from another HCO webhook", func() {
+		vwc := newValidatingConfig(otherCert)
+		mwc := newMutatingConfig(servingCert)
+		cl := reconcileObjects(vwc, mwc)
+
+		Expect(getValidatingConfig(cl, vwc.Name).Webhooks[0].ClientConfig.CABundle).To(Equal(servingCert))
+		Expect(getMutatingConfig(cl, mwc.Name).Webhooks[0].ClientConfig.CABundle).To(Equal(servingCert))
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+			EventType: corev1.EventTypeWarning,
+			Reason:    webhookConfigurationDriftReason,
+			Msg:       "Restored caBundle of the " + hcoutil.HcoValidatingWebhook + " webhook in " + vwc.Name,
+		}})).To(BeTrue())
+	})
+
+	It("should report a caBundle mismatch if no HCO webhook has a valid caBundle", func() {
+		vwc := newValidatingConfig(otherCert)
+		cl := reconcileObjects(vwc)
+
+		Expect(getValidatingConfig(cl, vwc.Name).Webhooks[0].ClientConfig.CABundle).To(Equal(otherCert))
+		Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+			EventType: corev1.EventTypeWarning,
+			Reason:    caBundleMismatchReason,
+			Msg: "The caBundle of the " + hcoutil.HcoValidatingWebhook + " webhook in " + vwc.Name +
+				" does not verify the serving certificate of the HCO webhook, and no valid caBundle was found",
+		}})).To(BeTrue())
+	})
+
+	It("should not check the caBundles if the serving certificate can't be read", func() {
+		Expect(os.Remove(filepath.Join(certDir, "apiserver.crt"))).To(Succeed())
+
+		reconcileWithoutUpdates(newValidatingConfig(otherCert))
+	})
+
+	It("should ignore the other webhooks", func() {
+		vwc := newValidatingConfig(servingCert)
+		vwc.Name = "other"
+		vwc.Webhooks[0].Name = "validate.example.com"
+		vwc.Webhooks[0].NamespaceSelector = nil
+		vwc.Webhooks[0].FailurePolicy = failurePolicy(admissionregistrationv1.Ignore)
+		reconcileWithoutUpdates(vwc)
+		Expect(hasHcoWebhook(vwc)).To(BeFalse())
+	})
+
+	It("should watch only the configurations of the HCO webhooks", func() {
+		Expect(hasHcoWebhook(newValidatingConfig(nil))).To(BeTrue())
+		Expect(hasHcoWebhook(newMutatingConfig(nil))).To(BeTrue())
+		Expect(hasHcoWebhook(&corev1.ConfigMap{})).To(BeFalse())
+	})
+
+	It("should return the update errors", func() {
+		vwc := newValidatingConfig(servingCert)
+		vwc.Webhooks[0].TimeoutSeconds = timeout(10)
+
+		cl := commonTestUtils.InitClient([]runtime.Object{vwc})
+		cl.InitiateUpdateErrors(func(obj client.Object) error {
+			return os.ErrPermission
+		})
+
+		_, err := newReconciler(cl).Reconcile(context.TODO(), webhookConfigurationsRequest)
+		Expect(err).To(HaveOccurred())
+	})
+})
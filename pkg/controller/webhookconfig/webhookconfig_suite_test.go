@@ -0,0 +1,13 @@
+package webhookconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebhookConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhook Configuration Controller Suite")
+}
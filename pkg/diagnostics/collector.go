@@ -0,0 +1,387 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	csvv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+)
+
+const (
+	// ArchiveRoot is the root directory of all the files in the archive
+	ArchiveRoot = "hco-diagnostics"
+
+	// RedactedValue replaces the values of the secrets
+	RedactedValue = "<redacted>"
+
+	// MaxEvents is the maximal number of events in the archive. The most recent events are kept.
+	MaxEvents = 200
+
+	clusterScopedDir = "_cluster"
+	coreGroupDir     = "core"
+	fileMode         = 0644
+
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// the values of the "name" label of the HCO pods
+var hcoPodNames = []string{"hyperconverged-cluster-operator", "hyperconverged-cluster-webhook"}
+
+var hcoGVK = hcov1beta1.SchemeGroupVersion.WithKind("HyperConverged")
+
+// PodLogsReader reads the logs of a pod container
+type PodLogsReader interface {
+	GetLogs(ctx context.Context, namespace, pod, container string) ([]byte, error)
+}
+
+// Collector collects the state of HCO and of the resources that HCO manages into a tar archive
+type Collector struct {
+	client    client.Client
+	logs      PodLogsReader
+	namespace string
+	name      string
+}
+
+// NewCollector creates a collector for the HyperConverged CR in the namespace
+func NewCollector(cl client.Client, logs PodLogsReader, namespace string) *Collector {
+	return &Collector{
+		client:    cl,
+		logs:      logs,
+		namespace: namespace,
+		name:      hcov1beta1.HyperConvergedName,
+	}
+}
+
+// collection is the content of the archive, by the file path
+type collection struct {
+	files  map[string][]byte
+	errors []string
+}
+
+func (c *collection) addFile(filePath string, content []byte) {
+	c.files[path.Join(ArchiveRoot, filePath)] = content
+}
+
+func (c *collection) addError(format string, args ...interface{}) {
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+// Collect writes the archive to out. Failures to read specific resources don't stop the collection; they are listed
+// in the errors.txt file of the archive. An error is returned only if the archive can't be written.
+func (c *Collector) Collect(ctx context.Context, out io.Writer) error {
+	col := &collection{files: make(map[string][]byte)}
+
+	involvedObjects := make(map[string]bool)
+
+	hc := c.collectHyperConverged(ctx, col)
+	if hc != nil {
+		involvedObjects[involvedObjectKey(hc.GetKind(), hc.GetName())] = true
+		c.collectRelatedObjects(ctx, col, hc)
+	}
+
+	for _, name := range c.collectPods(ctx, col) {
+		involvedObjects[involvedObjectKey("Pod", name)] = true
+	}
+
+	for _, name := range c.collectCSVs(ctx, col) {
+		involvedObjects[involvedObjectKey("ClusterServiceVersion", name)] = true
+	}
+
+	c.collectEvents(ctx, col, involvedObjects)
+
+	if len(col.errors) > 0 {
+		col.addFile("errors.txt", []byte(strings.Join(col.errors, "\n")+"\n"))
+	}
+
+	return writeArchive(out, col.files)
+}
+
+func (c *Collector) collectHyperConverged(ctx context.Context, col *collection) *unstructured.Unstructured {
+	hc := &unstructured.Unstructured{}
+	hc.SetGroupVersionKind(hcoGVK)
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: c.name}, hc); err != nil {
+		col.addError("can't read the HyperConverged CR %s/%s: %v", c.namespace, c.name, err)
+		return nil
+	}
+
+	c.addObject(col, "hyperconverged.yaml", hc)
+	return hc
+}
+
+// collectRelatedObjects writes the objects of status.relatedObjects, and a summary of their conditions
+func (c *Collector) collectRelatedObjects(ctx context.Context, col *collection, hc *unstructured.Unstructured) {
+	conditions := map[string]interface{}{}
+	if hcConditions, found, _ := unstructured.NestedSlice(hc.Object, "status", "conditions"); found {
+		conditions[objectDesc(hc)] = hcConditions
+	}
+
+	refs, _, err := unstructured.NestedSlice(hc.Object, "status", "relatedObjects")
+	if err != nil {
+		col.addError("can't read the related objects of the HyperConverged CR: %v", err)
+	}
+
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		apiVersion, _, _ := unstructured.NestedString(ref, "apiVersion")
+		kind, _, _ := unstructured.NestedString(ref, "kind")
+		namespace, _, _ := unstructured.NestedString(ref, "namespace")
+		name, _, _ := unstructured.NestedString(ref, "name")
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		if err = c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+			col.addError("can't read the related object %s %s/%s: %v", kind, namespace, name, err)
+			continue
+		}
+
+		c.addObject(col, relatedObjectPath(obj.GroupVersionKind(), namespace, name), obj)
+
+		if objConditions, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions"); found {
+			conditions[objectDesc(obj)] = objConditions
+		}
+	}
+
+	content, err := yaml.Marshal(conditions)
+	if err != nil {
+		col.addError("can't write the conditions: %v", err)
+		return
+	}
+	col.addFile("conditions.yaml", content)
+}
+
+// collectPods writes the HCO pods and their logs, and returns the pod names
+func (c *Collector) collectPods(ctx context.Context, col *collection) []string {
+	selector, err := labelInSelector("name", hcoPodNames)
+	if err != nil {
+		col.addError("can't list the HCO pods: %v", err)
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err = c.client.List(ctx, pods, client.InNamespace(c.namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		col.addError("can't list the HCO pods: %v", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		names = append(names, pod.Name)
+		c.addTypedObject(col, path.Join("pods", pod.Name+".yaml"), pod)
+
+		if c.logs == nil {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			logs, err := c.logs.GetLogs(ctx, pod.Namespace, pod.Name, container.Name)
+			if err != nil {
+				col.addError("can't read the logs of the %s container of the %s pod: %v", container.Name, pod.Name, err)
+				continue
+			}
+			col.addFile(path.Join("logs", pod.Name, container.Name+".log"), logs)
+		}
+	}
+
+	return names
+}
+
+// collectCSVs writes the ClusterServiceVersions of the HCO namespace, and returns their names
+func (c *Collector) collectCSVs(ctx context.Context, col *collection) []string {
+	csvs := &csvv1alpha1.ClusterServiceVersionList{}
+	if err := c.client.List(ctx, csvs, client.InNamespace(c.namespace)); err != nil {
+		col.addError("can't list the ClusterServiceVersions: %v", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(csvs.Items))
+	for i := range csvs.Items {
+		csv := &csvs.Items[i]
+		names = append(names, csv.Name)
+		c.addTypedObject(col, path.Join("csvs", csv.Name+".yaml"), csv)
+	}
+
+	return names
+}
+
+// collectEvents writes the most recent events of the HyperConverged CR, the HCO pods and the ClusterServiceVersions
+func (c *Collector) collectEvents(ctx context.Context, col *collection, involvedObjects map[string]bool) {
+	events := &corev1.EventList{}
+	if err := c.client.List(ctx, events, client.InNamespace(c.namespace)); err != nil {
+		col.addError("can't list the events: %v", err)
+		return
+	}
+
+	var selected []corev1.Event
+	for _, event := range events.Items {
+		if involvedObjects[involvedObjectKey(event.InvolvedObject.Kind, event.InvolvedObject.Name)] {
+			event.ManagedFields = nil
+			selected = append(selected, event)
+		}
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		ti, tj := eventTime(selected[i]), eventTime(selected[j])
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return selected[i].Name < selected[j].Name
+	})
+
+	if len(selected) > MaxEvents {
+		selected = selected[len(selected)-MaxEvents:]
+	}
+
+	content, err := yaml.Marshal(selected)
+	if err != nil {
+		col.addError("can't write the events: %v", err)
+		return
+	}
+	col.addFile("events.yaml", content)
+}
+
+func (c *Collector) addTypedObject(col *collection, filePath string, obj client.Object) {
+	gvk, err := apiutil.GVKForObject(obj, c.client.Scheme())
+	if err != nil {
+		col.addError("can't write %s: %v", filePath, err)
+		return
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		col.addError("can't write %s: %v", filePath, err)
+		return
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	c.addObject(col, filePath, u)
+}
+
+func (c *Collector) addObject(col *collection, filePath string, obj *unstructured.Unstructured) {
+	content, err := yaml.Marshal(sanitize(obj).Object)
+	if err != nil {
+		col.addError("can't write %s: %v", filePath, err)
+		return
+	}
+	col.addFile(filePath, content)
+}
+
+// sanitize returns a copy of the object without the managed fields, and with the secret values redacted
+func sanitize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+
+	if gvk := obj.GroupVersionKind(); gvk.Group != "" || gvk.Kind != "Secret" {
+		return obj
+	}
+
+	for _, field := range []string{"data", "stringData"} {
+		if values, found, _ := unstructured.NestedMap(obj.Object, field); found {
+			for key := range values {
+				values[key] = RedactedValue
+			}
+			_ = unstructured.SetNestedMap(obj.Object, values, field)
+		}
+	}
+
+	// the annotation may contain the secret values
+	annotations := obj.GetAnnotations()
+	if _, found := annotations[lastAppliedConfigAnnotation]; found {
+		annotations[lastAppliedConfigAnnotation] = RedactedValue
+		obj.SetAnnotations(annotations)
+	}
+
+	return obj
+}
+
+// writeArchive writes the files in the order of their paths, with fixed modes and modification times, so that the same
+// content always produces the same archive
+func writeArchive(out io.Writer, files map[string][]byte) error {
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	tw := tar.NewWriter(out)
+	for _, filePath := range paths {
+		content := files[filePath]
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     filePath,
+			Mode:     fileMode,
+			Size:     int64(len(content)),
+			ModTime:  time.Unix(0, 0),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func relatedObjectPath(gvk schema.GroupVersionKind, namespace, name string) string {
+	group := gvk.Group
+	if group == "" {
+		group = coreGroupDir
+	}
+	if namespace == "" {
+		namespace = clusterScopedDir
+	}
+
+	return path.Join("related-objects", group, strings.ToLower(gvk.Kind), namespace, name+".yaml")
+}
+
+func objectDesc(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+func involvedObjectKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+func labelInSelector(key string, values []string) (labels.Selector, error) {
+	req, err := labels.NewRequirement(key, selection.In, values)
+	if err != nil {
+		return nil, err
+	}
+	return labels.NewSelector().Add(*req), nil
+}
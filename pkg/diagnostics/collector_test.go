@@ -0,0 +1,298 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	csvv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis"
+	hcov1beta1 "github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+)
+
+const testNamespace = "kubevirt-hyperconverged"
+
+type fakeLogsReader struct {
+	failFor string
+}
+
+func (r fakeLogsReader) GetLogs(_ context.Context, namespace, pod, container string) ([]byte, error) {
+	if pod == r.failFor {
+		return nil, errors.New("fake error")
+	}
+	return []byte(fmt.Sprintf("logs of %s/%s/%s\n", namespace, pod, container)), nil
+}
+
+var _ = Describe("Collector", func() {
+	var (
+		testScheme *runtime.Scheme
+		hco        *hcov1beta1.HyperConverged
+		cdi        *cdiv1beta1.CDI
+		cm         *corev1.ConfigMap
+		secret     *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		testScheme = runtime.NewScheme()
+		for _, f := range []func(*runtime.Scheme) error{
+			clientgoscheme.AddToScheme,
+			apis.AddToScheme,
+			cdiv1beta1.AddToScheme,
+			csvv1alpha1.AddToScheme,
+		} {
+			Expect(f(testScheme)).To(Succeed())
+		}
+
+		cdi = &cdiv1beta1.CDI{
+			ObjectMeta: metav1.ObjectMeta{Name: "cdi-kubevirt-hyperconverged"},
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubevirt-config", Namespace: testNamespace},
+			Data:       map[string]string{"key": "value"},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "some-secret",
+				Namespace:   testNamespace,
+				Annotations: map[string]string{lastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`},
+			},
+			Data:       map[string][]byte{"password": []byte("secret")},
+			StringData: map[string]string{"token": "secret"},
+		}
+
+		hco = &hcov1beta1.HyperConverged{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      hcov1beta1.HyperConvergedName,
+				Namespace: testNamespace,
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: "kubectl"},
+				},
+			},
+			Status: hcov1beta1.HyperConvergedStatus{
+				Conditions: []conditionsv1.Condition{
+					{Type: conditionsv1.ConditionAvailable, Status: corev1.ConditionTrue, Reason: "ReconcileCompleted"},
+				},
+				RelatedObjects: []corev1.ObjectReference{
+					{APIVersion: "cdi.kubevirt.io/v1beta1", Kind: "CDI", Name: cdi.Name},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: cm.Name, Namespace: testNamespace},
+					{APIVersion: "v1", Kind: "Secret", Name: secret.Name, Namespace: testNamespace},
+				},
+			},
+		}
+	})
+
+	newPod := func(name, nameLabel string, containers ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels:    map[string]string{"name": nameLabel},
+			},
+		}
+		for _, container := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		}
+		return pod
+	}
+
+	newEvent := func(name, kind, objName string, minute int) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objName, Namespace: testNamespace},
+			Reason:         "Test",
+			LastTimestamp:  metav1.NewTime(time.Date(2021, 1, 1, 0, minute, 0, 0, time.UTC)),
+		}
+	}
+
+	newClient := func(objs ...runtime.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(testScheme).WithRuntimeObjects(objs...).Build()
+	}
+
+	collect := func(cl client.Client, logs PodLogsReader) []byte {
+		buf := &bytes.Buffer{}
+		Expect(NewCollector(cl, logs, testNamespace).Collect(context.TODO(), buf)).To(Succeed())
+		return buf.Bytes()
+	}
+
+	// readArchive returns the file paths in the order of the archive, and the file contents
+	readArchive := func(archive []byte) ([]string, map[string]string) {
+		var paths []string
+		contents := make(map[string]string)
+
+		tr := tar.NewReader(bytes.NewReader(archive))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hdr.ModTime.Unix()).To(BeZero())
+			Expect(hdr.Mode).To(Equal(int64(fileMode)))
+
+			content, err := ioutil.ReadAll(tr)
+			Expect(err).ToNot(HaveOccurred())
+			paths = append(paths, hdr.Name)
+			contents[hdr.Name] = string(content)
+		}
+
+		return paths, contents
+	}
+
+	It("should collect the HCO state in a fixed layout", func() {
+		cl := newClient(
+			hco, cdi, cm, secret,
+			newPod("hco-operator-1", "hyperconverged-cluster-operator", "hyperconverged-cluster-operator"),
+			newPod("hco-webhook-1", "hyperconverged-cluster-webhook", "hyperconverged-cluster-webhook"),
+			newPod("other-pod", "other", "other"),
+			&csvv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: "kubevirt-hyperconverged-operator.v1.4.0", Namespace: testNamespace}},
+		)
+
+		paths, contents := readArchive(collect(cl, fakeLogsReader{}))
+		Expect(paths).To(Equal([]string{
+			"hco-diagnostics/conditions.yaml",
+			"hco-diagnostics/csvs/kubevirt-hyperconverged-operator.v1.4.0.yaml",
+			"hco-diagnostics/events.yaml",
+			"hco-diagnostics/hyperconverged.yaml",
+			"hco-diagnostics/logs/hco-operator-1/hyperconverged-cluster-operator.log",
+			"hco-diagnostics/logs/hco-webhook-1/hyperconverged-cluster-webhook.log",
+			"hco-diagnostics/pods/hco-operator-1.yaml",
+			"hco-diagnostics/pods/hco-webhook-1.yaml",
+			"hco-diagnostics/related-objects/cdi.kubevirt.io/cdi/_cluster/cdi-kubevirt-hyperconverged.yaml",
+			"hco-diagnostics/related-objects/core/configmap/kubevirt-hyperconverged/kubevirt-config.yaml",
+			"hco-diagnostics/related-objects/core/secret/kubevirt-hyperconverged/some-secret.yaml",
+		}))
+
+		Expect(contents["hco-diagnostics/logs/hco-operator-1/hyperconverged-cluster-operator.log"]).
+			To(Equal("logs of kubevirt-hyperconverged/hco-operator-1/hyperconverged-cluster-operator\n"))
+
+		hcContent := contents["hco-diagnostics/hyperconverged.yaml"]
+		Expect(hcContent).To(ContainSubstring("kind: HyperConverged"))
+		Expect(hcContent).ToNot(ContainSubstring("managedFields"))
+
+		pod := &corev1.Pod{}
+		Expect(yaml.Unmarshal([]byte(contents["hco-diagnostics/pods/hco-operator-1.yaml"]), pod)).To(Succeed())
+		Expect(pod.Kind).To(Equal("Pod"))
+		Expect(pod.Name).To(Equal("hco-operator-1"))
+
+		conditions := map[string][]conditionsv1.Condition{}
+		Expect(yaml.Unmarshal([]byte(contents["hco-diagnostics/conditions.yaml"]), &conditions)).To(Succeed())
+		Expect(conditions).To(HaveKey("HyperConverged/kubevirt-hyperconverged/kubevirt-hyperconverged"))
+		Expect(conditions["HyperConverged/kubevirt-hyperconverged/kubevirt-hyperconverged"][0].Reason).To(Equal("ReconcileCompleted"))
+	})
+
+	It("should redact the secrets", func() {
+		cl := newClient(hco, cdi, cm, secret)
+
+		_, contents := readArchive(collect(cl, fakeLogsReader{}))
+		content := contents["hco-diagnostics/related-objects/core/secret/kubevirt-hyperconverged/some-secret.yaml"]
+
+		redacted := map[string]interface{}{}
+		Expect(yaml.Unmarshal([]byte(content), &redacted)).To(Succeed())
+		Expect(redacted["data"]).To(Equal(map[string]interface{}{"password": RedactedValue}))
+		Expect(redacted["stringData"]).To(Equal(map[string]interface{}{"token": RedactedValue}))
+		Expect(content).ToNot(ContainSubstring("c2VjcmV0"))
+		Expect(content).To(ContainSubstring(lastAppliedConfigAnnotation + ": " + RedactedValue))
+
+		// the config map values are not redacted
+		Expect(contents["hco-diagnostics/related-objects/core/configmap/kubevirt-hyperconverged/kubevirt-config.yaml"]).
+			To(ContainSubstring("key: value"))
+	})
+
+	It("should produce the same archive for the same state", func() {
+		objs := []runtime.Object{
+			hco, cdi, cm, secret,
+			newPod("hco-operator-1", "hyperconverged-cluster-operator", "hyperconverged-cluster-operator"),
+			newEvent("event-1", "HyperConverged", hco.Name, 1),
+		}
+
+		first := collect(newClient(objs...), fakeLogsReader{})
+		second := collect(newClient(objs...), fakeLogsReader{})
+		Expect(first).To(Equal(second))
+	})
+
+	It("should collect the recent events of the HCO CR, the HCO pods and the CSVs", func() {
+		cl := newClient(
+			hco,
+			newPod("hco-operator-1", "hyperconverged-cluster-operator"),
+			newPod("other-pod", "other"),
+			&csvv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: "hco-csv", Namespace: testNamespace}},
+			newEvent("pod-event", "Pod", "hco-operator-1", 3),
+			newEvent("hco-event", "HyperConverged", hco.Name, 2),
+			newEvent("csv-event", "ClusterServiceVersion", "hco-csv", 1),
+			newEvent("other-pod-event", "Pod", "other-pod", 4),
+		)
+
+		_, contents := readArchive(collect(cl, fakeLogsReader{}))
+
+		var events []corev1.Event
+		Expect(yaml.Unmarshal([]byte(contents["hco-diagnostics/events.yaml"]), &events)).To(Succeed())
+		Expect(events).To(HaveLen(3))
+		Expect(events[0].Name).To(Equal("csv-event"))
+		Expect(events[1].Name).To(Equal("hco-event"))
+		Expect(events[2].Name).To(Equal("pod-event"))
+	})
+
+	It("should keep only the most recent events", func() {
+		objs := []runtime.Object{hco}
+		for i := 0; i < MaxEvents+10; i++ {
+			event := newEvent(fmt.Sprintf("event-%03d", i), "HyperConverged", hco.Name, 0)
+			event.LastTimestamp = metav1.NewTime(event.LastTimestamp.Add(time.Duration(i) * time.Second))
+			objs = append(objs, event)
+		}
+
+		_, contents := readArchive(collect(newClient(objs...), fakeLogsReader{}))
+
+		var events []corev1.Event
+		Expect(yaml.Unmarshal([]byte(contents["hco-diagnostics/events.yaml"]), &events)).To(Succeed())
+		Expect(events).To(HaveLen(MaxEvents))
+		Expect(events[0].Name).To(Equal("event-010"))
+		Expect(events[MaxEvents-1].Name).To(Equal(fmt.Sprintf("event-%03d", MaxEvents+9)))
+	})
+
+	It("should list the collection errors, and collect the rest", func() {
+		cl := newClient(
+			hco, cm, // CDI and the secret are missing
+			newPod("hco-operator-1", "hyperconverged-cluster-operator", "hyperconverged-cluster-operator"),
+			newPod("hco-webhook-1", "hyperconverged-cluster-webhook", "hyperconverged-cluster-webhook"),
+		)
+
+		paths, contents := readArchive(collect(cl, fakeLogsReader{failFor: "hco-webhook-1"}))
+		Expect(paths).To(ContainElement("hco-diagnostics/related-objects/core/configmap/kubevirt-hyperconverged/kubevirt-config.yaml"))
+		Expect(paths).To(ContainElement("hco-diagnostics/logs/hco-operator-1/hyperconverged-cluster-operator.log"))
+		Expect(paths).ToNot(ContainElement("hco-diagnostics/logs/hco-webhook-1/hyperconverged-cluster-webhook.log"))
+
+		errorsFile := contents["hco-diagnostics/errors.txt"]
+		Expect(errorsFile).To(ContainSubstring("can't read the related object CDI /cdi-kubevirt-hyperconverged"))
+		Expect(errorsFile).To(ContainSubstring("can't read the related object Secret kubevirt-hyperconverged/some-secret"))
+		Expect(errorsFile).To(ContainSubstring("can't read the logs of the hyperconverged-cluster-webhook container of the hco-webhook-1 pod: fake error"))
+	})
+
+	It("should collect the pods if the HyperConverged CR is missing", func() {
+		cl := newClient(newPod("hco-operator-1", "hyperconverged-cluster-operator", "hyperconverged-cluster-operator"))
+
+		paths, contents := readArchive(collect(cl, nil))
+		Expect(paths).To(Equal([]string{
+			"hco-diagnostics/errors.txt",
+			"hco-diagnostics/events.yaml",
+			"hco-diagnostics/pods/hco-operator-1.yaml",
+		}))
+		Expect(contents["hco-diagnostics/errors.txt"]).To(ContainSubstring("can't read the HyperConverged CR kubevirt-hyperconverged/kubevirt-hyperconverged"))
+	})
+})
@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MaxLogLines is the maximal number of log lines that are read from each container
+const MaxLogLines int64 = 10000
+
+type clientsetLogsReader struct {
+	clientset kubernetes.Interface
+}
+
+// NewPodLogsReader creates a PodLogsReader that reads the last MaxLogLines lines of the logs by the Kubernetes API
+func NewPodLogsReader(clientset kubernetes.Interface) PodLogsReader {
+	return &clientsetLogsReader{clientset: clientset}
+}
+
+func (r clientsetLogsReader) GetLogs(ctx context.Context, namespace, pod, container string) ([]byte, error) {
+	tailLines := MaxLogLines
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}
+
+	return r.clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).DoRaw(ctx)
+}
@@ -2,26 +2,84 @@ package util
 
 import (
 	"context"
-	"strings"
+	"sync"
 
 	"github.com/go-logr/logr"
 	openshiftconfigv1 "github.com/openshift/api/config/v1"
-	secv1 "github.com/openshift/api/security/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Capability is an optional API of the cluster, that some of the HCO operands depend on
+type Capability string
+
+const (
+	// CapabilityMonitoring is the prometheus-operator API; ServiceMonitor and PrometheusRule
+	CapabilityMonitoring Capability = "Monitoring"
+	// CapabilityConsole is the OpenShift console API; ConsoleQuickStart and ConsoleCLIDownload
+	CapabilityConsole Capability = "Console"
+	// CapabilityOLM is the OLM ClusterServiceVersion API
+	CapabilityOLM Capability = "OLM"
+	// CapabilityClusterVersion is the OpenShift ClusterVersion object; it exists only on OpenShift clusters
+	CapabilityClusterVersion Capability = "ClusterVersion"
+)
+
+// capabilityKinds are the kinds that must be served by the cluster, for each capability
+var capabilityKinds = map[Capability][]schema.GroupVersionKind{
+	CapabilityMonitoring: {
+		{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"},
+		{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"},
+	},
+	CapabilityConsole: {
+		{Group: "console.openshift.io", Version: "v1", Kind: "ConsoleQuickStart"},
+		{Group: "console.openshift.io", Version: "v1", Kind: "ConsoleCLIDownload"},
+	},
+	CapabilityOLM: {
+		{Group: "operators.coreos.com", Version: "v1alpha1", Kind: "ClusterServiceVersion"},
+	},
+	CapabilityClusterVersion: {
+		openshiftconfigv1.GroupVersion.WithKind("ClusterVersion"),
+	},
+}
+
+// Capabilities is the list of all the known capabilities, in a stable order
+var Capabilities = []Capability{
+	CapabilityMonitoring,
+	CapabilityConsole,
+	CapabilityOLM,
+	CapabilityClusterVersion,
+}
+
+// IsCapabilityGroup returns true if the API group provides any of the capabilities. Adding or removing a CRD of such
+// a group may change the capabilities of the cluster.
+func IsCapabilityGroup(group string) bool {
+	for _, kinds := range capabilityKinds {
+		for _, gvk := range kinds {
+			if gvk.Group == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type ClusterInfo interface {
-	CheckRunningInOpenshift(creader client.Reader, ctx context.Context, logger logr.Logger, runningLocally bool) error
+	Init(ctx context.Context, creader client.Reader, mapper meta.RESTMapper, logger logr.Logger, runningLocally bool) error
+	RefreshCapabilities(ctx context.Context, logger logr.Logger) (bool, error)
+	HasCapability(capability Capability) bool
 	IsOpenshift() bool
 	IsRunningLocally() bool
 }
 
 type ClusterInfoImp struct {
-	runningInOpenshift bool
-	runningLocally     bool
+	lock           sync.RWMutex
+	creader        client.Reader
+	mapper         meta.RESTMapper
+	capabilities   map[Capability]bool
+	runningLocally bool
 }
 
 var clusterInfo ClusterInfo
@@ -30,32 +88,21 @@ func GetClusterInfo() ClusterInfo {
 	return clusterInfo
 }
 
-func (c *ClusterInfoImp) CheckRunningInOpenshift(creader client.Reader, ctx context.Context, logger logr.Logger, runningLocally bool) error {
+// Init detects the capabilities of the cluster. The reader and the REST mapper are kept, to detect the capabilities
+// again by RefreshCapabilities, when CRDs are added or removed.
+func (c *ClusterInfoImp) Init(ctx context.Context, creader client.Reader, mapper meta.RESTMapper, logger logr.Logger, runningLocally bool) error {
+	c.lock.Lock()
+	c.creader = creader
+	c.mapper = mapper
 	c.runningLocally = runningLocally
-	isOpenShift := false
-	version := ""
+	c.lock.Unlock()
 
-	clusterVersion := &openshiftconfigv1.ClusterVersion{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "version",
-		},
-	}
-	if err := creader.Get(ctx, client.ObjectKeyFromObject(clusterVersion), clusterVersion); err != nil {
-		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
-			// Not on OpenShift
-			isOpenShift = false
-		} else {
-			logger.Error(err, "Failed to get ClusterVersion")
-			return err
-		}
-	} else {
-		isOpenShift = true
-		version = clusterVersion.Status.Desired.Version
+	if _, err := c.RefreshCapabilities(ctx, logger); err != nil {
+		return err
 	}
 
-	c.runningInOpenshift = isOpenShift
-	if isOpenShift {
-		logger.Info("Cluster type = openshift", "version", version)
+	if c.IsOpenshift() {
+		logger.Info("Cluster type = openshift")
 	} else {
 		logger.Info("Cluster type = kubernetes")
 	}
@@ -63,30 +110,97 @@ func (c *ClusterInfoImp) CheckRunningInOpenshift(creader client.Reader, ctx cont
 	return nil
 }
 
-func (c ClusterInfoImp) IsOpenshift() bool {
-	return c.runningInOpenshift
+// RefreshCapabilities detects the capabilities of the cluster, and returns true if they were changed
+func (c *ClusterInfoImp) RefreshCapabilities(ctx context.Context, logger logr.Logger) (bool, error) {
+	c.lock.RLock()
+	creader, mapper := c.creader, c.mapper
+	c.lock.RUnlock()
+
+	capabilities := make(map[Capability]bool, len(Capabilities))
+	for _, capability := range Capabilities {
+		found, err := hasKinds(mapper, capabilityKinds[capability])
+		if err != nil {
+			logger.Error(err, "Failed to detect the cluster capability", "capability", capability)
+			return false, err
+		}
+		capabilities[capability] = found
+	}
+
+	if capabilities[CapabilityClusterVersion] {
+		found, err := getClusterVersion(ctx, creader, logger)
+		if err != nil {
+			return false, err
+		}
+		capabilities[CapabilityClusterVersion] = found
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	changed := false
+	for _, capability := range Capabilities {
+		if c.capabilities[capability] != capabilities[capability] {
+			logger.Info("Detected a change of a cluster capability", "capability", capability, "available", capabilities[capability])
+			changed = true
+		}
+	}
+	c.capabilities = capabilities
+
+	return changed, nil
+}
+
+func (c *ClusterInfoImp) HasCapability(capability Capability) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.capabilities[capability]
+}
+
+// IsOpenshift returns true if the cluster has the OpenShift ClusterVersion object
+func (c *ClusterInfoImp) IsOpenshift() bool {
+	return c.HasCapability(CapabilityClusterVersion)
 }
 
-func (c ClusterInfoImp) IsRunningLocally() bool {
+func (c *ClusterInfoImp) IsRunningLocally() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	return c.runningLocally
 }
 
-func (c ClusterInfoImp) findApi(apis []*metav1.APIResourceList, resourceName string) bool {
-	for _, api := range apis {
-		if api.GroupVersion == secv1.GroupVersion.String() {
-			for _, resource := range api.APIResources {
-				if strings.ToLower(resource.Name) == resourceName {
-					return true
-				}
+func hasKinds(mapper meta.RESTMapper, kinds []schema.GroupVersionKind) (bool, error) {
+	for _, gvk := range kinds {
+		if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				return false, nil
 			}
+			return false, err
 		}
 	}
 
-	return false
+	return true, nil
+}
+
+func getClusterVersion(ctx context.Context, creader client.Reader, logger logr.Logger) (bool, error) {
+	clusterVersion := &openshiftconfigv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "version",
+		},
+	}
+	if err := creader.Get(ctx, client.ObjectKeyFromObject(clusterVersion), clusterVersion); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		logger.Error(err, "Failed to get ClusterVersion")
+		return false, err
+	}
+
+	logger.Info("Found the ClusterVersion object", "version", clusterVersion.Status.Desired.Version)
+	return true, nil
 }
 
 func init() {
 	clusterInfo = &ClusterInfoImp{
-		runningInOpenshift: false,
+		capabilities: make(map[Capability]bool),
 	}
 }
@@ -8,6 +8,7 @@ const (
 	ContainerOperatorApp   = "OPERATOR"
 	ContainerWebhookApp    = "WEBHOOK"
 	HcoKvIoVersionName     = "HCO_KV_IO_VERSION"
+	HcoVersionName         = "operator"
 	KubevirtVersionEnvV    = "KUBEVIRT_VERSION"
 	CdiVersionEnvV         = "CDI_VERSION"
 	CnaoVersionEnvV        = "NETWORK_ADDONS_VERSION"
@@ -17,12 +18,16 @@ const (
 	VMImportEnvV           = "VM_IMPORT_VERSION"
 	HcoValidatingWebhook   = "validate-hco.kubevirt.io"
 	HcoMutatingWebhookNS   = "mutate-ns-hco.kubevirt.io"
+	HcoMutatingWebhook     = "mutate-hco.kubevirt.io"
+	HcoConversionWebhook   = "convert-hco.kubevirt.io"
+	HcoOperandWebhook      = "validate-operands-hco.kubevirt.io"
 	AppLabel               = "app"
 	UndefinedNamespace     = ""
 	OpenshiftNamespace     = "openshift"
 	OperatorTestNamespace  = "test-operators"
 	APIVersionAlpha        = "v1alpha1"
 	APIVersionBeta         = "v1beta1"
+	APIVersionV1           = "v1"
 	CurrentAPIVersion      = APIVersionBeta
 	APIVersionGroup        = "hco.kubevirt.io"
 	APIVersion             = APIVersionGroup + "/" + APIVersionBeta
@@ -35,21 +40,34 @@ const (
 	AppLabelComponent = AppLabelPrefix + "/component"
 	// Operator name for managed-by label
 	OperatorName = "hco-operator"
+	// HCOServiceAccountName is the name of the service account of the HCO operator and webhook
+	HCOServiceAccountName = "hyperconverged-cluster-operator"
 	// Value for "part-of" label
 	HyperConvergedCluster = "hyperconverged-cluster"
+	// AuditConfigMapName is the name of the ConfigMap in the HCO namespace, that keeps the latest changes of the
+	// HyperConverged CR
+	AuditConfigMapName = "hyperconverged-cluster-audit"
 
 	// HyperConvergedName is the name of the HyperConverged resource that will be reconciled
-	HyperConvergedName          = "kubevirt-hyperconverged"
-	MetricsHost                 = "0.0.0.0"
-	MetricsPort           int32 = 8383
-	OperatorMetricsPort   int32 = 8686
-	HealthProbeHost             = "0.0.0.0"
-	HealthProbePort       int32 = 6060
-	ReadinessEndpointName       = "/readyz"
-	LivenessEndpointName        = "/livez"
-	HCOWebhookPath              = "/validate-hco-kubevirt-io-v1beta1-hyperconverged"
-	HCONSWebhookPath            = "/mutate-ns-hco-kubevirt-io"
-	WebhookPort                 = 4343
+	HyperConvergedName           = "kubevirt-hyperconverged"
+	MetricsHost                  = "0.0.0.0"
+	MetricsPort            int32 = 8383
+	OperatorMetricsPort    int32 = 8686
+	HealthProbeHost              = "0.0.0.0"
+	HealthProbePort        int32 = 6060
+	ReadinessEndpointName        = "/readyz"
+	LivenessEndpointName         = "/livez"
+	HCOWebhookPath               = "/validate-hco-kubevirt-io-v1beta1-hyperconverged"
+	HCONSWebhookPath             = "/mutate-ns-hco-kubevirt-io"
+	HCOMutatingWebhookPath       = "/mutate-hco-kubevirt-io-v1beta1-hyperconverged"
+	HCOConvertWebhookPath        = "/convert"
+	HCOOperandWebhookPath        = "/validate-operands-hco-kubevirt-io"
+	WebhookPort                  = 4343
+	// WebhookTimeoutSeconds is the timeout of the HCO webhooks
+	WebhookTimeoutSeconds int32 = 30
+	// OperandWebhookTimeoutSeconds is the timeout of the operand guard webhook. The webhook fails open, so it must not
+	// hold the updates of the operand CRs for long when the HCO webhook Pod is not available.
+	OperandWebhookTimeoutSeconds int32 = 5
 )
 
 type AppComponent string
@@ -63,3 +81,13 @@ const (
 	AppComponentSchedule                = "schedule"
 	AppComponentDeployment              = "deployment"
 )
+
+// OperandOperatorServiceAccountNames are the names of the service accounts of the operand operators, as set by the
+// operand CSVs
+var OperandOperatorServiceAccountNames = []string{
+	"kubevirt-operator",
+	"cdi-operator",
+	"cluster-network-addons-operator",
+	"ssp-operator",
+	"vm-import-operator",
+}
@@ -64,7 +64,7 @@ func (ee *eventEmitter) UpdateClient(ctx context.Context, clnt client.Reader, lo
 		}
 	}
 
-	if (ee.csv == nil) && clusterInfo.IsOpenshift() {
+	if (ee.csv == nil) && ee.clusterInfo.HasCapability(CapabilityOLM) {
 		var err error
 		ee.csv, err = GetCSVfromPod(ee.pod, clnt, logger)
 		if err != nil {
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ReadManifests reads the resources of all the documents of a YAML or JSON stream. Empty documents are skipped.
+func ReadManifests(reader io.Reader) ([]*unstructured.Unstructured, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []*unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		raw := json.RawMessage{}
+		if err = decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return manifests, nil
+			}
+			return nil, err
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+			continue
+		}
+
+		manifest := &unstructured.Unstructured{}
+		if err = manifest.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeMaintenanceGroupVersionKind is the GVK of the NodeMaintenance CR of the node maintenance operator. The node
+// maintenance API is not vendored, so the NodeMaintenance CRs are handled as unstructured objects.
+var NodeMaintenanceGroupVersionKind = schema.GroupVersionKind{
+	Group:   "nodemaintenance.kubevirt.io",
+	Version: "v1beta1",
+	Kind:    "NodeMaintenance",
+}
+
+const (
+	// NodeMaintenanceRunning is the phase of a NodeMaintenance while its node is being drained
+	NodeMaintenanceRunning = "Running"
+
+	virtLauncherPodPrefix = "virt-launcher-"
+)
+
+// NodeMaintenance is the part of a NodeMaintenance CR that is used by HCO
+type NodeMaintenance struct {
+	Name     string
+	NodeName string
+	Reason   string
+	Phase    string
+	// the pods that were not evicted from the node yet
+	PendingPods []string
+}
+
+// PendingVMs returns the number of virtual machines that were not migrated or shut down yet, by counting the pending
+// virt-launcher pods.
+func (nm NodeMaintenance) PendingVMs() int {
+	count := 0
+	for _, pod := range nm.PendingPods {
+		if strings.HasPrefix(pod, virtLauncherPodPrefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// NewNodeMaintenanceWithGVKOnly returns an empty NodeMaintenance CR, to be used for watches and reads
+func NewNodeMaintenanceWithGVKOnly() *unstructured.Unstructured {
+	nm := &unstructured.Unstructured{}
+	nm.SetGroupVersionKind(NodeMaintenanceGroupVersionKind)
+	return nm
+}
+
+// GetNodeMaintenances returns the NodeMaintenance CRs in the cluster, sorted by node name. If the node maintenance
+// operator is not deployed, no NodeMaintenance is returned.
+func GetNodeMaintenances(ctx context.Context, c client.Reader) ([]NodeMaintenance, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(NodeMaintenanceGroupVersionKind.GroupVersion().WithKind(NodeMaintenanceGroupVersionKind.Kind + "List"))
+
+	if err := c.List(ctx, list); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := make([]NodeMaintenance, 0, len(list.Items))
+	for _, item := range list.Items {
+		nm := NodeMaintenance{Name: item.GetName()}
+		nm.NodeName, _, _ = unstructured.NestedString(item.Object, "spec", "nodeName")
+		nm.Reason, _, _ = unstructured.NestedString(item.Object, "spec", "reason")
+		nm.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+		nm.PendingPods, _, _ = unstructured.NestedStringSlice(item.Object, "status", "pendingPods")
+		res = append(res, nm)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].NodeName != res[j].NodeName {
+			return res[i].NodeName < res[j].NodeName
+		}
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+// GetNodesUnderMaintenance returns the set of the names of the nodes that have a NodeMaintenance CR. These nodes are
+// cordoned, or about to be cordoned, so workloads can't be scheduled on them.
+func GetNodesUnderMaintenance(ctx context.Context, c client.Reader) (map[string]bool, error) {
+	nms, err := GetNodeMaintenances(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]bool, len(nms))
+	for _, nm := range nms {
+		nodes[nm.NodeName] = true
+	}
+	return nodes, nil
+}
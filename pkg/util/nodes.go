@@ -0,0 +1,227 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CPUModelLabelPrefix is the prefix of the node labels that are written by the node labeller, one for each CPU model
+// that is supported by the node
+const CPUModelLabelPrefix = "cpu-model.node.kubevirt.io/"
+
+// GetPlacementNodes returns the nodes that match the node selector, the required node affinity and the tolerations
+// of the node placement, sorted by name. A nil placement matches all the schedulable nodes.
+func GetPlacementNodes(ctx context.Context, c client.Reader, placement *sdkapi.NodePlacement) ([]corev1.Node, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+
+	var res []corev1.Node
+	for _, node := range nodes.Items {
+		if NodeMatchesPlacement(&node, placement) {
+			res = append(res, node)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+// GetEligibleNodes returns the nodes that match the node placement, and that are not under maintenance by the node
+// maintenance operator, sorted by name
+func GetEligibleNodes(ctx context.Context, c client.Reader, placement *sdkapi.NodePlacement) ([]corev1.Node, error) {
+	nodes, err := GetPlacementNodes(ctx, c, placement)
+	if err != nil {
+		return nil, err
+	}
+
+	underMaintenance, err := GetNodesUnderMaintenance(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !underMaintenance[node.Name] {
+			eligible = append(eligible, node)
+		}
+	}
+	return eligible, nil
+}
+
+// NodeMatchesPlacement returns true if the pods with the node placement can be scheduled on the node. Only the node
+// selector, the required node affinity and the NoSchedule and NoExecute taints are checked.
+func NodeMatchesPlacement(node *corev1.Node, placement *sdkapi.NodePlacement) bool {
+	var tolerations []corev1.Toleration
+	if placement != nil {
+		if !labels.SelectorFromSet(placement.NodeSelector).Matches(labels.Set(node.Labels)) {
+			return false
+		}
+
+		if placement.Affinity != nil && placement.Affinity.NodeAffinity != nil &&
+			!matchNodeSelector(node, placement.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution) {
+			return false
+		}
+
+		tolerations = placement.Tolerations
+	}
+
+	return !node.Spec.Unschedulable && toleratesTaints(node.Spec.Taints, tolerations)
+}
+
+// matchNodeSelector returns true if the node matches any of the terms of the node selector. A nil node selector
+// matches all the nodes.
+func matchNodeSelector(node *corev1.Node, nodeSelector *corev1.NodeSelector) bool {
+	if nodeSelector == nil {
+		return true
+	}
+
+	for _, term := range nodeSelector.NodeSelectorTerms {
+		if matchNodeSelectorTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNodeSelectorTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		// an empty term matches no objects
+		return false
+	}
+
+	for _, req := range term.MatchExpressions {
+		if !matchNodeSelectorRequirement(req, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+
+	for _, req := range term.MatchFields {
+		// metadata.name is the only supported field
+		if req.Key != "metadata.name" || !matchNodeSelectorRequirement(req, labels.Set{req.Key: node.Name}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchNodeSelectorRequirement(req corev1.NodeSelectorRequirement, values labels.Set) bool {
+	switch req.Operator {
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if len(req.Values) != 1 || !values.Has(req.Key) {
+			return false
+		}
+		required, err := strconv.ParseInt(req.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		actual, err := strconv.ParseInt(values.Get(req.Key), 10, 64)
+		if err != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return actual > required
+		}
+		return actual < required
+	}
+
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	}
+
+	op, ok := operators[req.Operator]
+	if !ok {
+		return false
+	}
+
+	r, err := labels.NewRequirement(req.Key, op, req.Values)
+	if err != nil {
+		return false
+	}
+
+	return r.Matches(values)
+}
+
+// toleratesTaints returns true if the NoSchedule and NoExecute taints of the node are tolerated
+func toleratesTaints(taints []corev1.Taint, tolerations []corev1.Toleration) bool {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// GetNodeCPUModels returns the CPU models that are supported by the node, by the node labeller labels, sorted by
+// name. If the node was not labelled yet, no CPU model is returned.
+func GetNodeCPUModels(node *corev1.Node) []string {
+	var models []string
+	for label, value := range node.Labels {
+		if model := strings.TrimPrefix(label, CPUModelLabelPrefix); model != label && value == "true" {
+			models = append(models, model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// GetDistinctCPUModels groups the nodes by their supported CPU models. The key of the returned map is the comma
+// separated list of the CPU models, and its value is the names of the nodes that support exactly these models. The
+// nodes that were not labelled yet are ignored. The nodes are homogeneous if the map has at most one key.
+func GetDistinctCPUModels(nodes []corev1.Node) map[string][]string {
+	res := make(map[string][]string)
+	for i := range nodes {
+		models := GetNodeCPUModels(&nodes[i])
+		if len(models) == 0 {
+			continue
+		}
+		key := strings.Join(models, ",")
+		res[key] = append(res[key], nodes[i].Name)
+	}
+	return res
+}
+
+// FormatDistinctCPUModels formats the result of GetDistinctCPUModels for a message, in a stable order
+func FormatDistinctCPUModels(models map[string][]string) string {
+	keys := make([]string, 0, len(models))
+	for key := range models {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	groups := make([]string, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, fmt.Sprintf("[%s] on nodes %s", key, strings.Join(models[key], ", ")))
+	}
+	return strings.Join(groups, "; ")
+}
@@ -0,0 +1,261 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+const (
+	// AuditConfigMapKey is the key of the audit ConfigMap, that holds a JSON list of the changes, oldest first
+	AuditConfigMapKey = "changes"
+	// AuditEventReason is the reason of the event that is emitted on the HyperConverged CR for each change
+	AuditEventReason = "HyperConvergedChanged"
+	// AuditStatusAdmitted is the status of the audit entries. The webhook records the updates when it admits them, before
+	// they are persisted, so an admitted update may still be rejected by a later admission step.
+	AuditStatusAdmitted = "admitted"
+
+	// the audit ConfigMap keeps only the latest changes, to stay far below the size limit of a ConfigMap
+	maxAuditEntries = 100
+	// the event message names only the first changed paths; the audit ConfigMap contains all of them
+	maxAuditEventPaths = 10
+	// the entries that are waiting to be written to the audit ConfigMap; above it, new entries are dropped
+	auditQueueSize = maxAuditEntries
+	// the audit ConfigMap may be updated concurrently by the other webhook replicas
+	auditUpdateRetries = 3
+	auditTimeOut       = time.Second * 3
+)
+
+// AuditEntry is one change of the HyperConverged CR, in the audit ConfigMap
+type AuditEntry struct {
+	Time     metav1.Time `json:"time"`
+	Status   string      `json:"status"`
+	User     string      `json:"user"`
+	Groups   []string    `json:"groups,omitempty"`
+	Paths    []string    `json:"paths"`
+	Revision string      `json:"revision,omitempty"`
+}
+
+// AuditUpdate records an admitted update of the HyperConverged CR: it emits an event on the CR, with the user and the
+// changed paths, and queues the change to be appended to the audit ConfigMap. It does not wait for the ConfigMap to be
+// updated; failures are only logged, and they never block the update.
+func (wh WebhookHandler) AuditUpdate(requested, exists *v1beta1.HyperConverged, userInfo authenticationv1.UserInfo) {
+	paths, err := getChangedPaths(requested, exists)
+	if err != nil {
+		wh.logger.Error(err, "can't compare the HyperConverged CRs; the change is not audited", "user", userInfo.Username)
+		return
+	}
+
+	// e.g. a change of the labels or of the finalizers
+	if len(paths) == 0 {
+		return
+	}
+
+	wh.logger.Info("Admitted a change of the HyperConverged CR", "user", userInfo.Username, "paths", paths)
+	wh.eventEmitter.EmitEvent(requested, corev1.EventTypeNormal, AuditEventReason, getAuditEventMessage(userInfo.Username, paths))
+
+	wh.auditWriter.add(AuditEntry{
+		Time:     metav1.Now(),
+		Status:   AuditStatusAdmitted,
+		User:     userInfo.Username,
+		Groups:   userInfo.Groups,
+		Paths:    paths,
+		Revision: exists.ResourceVersion,
+	})
+}
+
+func getAuditEventMessage(user string, paths []string) string {
+	if len(paths) > maxAuditEventPaths {
+		return fmt.Sprintf("Admitted a change by %s of %s and %d more; see the %s ConfigMap",
+			user, strings.Join(paths[:maxAuditEventPaths], ", "), len(paths)-maxAuditEventPaths, hcoutil.AuditConfigMapName)
+	}
+
+	return fmt.Sprintf("Admitted a change by %s of %s", user, strings.Join(paths, ", "))
+}
+
+// auditWriter appends the audit entries to the audit ConfigMap in the background, so the admission requests don't wait
+// for the API server
+type auditWriter struct {
+	logger    logr.Logger
+	cli       client.Client
+	namespace string
+	entries   chan AuditEntry
+	start     sync.Once
+}
+
+func newAuditWriter(logger logr.Logger, cli client.Client, namespace string) *auditWriter {
+	return &auditWriter{
+		logger:    logger,
+		cli:       cli,
+		namespace: namespace,
+		entries:   make(chan AuditEntry, auditQueueSize),
+	}
+}
+
+// add queues the entry, and starts the writer on the first entry
+func (w *auditWriter) add(entry AuditEntry) {
+	w.start.Do(func() {
+		go w.run()
+	})
+
+	select {
+	case w.entries <- entry:
+	default:
+		w.logger.Info("too many pending audit entries; dropping the entry", "user", entry.User, "paths", entry.Paths)
+	}
+}
+
+// run writes the queued entries; the entries that were queued while the ConfigMap was updated are written together
+func (w *auditWriter) run() {
+	for entry := range w.entries {
+		batch := []AuditEntry{entry}
+		for pending := len(w.entries); pending > 0; pending-- {
+			batch = append(batch, <-w.entries)
+		}
+
+		w.write(batch)
+	}
+}
+
+func (w *auditWriter) write(batch []AuditEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), auditTimeOut)
+	defer cancel()
+
+	var err error
+	for i := 0; i < auditUpdateRetries; i++ {
+		err = w.appendAuditEntries(ctx, batch)
+		if !apierrors.IsConflict(err) && !apierrors.IsAlreadyExists(err) {
+			break
+		}
+	}
+
+	if err != nil {
+		w.logger.Error(err, "failed to update the audit ConfigMap", "name", hcoutil.AuditConfigMapName, "entries", len(batch))
+	}
+}
+
+// appendAuditEntries adds the changes to the audit ConfigMap, and drops the oldest changes above maxAuditEntries
+func (w *auditWriter) appendAuditEntries(ctx context.Context, batch []AuditEntry) error {
+	cm := &corev1.ConfigMap{}
+	err := w.cli.Get(ctx, client.ObjectKey{Namespace: w.namespace, Name: hcoutil.AuditConfigMapName}, cm)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		cm = newAuditConfigMap(w.namespace)
+	}
+
+	var entries []AuditEntry
+	if content, found := cm.Data[AuditConfigMapKey]; found && content != "" {
+		if err := json.Unmarshal([]byte(content), &entries); err != nil {
+			// don't lose the new changes because the ConfigMap was corrupted; start a new list instead
+			w.logger.Error(err, "can't read the audit ConfigMap; dropping its content", "name", hcoutil.AuditConfigMapName)
+			entries = nil
+		}
+	}
+
+	entries = append(entries, batch...)
+	if len(entries) > maxAuditEntries {
+		entries = entries[len(entries)-maxAuditEntries:]
+	}
+
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[AuditConfigMapKey] = string(content)
+
+	if cm.ResourceVersion == "" {
+		return w.cli.Create(ctx, cm)
+	}
+	return w.cli.Update(ctx, cm)
+}
+
+func newAuditConfigMap(namespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hcoutil.AuditConfigMapName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				hcoutil.AppLabel: hcoutil.HyperConvergedName,
+			},
+		},
+	}
+}
+
+// getChangedPaths returns the sorted paths of the spec fields and of the annotations that differ between the CRs,
+// e.g. "spec.infra.nodePlacement" or "metadata.annotations[kubevirt.kubevirt.io/jsonpatch]". A field that was added
+// or removed is reported once, and not by its nested fields.
+func getChangedPaths(requested, exists *v1beta1.HyperConverged) ([]string, error) {
+	requestedSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&requested.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	existsSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&exists.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := appendChangedPaths(nil, "spec", existsSpec, requestedSpec)
+
+	for key, value := range requested.Annotations {
+		if existing, found := exists.Annotations[key]; !found || existing != value {
+			paths = append(paths, fmt.Sprintf("metadata.annotations[%s]", key))
+		}
+	}
+
+	for key := range exists.Annotations {
+		if _, found := requested.Annotations[key]; !found {
+			paths = append(paths, fmt.Sprintf("metadata.annotations[%s]", key))
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func appendChangedPaths(paths []string, path string, exists, requested interface{}) []string {
+	existsMap, existsIsMap := exists.(map[string]interface{})
+	requestedMap, requestedIsMap := requested.(map[string]interface{})
+
+	if !existsIsMap || !requestedIsMap {
+		if !reflect.DeepEqual(exists, requested) {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	for key, value := range requestedMap {
+		paths = appendChangedPaths(paths, path+"."+key, existsMap[key], value)
+	}
+
+	for key := range existsMap {
+		if _, found := requestedMap[key]; !found {
+			paths = append(paths, path+"."+key)
+		}
+	}
+
+	return paths
+}
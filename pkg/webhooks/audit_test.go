@@ -0,0 +1,244 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+var _ = Describe("audit trail", func() {
+	const adminUser = "kube:admin"
+
+	var (
+		exists       *v1beta1.HyperConverged
+		requested    *v1beta1.HyperConverged
+		cli          *commonTestUtils.HcoTestClient
+		wh           *WebhookHandler
+		eventEmitter *commonTestUtils.EventEmitterMock
+		userInfo     authenticationv1.UserInfo
+	)
+
+	// returns nil until the audit ConfigMap is created
+	getAuditEntries := func() []AuditEntry {
+		cm := &corev1.ConfigMap{}
+		err := cli.Get(context.TODO(), client.ObjectKey{Namespace: HcoValidNamespace, Name: hcoutil.AuditConfigMapName}, cm)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		Expect(err).ToNot(HaveOccurred())
+
+		var entries []AuditEntry
+		Expect(json.Unmarshal([]byte(cm.Data[AuditConfigMapKey]), &entries)).To(Succeed())
+		return entries
+	}
+
+	BeforeEach(func() {
+		exists = commonTestUtils.NewHco()
+		exists.ResourceVersion = "1"
+		requested = exists.DeepCopy()
+
+		cli = commonTestUtils.InitClient(nil)
+		eventEmitter = commonTestUtils.NewEventEmitterMock()
+
+		wh = &WebhookHandler{}
+		wh.Init(logger, cli, HcoValidNamespace, true)
+		wh.eventEmitter = eventEmitter
+
+		userInfo = authenticationv1.UserInfo{Username: adminUser, Groups: []string{"system:authenticated"}}
+	})
+
+	Context("getChangedPaths", func() {
+		It("should return the changed spec fields and annotations, sorted", func() {
+			disabled, enabled := false, true
+			exists.Spec.FeatureGates = &v1beta1.HyperConvergedFeatureGates{HotplugVolumes: &disabled}
+			requested = exists.DeepCopy()
+
+			requested.Spec.LocalStorageClassName = "local"
+			requested.Spec.Infra.NodePlacement = commonTestUtils.NewNodePlacement()
+			requested.Spec.FeatureGates.HotplugVolumes = &enabled
+			requested.Annotations = map[string]string{common.JSONPatchKVAnnotationName: "[]"}
+
+			paths, err := getChangedPaths(requested, exists)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paths).To(Equal([]string{
+				"metadata.annotations[" + common.JSONPatchKVAnnotationName + "]",
+				"spec.featureGates.hotplugVolumes",
+				"spec.infra.nodePlacement",
+				"spec.localStorageClassName",
+			}))
+		})
+
+		It("should return the removed fields and annotations", func() {
+			exists.Spec.LocalStorageClassName = "local"
+			exists.Annotations = map[string]string{"foo": "bar"}
+
+			paths, err := getChangedPaths(requested, exists)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paths).To(Equal([]string{"metadata.annotations[foo]", "spec.localStorageClassName"}))
+		})
+
+		It("should ignore the changes of the other metadata fields", func() {
+			requested.Labels = map[string]string{"foo": "bar"}
+			requested.Finalizers = []string{"foo"}
+
+			paths, err := getChangedPaths(requested, exists)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paths).To(BeEmpty())
+		})
+	})
+
+	Context("AuditUpdate", func() {
+		It("should emit an event and create the audit ConfigMap", func() {
+			before := metav1.Now().Rfc3339Copy()
+			requested.Spec.LocalStorageClassName = "local"
+			wh.AuditUpdate(requested, exists, userInfo)
+
+			Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+				EventType: corev1.EventTypeNormal,
+				Reason:    AuditEventReason,
+				Msg:       "Admitted a change by " + adminUser + " of spec.localStorageClassName",
+			}})).To(BeTrue())
+
+			Eventually(getAuditEntries).Should(HaveLen(1))
+			entries := getAuditEntries()
+			Expect(entries[0].Status).To(Equal(AuditStatusAdmitted))
+			Expect(entries[0].User).To(Equal(adminUser))
+			Expect(entries[0].Groups).To(Equal(userInfo.Groups))
+			Expect(entries[0].Paths).To(Equal([]string{"spec.localStorageClassName"}))
+			Expect(entries[0].Revision).To(Equal("1"))
+			Expect(entries[0].Time.Before(&before)).To(BeFalse())
+		})
+
+		It("should write all the queued changes", func() {
+			for i := 0; i < 10; i++ {
+				requested.Spec.LocalStorageClassName = fmt.Sprintf("local-%d", i)
+				wh.AuditUpdate(requested, exists, authenticationv1.UserInfo{Username: fmt.Sprintf("user-%d", i)})
+			}
+
+			Eventually(getAuditEntries).Should(HaveLen(10))
+			entries := getAuditEntries()
+			for i, entry := range entries {
+				Expect(entry.User).To(Equal(fmt.Sprintf("user-%d", i)))
+			}
+		})
+
+		It("should not audit an update that does not change the spec or the annotations", func() {
+			requested.Labels = map[string]string{"foo": "bar"}
+			wh.AuditUpdate(requested, exists, userInfo)
+
+			Consistently(func() bool {
+				err := cli.Get(context.TODO(), client.ObjectKey{Namespace: HcoValidNamespace, Name: hcoutil.AuditConfigMapName}, &corev1.ConfigMap{})
+				return apierrors.IsNotFound(err)
+			}, "100ms").Should(BeTrue())
+		})
+
+		It("should only name the first paths in the event", func() {
+			paths := make([]string, maxAuditEventPaths+2)
+			for i := range paths {
+				paths[i] = fmt.Sprintf("spec.field%02d", i)
+			}
+
+			msg := getAuditEventMessage(adminUser, paths)
+			Expect(msg).To(HaveSuffix("spec.field09 and 2 more; see the " + hcoutil.AuditConfigMapName + " ConfigMap"))
+		})
+
+		It("should not wait for the audit ConfigMap", func() {
+			blocked := make(chan struct{})
+			defer close(blocked)
+			cli.InitiateGetErrors(func(key client.ObjectKey) error {
+				if key.Name == hcoutil.AuditConfigMapName {
+					<-blocked
+				}
+				return nil
+			})
+
+			requested.Spec.LocalStorageClassName = "local"
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				wh.AuditUpdate(requested, exists, userInfo)
+			}()
+
+			Eventually(done).Should(BeClosed())
+			Expect(eventEmitter.CheckEvents([]commonTestUtils.MockEvent{{
+				EventType: corev1.EventTypeNormal,
+				Reason:    AuditEventReason,
+				Msg:       "Admitted a change by " + adminUser + " of spec.localStorageClassName",
+			}})).To(BeTrue())
+		})
+	})
+
+	Context("auditWriter", func() {
+		var writer *auditWriter
+
+		BeforeEach(func() {
+			writer = newAuditWriter(logger, cli, HcoValidNamespace)
+		})
+
+		newEntries := func(first, count int) []AuditEntry {
+			entries := make([]AuditEntry, count)
+			for i := range entries {
+				entries[i] = AuditEntry{Status: AuditStatusAdmitted, User: fmt.Sprintf("user-%d", first+i)}
+			}
+			return entries
+		}
+
+		It("should append to the existing audit ConfigMap, and keep only the latest changes", func() {
+			writer.write(newEntries(0, 10))
+			writer.write(newEntries(10, maxAuditEntries-5))
+
+			entries := getAuditEntries()
+			Expect(entries).To(HaveLen(maxAuditEntries))
+			Expect(entries[0].User).To(Equal("user-5"))
+			Expect(entries[maxAuditEntries-1].User).To(Equal(fmt.Sprintf("user-%d", maxAuditEntries+4)))
+		})
+
+		It("should replace a corrupted audit ConfigMap", func() {
+			cm := newAuditConfigMap(HcoValidNamespace)
+			cm.Data = map[string]string{AuditConfigMapKey: "not a json"}
+			Expect(cli.Create(context.TODO(), cm)).To(Succeed())
+
+			writer.write(newEntries(0, 1))
+
+			Expect(getAuditEntries()).To(HaveLen(1))
+		})
+
+		It("should retry on conflicts", func() {
+			conflicts := 0
+			cli.InitiateCreateErrors(func(obj client.Object) error {
+				if obj.GetName() == hcoutil.AuditConfigMapName && conflicts < auditUpdateRetries-1 {
+					conflicts++
+					return apierrors.NewAlreadyExists(corev1.Resource("configmaps"), obj.GetName())
+				}
+				return nil
+			})
+
+			writer.write(newEntries(0, 1))
+
+			Expect(getAuditEntries()).To(HaveLen(1))
+		})
+
+		It("should drop the entries above the queue size", func() {
+			// don't start the writer, so the queue is not drained
+			writer.start.Do(func() {})
+
+			for _, entry := range newEntries(0, auditQueueSize+5) {
+				writer.add(entry)
+			}
+			Expect(writer.entries).To(HaveLen(auditQueueSize))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// operandFields maps the kind of each operand CR to the HyperConverged spec fields that control its spec
+var operandFields = map[string]string{
+	"KubeVirt":            "spec.featureGates, spec.infra, spec.workloads",
+	"CDI":                 "spec.infra, spec.workloads",
+	"NetworkAddonsConfig": "spec.infra, spec.workloads",
+	"SSP":                 "spec.infra, spec.workloads",
+	"VMImportConfig":      "spec.infra",
+}
+
+// operandGuardAllowedServiceAccounts are the service accounts of the HCO namespace that may change the spec of the
+// operand CRs: HCO itself, and the operand operators that set the defaults of their own CRs
+var operandGuardAllowedServiceAccounts = append([]string{hcoutil.HCOServiceAccountName}, hcoutil.OperandOperatorServiceAccountNames...)
+
+// getOperandFields returns the HyperConverged fields and the patch annotations that control the spec of an operand CR
+func getOperandFields(kind string) string {
+	fields, found := operandFields[kind]
+	if !found {
+		fields = "spec"
+	}
+
+	annotations := operands.GetPatchAnnotationNamesOfKind(kind)
+	if len(annotations) == 0 {
+		return fields
+	}
+
+	return fmt.Sprintf("%s or one of the %s annotations", fields, strings.Join(annotations, ", "))
+}
+
+// ValidateOperandUpdate rejects a spec change of an operand CR that was not made by HCO, if the operand guard is
+// enabled by the HyperConverged CR. The service accounts of HCO and of the operand operators are allowed, because the
+// operand operators set the defaults of their own CRs. The break-glass annotation on the operand CR bypasses the check.
+func (wh WebhookHandler) ValidateOperandUpdate(requested, exists *unstructured.Unstructured, username string) error {
+	if reflect.DeepEqual(requested.Object["spec"], exists.Object["spec"]) {
+		return nil
+	}
+
+	if wh.isOperandGuardAllowedUser(username) {
+		return nil
+	}
+
+	kind := requested.GetKind()
+	if requested.GetAnnotations()[common.BreakGlassAnnotationName] == "true" {
+		wh.logger.Info("Allowing an out-of-band change of an operand CR, by the break-glass annotation",
+			"kind", kind, "name", requested.GetName(), "user", username)
+		return nil
+	}
+
+	enabled, err := wh.isOperandGuardEnabled(context.TODO())
+	if err != nil {
+		// the guard is optional; never block the operand CRs because the HyperConverged CR can't be read
+		wh.logger.Error(err, "failed to read the HyperConverged CR; allowing the operand CR change", "kind", kind, "name", requested.GetName())
+		return nil
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	return fmt.Errorf("the spec of the %s %s is managed by HCO, and changes that are not made by HCO are reverted; "+
+		"please edit %s of the HyperConverged %s/%s instead, or set the %s annotation of the %s to \"true\" to bypass this check",
+		kind, requested.GetName(), getOperandFields(kind), wh.namespace, hcoutil.HyperConvergedName, common.BreakGlassAnnotationName, kind)
+}
+
+func (wh WebhookHandler) isOperandGuardAllowedUser(username string) bool {
+	for _, sa := range operandGuardAllowedServiceAccounts {
+		if username == fmt.Sprintf("system:serviceaccount:%s:%s", wh.namespace, sa) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wh WebhookHandler) isOperandGuardEnabled(ctx context.Context) (bool, error) {
+	hc := &v1beta1.HyperConverged{}
+	err := wh.cli.Get(ctx, client.ObjectKey{Namespace: wh.namespace, Name: hcoutil.HyperConvergedName}, hc)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return hc.Annotations[common.OperandGuardAnnotationName] == "true", nil
+}
@@ -0,0 +1,177 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+var _ = Describe("operand guard", func() {
+	const (
+		adminUser    = "kube:admin"
+		operatorUser = "system:serviceaccount:" + HcoValidNamespace + ":" + hcoutil.HCOServiceAccountName
+		kvUser       = "system:serviceaccount:" + HcoValidNamespace + ":kubevirt-operator"
+		otherNsUser  = "system:serviceaccount:default:" + hcoutil.HCOServiceAccountName
+		otherSAUser  = "system:serviceaccount:" + HcoValidNamespace + ":default"
+	)
+
+	var (
+		cr  *v1beta1.HyperConverged
+		cli *commonTestUtils.HcoTestClient
+		wh  *WebhookHandler
+	)
+
+	newOperand := func(kind string, spec map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetKind(kind)
+		obj.SetName("operand")
+		return obj
+	}
+
+	BeforeEach(func() {
+		cr = &v1beta1.HyperConverged{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        ResourceName,
+				Namespace:   HcoValidNamespace,
+				Annotations: map[string]string{common.OperandGuardAnnotationName: "true"},
+			},
+		}
+		cli = commonTestUtils.InitClient(nil)
+		Expect(cli.Create(context.TODO(), cr)).To(Succeed())
+
+		wh = &WebhookHandler{}
+		wh.Init(logger, cli, HcoValidNamespace, true)
+	})
+
+	It("should reject a spec change by a user, and point to the HyperConverged fields", func() {
+		exists := newOperand("KubeVirt", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("KubeVirt", map[string]interface{}{"foo": "baz"})
+
+		err := wh.ValidateOperandUpdate(requested, exists, adminUser)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the spec of the KubeVirt operand is managed by HCO"))
+		Expect(err.Error()).To(ContainSubstring("spec.featureGates, spec.infra, spec.workloads or one of the " +
+			common.JSONPatchKVAnnotationName + ", " + common.MergePatchKVAnnotationName + ", " + common.StrategicPatchKVAnnotationName + " annotations"))
+		Expect(err.Error()).To(ContainSubstring(common.BreakGlassAnnotationName))
+	})
+
+	DescribeTable("should name the HyperConverged fields of each operand",
+		func(kind, fields string) {
+			exists := newOperand(kind, map[string]interface{}{"foo": "bar"})
+			requested := newOperand(kind, map[string]interface{}{"foo": "baz"})
+
+			err := wh.ValidateOperandUpdate(requested, exists, adminUser)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("please edit " + fields + " of the HyperConverged"))
+		},
+		Entry("CDI", "CDI", "spec.infra, spec.workloads or one of the "+common.JSONPatchCDIAnnotationName+", "+
+			common.MergePatchCDIAnnotationName+", "+common.StrategicPatchCDIAnnotationName+" annotations"),
+		Entry("NetworkAddonsConfig", "NetworkAddonsConfig", "spec.infra, spec.workloads or one of the "+common.JSONPatchCNAOAnnotationName+", "+
+			common.MergePatchCNAOAnnotationName+", "+common.StrategicPatchCNAOAnnotationName+" annotations"),
+		Entry("SSP", "SSP", "spec.infra, spec.workloads or one of the "+common.JSONPatchSSPAnnotationName+", "+
+			common.MergePatchSSPAnnotationName+", "+common.StrategicPatchSSPAnnotationName+" annotations"),
+		Entry("VMImportConfig", "VMImportConfig", "spec.infra or one of the "+common.JSONPatchVMImportAnnotationName+", "+
+			common.MergePatchVMImportAnnotationName+", "+common.StrategicPatchVMImportAnnotationName+" annotations"),
+	)
+
+	It("should reject a spec change by a service account of another namespace", func() {
+		exists := newOperand("CDI", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("CDI", map[string]interface{}{"foo": "baz"})
+
+		Expect(wh.ValidateOperandUpdate(requested, exists, otherNsUser)).ToNot(Succeed())
+	})
+
+	It("should reject a spec change by another service account of the HCO namespace", func() {
+		exists := newOperand("CDI", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("CDI", map[string]interface{}{"foo": "baz"})
+
+		Expect(wh.ValidateOperandUpdate(requested, exists, otherSAUser)).ToNot(Succeed())
+	})
+
+	DescribeTable("should allow the change",
+		func(user string, annotations map[string]string, requestedSpec map[string]interface{}) {
+			exists := newOperand("KubeVirt", map[string]interface{}{"foo": "bar"})
+			requested := newOperand("KubeVirt", requestedSpec)
+			requested.SetAnnotations(annotations)
+
+			Expect(wh.ValidateOperandUpdate(requested, exists, user)).To(Succeed())
+		},
+		Entry("if the spec was not changed", adminUser, nil, map[string]interface{}{"foo": "bar"}),
+		Entry("if it was made by HCO", operatorUser, nil, map[string]interface{}{"foo": "baz"}),
+		Entry("if it was made by an operand operator", kvUser, nil, map[string]interface{}{"foo": "baz"}),
+		Entry("if the break-glass annotation is set", adminUser, map[string]string{common.BreakGlassAnnotationName: "true"}, map[string]interface{}{"foo": "baz"}),
+	)
+
+	It("should not allow the change if the break-glass annotation is not true", func() {
+		exists := newOperand("KubeVirt", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("KubeVirt", map[string]interface{}{"foo": "baz"})
+		requested.SetAnnotations(map[string]string{common.BreakGlassAnnotationName: "false"})
+
+		Expect(wh.ValidateOperandUpdate(requested, exists, adminUser)).ToNot(Succeed())
+	})
+
+	It("should allow any change if the operand guard is not enabled", func() {
+		cr.Annotations = nil
+		Expect(cli.Update(context.TODO(), cr)).To(Succeed())
+
+		exists := newOperand("KubeVirt", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("KubeVirt", map[string]interface{}{"foo": "baz"})
+
+		Expect(wh.ValidateOperandUpdate(requested, exists, adminUser)).To(Succeed())
+	})
+
+	It("should allow any change if the HyperConverged CR does not exist", func() {
+		Expect(cli.Delete(context.TODO(), cr)).To(Succeed())
+
+		exists := newOperand("KubeVirt", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("KubeVirt", map[string]interface{}{"foo": "baz"})
+
+		Expect(wh.ValidateOperandUpdate(requested, exists, adminUser)).To(Succeed())
+	})
+
+	It("should allow the change if the HyperConverged CR can't be read", func() {
+		cli.InitiateGetErrors(func(key client.ObjectKey) error {
+			return errors.New("fake get error")
+		})
+
+		exists := newOperand("KubeVirt", map[string]interface{}{"foo": "bar"})
+		requested := newOperand("KubeVirt", map[string]interface{}{"foo": "baz"})
+
+		Expect(wh.ValidateOperandUpdate(requested, exists, adminUser)).To(Succeed())
+	})
+
+	It("should allow only the service accounts of the deployments of HCO and of the operand operators", func() {
+		// the deployments are generated by the manifest-templator, from the HCO components and the operand CSVs
+		content, err := ioutil.ReadFile("../../deploy/operator.yaml")
+		Expect(err).ToNot(HaveOccurred())
+
+		var deploymentServiceAccounts []string
+		for _, doc := range strings.Split(string(content), "\n---\n") {
+			deployment := &appsv1.Deployment{}
+			Expect(yaml.Unmarshal([]byte(doc), deployment)).To(Succeed())
+			if deployment.Kind == "Deployment" {
+				deploymentServiceAccounts = append(deploymentServiceAccounts, deployment.Spec.Template.Spec.ServiceAccountName)
+			}
+		}
+
+		Expect(deploymentServiceAccounts).To(ContainElement(hcoutil.HCOServiceAccountName))
+		for _, sa := range operandGuardAllowedServiceAccounts {
+			Expect(deploymentServiceAccounts).To(ContainElement(sa))
+		}
+	})
+})
@@ -0,0 +1,196 @@
+package webhooks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/validate"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	operandCRDsLocationVarName = "OPERAND_CRDS_LOCATION"
+	defaultOperandCRDsLocation = "./operandCRDs"
+)
+
+// operandSchema validates the CRs of one version of an operand CRD
+type operandSchema struct {
+	validator *validate.SchemaValidator
+	// structural is nil if the schema is not structural; such schemas have no defaults
+	structural *structuralschema.Structural
+}
+
+// operandSchemas holds the OpenAPI schemas of the operand CRDs, that are bundled with the webhook, so the rendered
+// operand CRs are validated in-process, as the API server would validate them, without calling the API server.
+type operandSchemas map[schema.GroupVersionKind]*operandSchema
+
+var (
+	loadedOperandSchemas operandSchemas
+	loadSchemasOnce      sync.Once
+)
+
+// getOperandSchemas loads the operand CRDs from the directory that is set by the OPERAND_CRDS_LOCATION environment
+// variable, once. If the CRDs can't be read, no schema is returned, and the rendered CRs are only validated by the
+// dry-run requests.
+func (wh WebhookHandler) getOperandSchemas() operandSchemas {
+	loadSchemasOnce.Do(func() {
+		dir := defaultOperandCRDsLocation
+		if val, ok := os.LookupEnv(operandCRDsLocationVarName); ok && val != "" {
+			dir = val
+		}
+
+		schemas, err := loadOperandSchemas(dir)
+		if err != nil {
+			wh.logger.Error(err, "can't load the operand CRDs; the operand CRs won't be validated offline", "directory", dir)
+		}
+		loadedOperandSchemas = schemas
+	})
+
+	return loadedOperandSchemas
+}
+
+// loadOperandSchemas reads the CRDs from the yaml files in the directory, in any apiextensions version
+func loadOperandSchemas(dir string) (operandSchemas, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	decoder := serializer.NewCodecFactory(scheme).UniversalDecoder()
+
+	schemas := operandSchemas{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".yaml") {
+			continue
+		}
+
+		if err := schemas.addFile(filepath.Join(dir, file.Name()), decoder); err != nil {
+			return nil, fmt.Errorf("failed to load the CRDs from %s; %w", file.Name(), err)
+		}
+	}
+
+	return schemas, nil
+}
+
+func (s operandSchemas) addFile(path string, decoder runtime.Decoder) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		crd := &apiextensions.CustomResourceDefinition{}
+		if _, _, err := decoder.Decode(doc, nil, crd); err != nil {
+			return err
+		}
+
+		if err := s.addCRD(crd); err != nil {
+			return err
+		}
+	}
+}
+
+func (s operandSchemas) addCRD(crd *apiextensions.CustomResourceDefinition) error {
+	for _, version := range crd.Spec.Versions {
+		validationSchema, err := apiextensions.GetSchemaForVersion(crd, version.Name)
+		if err != nil {
+			return err
+		}
+
+		if validationSchema == nil || validationSchema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		validator, _, err := validation.NewSchemaValidator(validationSchema)
+		if err != nil {
+			return err
+		}
+
+		structural, err := structuralschema.NewStructural(validationSchema.OpenAPIV3Schema)
+		if err != nil {
+			structural = nil
+		}
+
+		gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+		s[gvk] = &operandSchema{validator: validator, structural: structural}
+	}
+
+	return nil
+}
+
+// validate validates the rendered CRs against the schemas of their CRDs. The CRs without a bundled schema are
+// ignored. All the errors are returned.
+func (s operandSchemas) validate(scheme *runtime.Scheme, objects ...client.Object) error {
+	var errs []error
+	for _, obj := range objects {
+		if err := s.validateObject(scheme, obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (s operandSchemas) validateObject(scheme *runtime.Scheme, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+
+	operandSchema, found := s[gvk]
+	if !found {
+		return nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	// the status is not validated on create and update
+	delete(content, "status")
+	content["apiVersion"], content["kind"] = gvk.ToAPIVersionAndKind()
+
+	// as the API server, apply the defaults of the schema before validating the CR
+	if operandSchema.structural != nil {
+		defaulting.Default(content, operandSchema.structural)
+	}
+
+	if errs := validation.ValidateCustomResource(nil, content, operandSchema.validator); len(errs) > 0 {
+		return fmt.Errorf("the rendered %s %s is invalid; %w", gvk.Kind, obj.GetName(), errs.ToAggregate())
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	csvv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+)
+
+const (
+	crdsLocation = "../../deploy/crds"
+	csvLocation  = "../../deploy/olm-catalog/community-kubevirt-hyperconverged/1.4.0/kubevirt-hyperconverged-operator.v1.4.0.clusterserviceversion.yaml"
+)
+
+var _ = Describe("offline validation of the operand CRs", func() {
+	enabled := true
+
+	var schemas operandSchemas
+
+	BeforeEach(func() {
+		var err error
+		schemas, err = loadOperandSchemas(crdsLocation)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	render := func(hc *v1beta1.HyperConverged) []client.Object {
+		kv, err := operands.NewKubeVirt(hc)
+		Expect(err).ToNot(HaveOccurred())
+		cdi, err := operands.NewCDI(hc)
+		Expect(err).ToNot(HaveOccurred())
+		cna, err := operands.NewNetworkAddons(hc)
+		Expect(err).ToNot(HaveOccurred())
+		ssp, err := operands.NewSSP(hc)
+		Expect(err).ToNot(HaveOccurred())
+		vmImport, err := operands.NewVMImportForCR(hc)
+		Expect(err).ToNot(HaveOccurred())
+
+		return []client.Object{kv, cdi, cna, ssp, vmImport}
+	}
+
+	// the sample HyperConverged CRs, that are deployed by the deploy scripts and by the OLM
+	getSamples := func() []*v1beta1.HyperConverged {
+		content, err := ioutil.ReadFile("../../deploy/hco.cr.yaml")
+		Expect(err).ToNot(HaveOccurred())
+
+		hc := &v1beta1.HyperConverged{}
+		Expect(yaml.Unmarshal(content, hc)).To(Succeed())
+		samples := []*v1beta1.HyperConverged{hc}
+
+		content, err = ioutil.ReadFile(csvLocation)
+		Expect(err).ToNot(HaveOccurred())
+		csv := &csvv1alpha1.ClusterServiceVersion{}
+		Expect(yaml.Unmarshal(content, csv)).To(Succeed())
+
+		var examples []json.RawMessage
+		Expect(json.Unmarshal([]byte(csv.Annotations["alm-examples"]), &examples)).To(Succeed())
+		for _, example := range examples {
+			meta := &metav1.TypeMeta{}
+			Expect(json.Unmarshal(example, meta)).To(Succeed())
+			if meta.Kind != "HyperConverged" {
+				continue
+			}
+
+			hc := &v1beta1.HyperConverged{}
+			Expect(json.Unmarshal(example, hc)).To(Succeed())
+			samples = append(samples, hc)
+		}
+
+		Expect(samples).To(HaveLen(2))
+		for _, hc := range samples {
+			hc.Namespace = HcoValidNamespace
+			hc.SetDefaults()
+		}
+		return samples
+	}
+
+	It("should load the schemas of all the operand CRDs", func() {
+		for _, gvk := range []schema.GroupVersionKind{
+			{Group: "kubevirt.io", Version: "v1", Kind: "KubeVirt"},
+			{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "CDI"},
+			{Group: "networkaddonsoperator.network.kubevirt.io", Version: "v1", Kind: "NetworkAddonsConfig"},
+			{Group: "ssp.kubevirt.io", Version: "v1beta1", Kind: "SSP"},
+			{Group: "v2v.kubevirt.io", Version: "v1beta1", Kind: "VMImportConfig"},
+		} {
+			Expect(schemas).To(HaveKey(gvk))
+		}
+	})
+
+	It("should render valid operand CRs for every sample HyperConverged CR", func() {
+		for _, hc := range getSamples() {
+			Expect(schemas.validate(commonTestUtils.GetScheme(), render(hc)...)).To(Succeed())
+		}
+	})
+
+	DescribeTable("should render valid operand CRs",
+		func(modify func(hc *v1beta1.HyperConverged)) {
+			for _, hc := range getSamples() {
+				modify(hc)
+				Expect(schemas.validate(commonTestUtils.GetScheme(), render(hc)...)).To(Succeed())
+			}
+		},
+		Entry("with node placements", func(hc *v1beta1.HyperConverged) {
+			hc.Spec.Infra.NodePlacement = newHyperConvergedConfig()
+			hc.Spec.Workloads.NodePlacement = newHyperConvergedConfig()
+		}),
+		Entry("with all the feature gates", func(hc *v1beta1.HyperConverged) {
+			hc.Spec.FeatureGates = &v1beta1.HyperConvergedFeatureGates{
+				SRIOVLiveMigration:     &enabled,
+				HotplugVolumes:         &enabled,
+				GPU:                    &enabled,
+				HostDevices:            &enabled,
+				WithHostPassthroughCPU: &enabled,
+				WithHostModelCPU:       &enabled,
+				HypervStrictCheck:      &enabled,
+			}
+		}),
+		Entry("with jsonpatch annotations", func(hc *v1beta1.HyperConverged) {
+			hc.Annotations = map[string]string{
+				common.JSONPatchKVAnnotationName:   validKvAnnotation,
+				common.JSONPatchCDIAnnotationName:  validCdiAnnotation,
+				common.JSONPatchCNAOAnnotationName: validCnaAnnotation,
+			}
+		}),
+	)
+
+	It("should reject a jsonpatch annotation that breaks the schema of the operand CR", func() {
+		hc := getSamples()[0]
+		// the filesystem overhead is a fraction; the type of the field is string, so only the schema can reject it
+		hc.Annotations = map[string]string{
+			common.JSONPatchCDIAnnotationName: `[{"op": "add", "path": "/spec/config/filesystemOverhead", "value": {"global": "50"}}]`,
+		}
+
+		err := schemas.validate(commonTestUtils.GetScheme(), render(hc)...)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the rendered CDI cdi-kubevirt-hyperconverged is invalid"))
+		Expect(err.Error()).To(ContainSubstring("spec.config.filesystemOverhead.global"))
+	})
+
+	It("should reject the creation of a HyperConverged CR with an invalid jsonpatch annotation, without calling the API server", func() {
+		cli := commonTestUtils.InitClient(nil)
+		cli.InitiateCreateErrors(func(obj client.Object) error {
+			if _, ok := obj.(*cdiv1beta1.CDI); ok {
+				Fail("the invalid CDI CR should not be created")
+			}
+			return nil
+		})
+		wh := &WebhookHandler{}
+		wh.Init(logger, cli, HcoValidNamespace, true)
+
+		hc := getSamples()[0]
+		hc.Annotations = map[string]string{
+			common.JSONPatchCDIAnnotationName: `[{"op": "add", "path": "/spec/config/filesystemOverhead", "value": {"global": "50"}}]`,
+		}
+
+		_, err := wh.ValidateCreate(hc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.config.filesystemOverhead.global"))
+	})
+
+	It("should reject the update of a HyperConverged CR with an invalid jsonpatch annotation", func() {
+		hc := getSamples()[0]
+		wh := &WebhookHandler{}
+		wh.Init(logger, getFakeClient(hc), HcoValidNamespace, true)
+
+		requested := hc.DeepCopy()
+		requested.Annotations = map[string]string{
+			common.JSONPatchCDIAnnotationName: `[{"op": "add", "path": "/spec/config/filesystemOverhead", "value": {"global": "50"}}]`,
+		}
+
+		_, err := wh.ValidateUpdate(requested, hc)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.config.filesystemOverhead.global"))
+	})
+
+	It("should fail to load the schemas from a missing directory", func() {
+		_, err := loadOperandSchemas("/not/a/directory")
+		Expect(err).To(HaveOccurred())
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
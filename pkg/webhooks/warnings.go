@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
+	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
+)
+
+// warningRule detects a risky or a deprecated setting in the HyperConverged CR. The warnings are returned to the
+// client as admission warnings; they do not block the request.
+type warningRule struct {
+	// name identifies the rule in the logs
+	name  string
+	check func(hc *v1beta1.HyperConverged) []string
+}
+
+// warningRules is the catalog of the admission warnings, in the order they are returned
+var warningRules = []warningRule{
+	{
+		name: "sriovLiveMigration",
+		check: func(hc *v1beta1.HyperConverged) []string {
+			if !hc.Spec.FeatureGates.IsEnabled(v1beta1.SRIOVLiveMigrationGateName) {
+				return nil
+			}
+			return []string{"the sriovLiveMigration feature gate runs the virt-launcher pods of the virtual machines with SR-IOV interfaces with the CAP_SYS_RESOURCE capability; this may degrade the security of virt-launcher"}
+		},
+	},
+	{
+		name: "withHostPassthroughCPU",
+		check: func(hc *v1beta1.HyperConverged) []string {
+			if !hc.Spec.FeatureGates.IsEnabled(v1beta1.WithHostPassthroughCPUGateName) {
+				return nil
+			}
+			return []string{"the withHostPassthroughCPU feature gate requires all the nodes in the cluster to have the same CPU model; otherwise, the live migration of virtual machines with the host-passthrough CPU mode may fail"}
+		},
+	},
+	{
+		name: "patchAnnotations",
+		check: func(hc *v1beta1.HyperConverged) []string {
+			var warnings []string
+			for _, annotation := range operands.GetActivePatchAnnotations(hc) {
+				warnings = append(warnings, fmt.Sprintf("the %s annotation is an unsupported debug feature; using it taints the configuration of the cluster", annotation))
+			}
+			return warnings
+		},
+	},
+}
+
+// getWarnings returns the warnings of all the rules in the catalog, and of the node placements
+func (wh WebhookHandler) getWarnings(hc *v1beta1.HyperConverged) []string {
+	var warnings []string
+	for _, rule := range warningRules {
+		if ruleWarnings := rule.check(hc); len(ruleWarnings) > 0 {
+			wh.logger.Info("admission warning", "rule", rule.name, "warnings", ruleWarnings)
+			warnings = append(warnings, ruleWarnings...)
+		}
+	}
+
+	return append(warnings, wh.getPlacementWarnings(context.TODO(), hc)...)
+}
+
+// getPlacementWarnings warns about the node placements that do not match any eligible node, so the operand pods that
+// use them would stay pending. A failure to read the nodes is logged, and does not produce a warning.
+func (wh WebhookHandler) getPlacementWarnings(ctx context.Context, hc *v1beta1.HyperConverged) []string {
+	var warnings []string
+	for _, placement := range []struct {
+		field     string
+		placement *sdkapi.NodePlacement
+	}{
+		{field: "spec.infra.nodePlacement", placement: hc.Spec.Infra.NodePlacement},
+		{field: "spec.workloads.nodePlacement", placement: hc.Spec.Workloads.NodePlacement},
+	} {
+		if placement.placement == nil {
+			continue
+		}
+
+		nodes, err := hcoutil.GetEligibleNodes(ctx, wh.cli, placement.placement)
+		if err != nil {
+			wh.logger.Error(err, "failed to read the eligible nodes", "placement", placement.field)
+			continue
+		}
+
+		if len(nodes) == 0 {
+			warnings = append(warnings, fmt.Sprintf("%s does not match any schedulable node that is not under maintenance; the pods that use it will stay pending", placement.field))
+		}
+	}
+	return warnings
+}
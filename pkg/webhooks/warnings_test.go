@@ -0,0 +1,144 @@
+package webhooks
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	sdkapi "kubevirt.io/controller-lifecycle-operator-sdk/pkg/sdk/api"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/apis/hco/v1beta1"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/common"
+	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/commonTestUtils"
+)
+
+var _ = Describe("admission warnings", func() {
+	enabled := true
+
+	var (
+		wh *WebhookHandler
+		cr *v1beta1.HyperConverged
+	)
+
+	BeforeEach(func() {
+		Expect(os.Setenv("OPERATOR_NAMESPACE", HcoValidNamespace)).To(BeNil())
+		wh = &WebhookHandler{}
+		wh.Init(logger, fake.NewFakeClientWithScheme(scheme.Scheme), HcoValidNamespace, true)
+
+		cr = &v1beta1.HyperConverged{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ResourceName,
+				Namespace: HcoValidNamespace,
+			},
+			Spec: v1beta1.HyperConvergedSpec{},
+		}
+		cr.SetDefaults()
+	})
+
+	It("should not warn about the defaults", func() {
+		Expect(wh.getWarnings(cr)).To(BeEmpty())
+	})
+
+	It("should warn that sriovLiveMigration adds the CAP_SYS_RESOURCE capability", func() {
+		cr.Spec.FeatureGates.SRIOVLiveMigration = &enabled
+		warnings := wh.getWarnings(cr)
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("CAP_SYS_RESOURCE"))
+	})
+
+	It("should warn that withHostPassthroughCPU requires homogeneous CPUs", func() {
+		cr.Spec.FeatureGates.WithHostPassthroughCPU = &enabled
+		warnings := wh.getWarnings(cr)
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("the same CPU model"))
+	})
+
+	DescribeTable("should warn that the patch annotations taint the configuration",
+		func(annotation string) {
+			cr.Annotations = map[string]string{annotation: "[]"}
+			warnings := wh.getWarnings(cr)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring(annotation))
+			Expect(warnings[0]).To(ContainSubstring("taints"))
+		},
+		Entry("KubeVirt", common.JSONPatchKVAnnotationName),
+		Entry("CDI", common.JSONPatchCDIAnnotationName),
+		Entry("CNAO", common.JSONPatchCNAOAnnotationName),
+		Entry("SSP merge patch", common.MergePatchSSPAnnotationName),
+		Entry("kubevirt-config strategic merge patch", common.StrategicPatchKVConfigAnnotationName),
+	)
+
+	Context("node placement", func() {
+		BeforeEach(func() {
+			cli := commonTestUtils.InitClient([]runtime.Object{
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node01", Labels: map[string]string{"role": "infra"}}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node02", Labels: map[string]string{"role": "worker"}}},
+			})
+			wh.Init(logger, cli, HcoValidNamespace, true)
+		})
+
+		It("should not warn if the placements match eligible nodes", func() {
+			cr.Spec.Infra.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "infra"}}
+			cr.Spec.Workloads.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "worker"}}
+			Expect(wh.getWarnings(cr)).To(BeEmpty())
+		})
+
+		It("should warn if a placement does not match any node", func() {
+			cr.Spec.Infra.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "infra"}}
+			cr.Spec.Workloads.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "wroker"}}
+			warnings := wh.getWarnings(cr)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(HavePrefix("spec.workloads.nodePlacement does not match any schedulable node"))
+		})
+
+		It("should warn if the matching nodes are not schedulable", func() {
+			cordoned := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node03", Labels: map[string]string{"role": "gpu"}}}
+			cordoned.Spec.Unschedulable = true
+			Expect(wh.cli.Create(context.TODO(), cordoned)).To(Succeed())
+
+			cr.Spec.Workloads.NodePlacement = &sdkapi.NodePlacement{NodeSelector: map[string]string{"role": "gpu"}}
+			warnings := wh.getWarnings(cr)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(HavePrefix("spec.workloads.nodePlacement"))
+		})
+	})
+
+	It("should return the warnings from ValidateCreate", func() {
+		cr.Spec.FeatureGates.SRIOVLiveMigration = &enabled
+		cr.Annotations = map[string]string{common.JSONPatchKVAnnotationName: "[]"}
+		warnings, err := wh.ValidateCreate(cr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(HaveLen(2))
+	})
+
+	It("should not return warnings if the validation failed", func() {
+		cr.Namespace = ResourceInvalidNamespace
+		cr.Spec.FeatureGates.SRIOVLiveMigration = &enabled
+		warnings, err := wh.ValidateCreate(cr)
+		Expect(err).To(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should return the warnings from ValidateUpdate", func() {
+		cr.Spec.FeatureGates.WithHostPassthroughCPU = &enabled
+		warnings, err := wh.ValidateUpdate(cr.DeepCopy(), cr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(HaveLen(1))
+	})
+
+	It("should not return warnings if the update validation failed", func() {
+		updated := cr.DeepCopy()
+		updated.Spec.FeatureGates.WithHostPassthroughCPU = &enabled
+		// the operand CRs do not exist, so the dry-run update fails
+		warnings, err := wh.ValidateUpdate(updated, cr)
+		Expect(err).To(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})
@@ -13,9 +13,12 @@ import (
 	"github.com/kubevirt/hyperconverged-cluster-operator/pkg/controller/operands"
 	hcoutil "github.com/kubevirt/hyperconverged-cluster-operator/pkg/util"
 	vmimportv1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	consolev1 "github.com/openshift/api/console/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
 	sspv1beta1 "kubevirt.io/ssp-operator/api/v1beta1"
@@ -24,13 +27,16 @@ import (
 
 const (
 	updateDryRunTimeOut = time.Second * 3
+	createDryRunTimeOut = time.Second * 3
 )
 
 type WebhookHandler struct {
-	logger      logr.Logger
-	cli         client.Client
-	namespace   string
-	isOpenshift bool
+	logger       logr.Logger
+	cli          client.Client
+	namespace    string
+	isOpenshift  bool
+	eventEmitter hcoutil.EventEmitter
+	auditWriter  *auditWriter
 }
 
 func (wh *WebhookHandler) Init(logger logr.Logger, cli client.Client, namespace string, isOpenshift bool) {
@@ -38,33 +44,119 @@ func (wh *WebhookHandler) Init(logger logr.Logger, cli client.Client, namespace
 	wh.cli = cli
 	wh.namespace = namespace
 	wh.isOpenshift = isOpenshift
+	wh.eventEmitter = hcoutil.GetEventEmitter()
+	wh.auditWriter = newAuditWriter(logger, cli, namespace)
 }
 
-func (wh WebhookHandler) ValidateCreate(hc *v1beta1.HyperConverged) error {
+// ValidateCreate is the ValidateCreate webhook implementation. It returns the admission warnings of the new CR.
+func (wh WebhookHandler) ValidateCreate(hc *v1beta1.HyperConverged) ([]string, error) {
+	if err := wh.validateCreate(hc); err != nil {
+		return nil, err
+	}
+
+	return wh.getWarnings(hc), nil
+}
+
+func (wh WebhookHandler) validateCreate(hc *v1beta1.HyperConverged) error {
 	wh.logger.Info("Validating create", "name", hc.Name, "namespace:", hc.Namespace)
 
 	if hc.Namespace != wh.namespace {
 		return fmt.Errorf("invalid namespace for v1beta1.HyperConverged - please use the %s namespace", wh.namespace)
 	}
 
-	if _, err := operands.NewKubeVirt(hc); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), createDryRunTimeOut)
+	defer cancel()
+
+	if err := wh.validateCPUModels(ctx, hc, nil); err != nil {
 		return err
 	}
 
-	if _, err := operands.NewCDI(hc); err != nil {
+	if err := operands.ValidateHppStoragePool(ctx, wh.cli, hc); err != nil {
 		return err
 	}
 
-	if _, err := operands.NewNetworkAddons(hc); err != nil {
+	return wh.dryRunCreateOperands(ctx, hc)
+}
+
+// dryRunCreateOperands renders the operand CRs of a new HyperConverged CR, including its patch annotations, validates
+// them against the bundled schemas of the operand CRDs, and dry-runs their creation. All the errors are returned.
+func (wh WebhookHandler) dryRunCreateOperands(ctx context.Context, hc *v1beta1.HyperConverged) error {
+	if err := operands.ValidatePatchAnnotations(hc); err != nil {
 		return err
 	}
 
-	return nil
+	var errs []error
+	var resources []client.Object
+
+	kv, err := operands.NewKubeVirt(hc)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		resources = append(resources, kv)
+	}
+
+	cdi, err := operands.NewCDI(hc)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		resources = append(resources, cdi)
+	}
+
+	cna, err := operands.NewNetworkAddons(hc)
+	if err != nil {
+		errs = append(errs, err)
+	} else if hc.Spec.Components.IsNetworkAddonsEnabled() {
+		resources = append(resources, cna)
+	}
+
+	vmImport, err := operands.NewVMImportForCR(hc)
+	if err != nil {
+		errs = append(errs, err)
+	} else if hc.Spec.Components.IsVMImportEnabled() {
+		resources = append(resources, vmImport)
+	}
+
+	ssp, err := operands.NewSSP(hc)
+	if err != nil {
+		errs = append(errs, err)
+	} else if wh.isOpenshift && hc.Spec.Components.IsSSPEnabled() {
+		resources = append(resources, ssp)
+	}
+
+	schemas := wh.getOperandSchemas()
+	opts := &client.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+	for _, obj := range resources {
+		// validate the rendered CR against the schema of its CRD first, to not call the API server for an invalid CR
+		if err := schemas.validateObject(wh.cli.Scheme(), obj); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := wh.cli.Create(ctx, obj, opts); err != nil {
+			// an operand CR may be left from a previous installation, or its CRD may not be deployed yet
+			if apierrors.IsAlreadyExists(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+
+			wh.logger.Error(err, "failed to dry-run create the object", "kind", obj.GetObjectKind())
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.Reduce(utilerrors.NewAggregate(errs))
 }
 
 // ValidateUpdate is the ValidateUpdate webhook implementation. It calls all the resources in parallel, to dry-run the
-// upgrade.
-func (wh WebhookHandler) ValidateUpdate(requested *v1beta1.HyperConverged, exists *v1beta1.HyperConverged) error {
+// upgrade, and returns the admission warnings of the updated CR.
+func (wh WebhookHandler) ValidateUpdate(requested *v1beta1.HyperConverged, exists *v1beta1.HyperConverged) ([]string, error) {
+	if err := wh.validateUpdate(requested, exists); err != nil {
+		return nil, err
+	}
+
+	return wh.getWarnings(requested), nil
+}
+
+func (wh WebhookHandler) validateUpdate(requested *v1beta1.HyperConverged, exists *v1beta1.HyperConverged) error {
 	wh.logger.Info("Validating update", "name", requested.Name)
 	ctx, cancel := context.WithTimeout(context.Background(), updateDryRunTimeOut)
 	defer cancel()
@@ -75,6 +167,18 @@ func (wh WebhookHandler) ValidateUpdate(requested *v1beta1.HyperConverged, exist
 		return nil
 	}
 
+	if err := wh.validateCPUModels(ctx, requested, exists); err != nil {
+		return err
+	}
+
+	if err := operands.ValidateHppStoragePool(ctx, wh.cli, requested); err != nil {
+		return err
+	}
+
+	if err := operands.ValidatePatchAnnotations(requested); err != nil {
+		return err
+	}
+
 	kv, err := operands.NewKubeVirt(requested)
 	if err != nil {
 		return err
@@ -90,27 +194,59 @@ func (wh WebhookHandler) ValidateUpdate(requested *v1beta1.HyperConverged, exist
 		return err
 	}
 
-	wg := sync.WaitGroup{}
-	errorCh := make(chan error)
-	done := make(chan bool)
+	vmImport, err := operands.NewVMImportForCR(requested)
+	if err != nil {
+		return err
+	}
+
+	ssp, err := operands.NewSSP(requested)
+	if err != nil {
+		return err
+	}
+
+	// all the required operand CRs are validated offline, including the ones that were not deployed yet
+	rendered := []client.Object{kv, cdi}
+	if requested.Spec.Components.IsNetworkAddonsEnabled() {
+		rendered = append(rendered, cna)
+	}
+	if requested.Spec.Components.IsVMImportEnabled() {
+		rendered = append(rendered, vmImport)
+	}
+	if wh.isOpenshift && requested.Spec.Components.IsSSPEnabled() {
+		rendered = append(rendered, ssp)
+	}
+
+	if err := wh.getOperandSchemas().validate(wh.cli.Scheme(), rendered...); err != nil {
+		return err
+	}
 
 	opts := &client.UpdateOptions{DryRun: []string{metav1.DryRunAll}}
 
 	resources := []client.Object{
 		kv,
 		cdi,
-		cna,
-		operands.NewVMImportForCR(requested),
 	}
 
-	if wh.isOpenshift {
-		resources = append(resources,
-			operands.NewSSP(requested),
-		)
+	// optional components are validated only if they were deployed, and are still required
+	if exists.Spec.Components.IsNetworkAddonsEnabled() && requested.Spec.Components.IsNetworkAddonsEnabled() {
+		resources = append(resources, cna)
+	}
+
+	if exists.Spec.Components.IsVMImportEnabled() && requested.Spec.Components.IsVMImportEnabled() {
+		resources = append(resources, vmImport)
 	}
 
+	if wh.isOpenshift && exists.Spec.Components.IsSSPEnabled() && requested.Spec.Components.IsSSPEnabled() {
+		resources = append(resources, ssp)
+	}
+
+	wg := sync.WaitGroup{}
 	wg.Add(len(resources))
 
+	// buffered, so the goroutines never block on sending their errors, even if the timeout has expired
+	errorCh := make(chan error, len(resources))
+	done := make(chan bool)
+
 	go func() {
 		wg.Wait()
 		close(done)
@@ -126,19 +262,44 @@ func (wh WebhookHandler) ValidateUpdate(requested *v1beta1.HyperConverged, exist
 	}
 
 	select {
-	case err := <-errorCh:
-		return err
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-done:
-		// just in case close(done) was selected while there is an error,
-		// check the error channel again.
-		if len(errorCh) != 0 {
-			err := <-errorCh
-			return err
-		}
+	}
+
+	close(errorCh)
+	var errs []error
+	for err := range errorCh {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.Reduce(utilerrors.NewAggregate(errs))
+}
+
+// validateCPUModels rejects enabling the withHostPassthroughCPU feature gate, or changing the workloads node placement
+// while it is enabled, if the workload nodes support different CPU models. exists is nil on create.
+func (wh WebhookHandler) validateCPUModels(ctx context.Context, requested *v1beta1.HyperConverged, exists *v1beta1.HyperConverged) error {
+	if !requested.Spec.FeatureGates.IsWithHostPassthroughCPUEnabled() {
 		return nil
 	}
+
+	if exists != nil && exists.Spec.FeatureGates.IsWithHostPassthroughCPUEnabled() &&
+		reflect.DeepEqual(exists.Spec.Workloads, requested.Spec.Workloads) {
+		return nil
+	}
+
+	nodes, err := hcoutil.GetPlacementNodes(ctx, wh.cli, requested.Spec.Workloads.NodePlacement)
+	if err != nil {
+		wh.logger.Error(err, "failed to read the workload nodes")
+		return err
+	}
+
+	if models := hcoutil.GetDistinctCPUModels(nodes); len(models) > 1 {
+		return fmt.Errorf("the %s feature gate requires the workload nodes to support the same CPU models, but they support %d distinct sets of CPU models: %s",
+			v1beta1.WithHostPassthroughCPUGateName, len(models), hcoutil.FormatDistinctCPUModels(models))
+	}
+
+	return nil
 }
 
 func (wh WebhookHandler) updateOperatorCr(ctx context.Context, hc *v1beta1.HyperConverged, exists client.Object, opts *client.UpdateOptions) error {
@@ -171,11 +332,17 @@ func (wh WebhookHandler) updateOperatorCr(ctx context.Context, hc *v1beta1.Hyper
 		required.Spec.DeepCopyInto(&existing.Spec)
 
 	case *sspv1beta1.SSP:
-		required := operands.NewSSP(hc)
+		required, err := operands.NewSSP(hc)
+		if err != nil {
+			return err
+		}
 		required.Spec.DeepCopyInto(&existing.Spec)
 
 	case *vmimportv1beta1.VMImportConfig:
-		required := operands.NewVMImportForCR(hc)
+		required, err := operands.NewVMImportForCR(hc)
+		if err != nil {
+			return err
+		}
 		required.Spec.DeepCopyInto(&existing.Spec)
 	}
 
@@ -188,26 +355,65 @@ func (wh WebhookHandler) updateOperatorCr(ctx context.Context, hc *v1beta1.Hyper
 	return nil
 }
 
+// ValidateDelete dry-runs the deletion of every operand resource that is removed with the HyperConverged CR. All the
+// errors are returned.
 func (wh WebhookHandler) ValidateDelete(hc *v1beta1.HyperConverged) error {
 	wh.logger.Info("Validating delete", "name", hc.Name, "namespace", hc.Namespace)
 
 	ctx := context.TODO()
 
-	kv := operands.NewKubeVirtWithNameOnly(hc)
-	cdi := operands.NewCDIWithNameOnly(hc)
+	resources := []client.Object{
+		operands.NewKubeVirtWithNameOnly(hc),
+		operands.NewCDIWithNameOnly(hc),
+		operands.NewNetworkAddonsWithNameOnly(hc),
+		operands.NewSSPWithNameOnly(hc),
+		operands.NewConsoleCLIDownload(hc),
+		operands.NewVMImportWithNameOnly(hc),
+		operands.NewHostPathProvisionerWithNameOnly(hc),
+		operands.NewHppStorageClass(hc),
+	}
 
-	for _, obj := range []client.Object{
-		kv,
-		cdi,
-	} {
+	var errs []error
+
+	quickStarts, err := wh.getQuickStarts(ctx, hc)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	resources = append(resources, quickStarts...)
+
+	for _, obj := range resources {
 		err := hcoutil.EnsureDeleted(ctx, wh.cli, obj, hc.Name, wh.logger, true, false)
 		if err != nil {
 			wh.logger.Error(err, "Delete validation failed", "GVK", obj.GetObjectKind().GroupVersionKind())
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return utilerrors.Reduce(utilerrors.NewAggregate(errs))
+}
+
+// getQuickStarts returns the quick starts that were deployed by HCO. The webhook does not read the quick start
+// manifests, so they are found by their labels.
+func (wh WebhookHandler) getQuickStarts(ctx context.Context, hc *v1beta1.HyperConverged) ([]client.Object, error) {
+	qsList := &consolev1.ConsoleQuickStartList{}
+	err := wh.cli.List(ctx, qsList, client.MatchingLabels{
+		hcoutil.AppLabel:          hc.Name,
+		hcoutil.AppLabelManagedBy: hcoutil.OperatorName,
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// the console API is not available; there are no quick starts
+			return nil, nil
+		}
+		wh.logger.Error(err, "failed to list the quick starts")
+		return nil, err
+	}
+
+	quickStarts := make([]client.Object, 0, len(qsList.Items))
+	for i := range qsList.Items {
+		quickStarts = append(quickStarts, &qsList.Items[i])
+	}
+	return quickStarts, nil
 }
 
 func (wh WebhookHandler) HandleMutatingNsDelete(ns *corev1.Namespace, dryRun bool) (bool, error) {
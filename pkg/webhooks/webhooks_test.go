@@ -21,10 +21,13 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	consolev1 "github.com/openshift/api/console/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes/scheme"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1beta1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1beta1"
@@ -48,6 +51,8 @@ var (
 
 func TestWebhook(t *testing.T) {
 	RegisterFailHandler(Fail)
+	// validate the rendered operand CRs against the CRDs that are deployed with HCO
+	Expect(os.Setenv(operandCRDsLocationVarName, "../../deploy/crds")).To(Succeed())
 	RunSpecs(t, "Webhooks Suite")
 }
 
@@ -78,7 +83,7 @@ const (
 				{
 					"op": "add",
 					"path": "/spec/config/filesystemOverhead",
-					"value": {"global": "50", "storageClass": {"AAA": "75", "BBB": "25"}}
+					"value": {"global": "0.5", "storageClass": {"AAA": "0.75", "BBB": "0.25"}}
 				}
 			]`
 	validCnaAnnotation = `[
@@ -129,51 +134,136 @@ var _ = Describe("webhooks handler", func() {
 		wh.Init(logger, cli, HcoValidNamespace, true)
 
 		It("should accept creation of a resource with a valid namespace", func() {
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("should reject creation of a resource with an arbitrary namespace", func() {
 			cr.ObjectMeta.Namespace = ResourceInvalidNamespace
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).To(HaveOccurred())
 		})
 
 		It("should accept creation of a resource with a valid kv annotation", func() {
 			cr.Annotations = map[string]string{common.JSONPatchKVAnnotationName: validKvAnnotation}
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("should reject creation of a resource with an invalid kv annotation", func() {
 			cr.Annotations = map[string]string{common.JSONPatchKVAnnotationName: invalidKvAnnotation}
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).To(HaveOccurred())
 		})
 
 		It("should accept creation of a resource with a valid cdi annotation", func() {
 			cr.Annotations = map[string]string{common.JSONPatchCDIAnnotationName: validCdiAnnotation}
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("should reject creation of a resource with an invalid cdi annotation", func() {
 			cr.Annotations = map[string]string{common.JSONPatchCDIAnnotationName: invalidCdiAnnotation}
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).To(HaveOccurred())
 		})
 
 		It("should accept creation of a resource with a valid cna annotation", func() {
 			cr.Annotations = map[string]string{common.JSONPatchCNAOAnnotationName: validCnaAnnotation}
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("should reject creation of a resource with an invalid cna annotation", func() {
 			cr.Annotations = map[string]string{common.JSONPatchCNAOAnnotationName: invalidCnaAnnotation}
-			err := wh.ValidateCreate(cr)
+			_, err := wh.ValidateCreate(cr)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should return the errors of all the invalid annotations", func() {
+			cr.Annotations = map[string]string{
+				common.JSONPatchKVAnnotationName:  invalidKvAnnotation,
+				common.JSONPatchCDIAnnotationName: invalidCdiAnnotation,
+			}
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).To(HaveOccurred())
+
+			aggregated, ok := err.(utilerrors.Aggregate)
+			Expect(ok).To(BeTrue())
+			Expect(aggregated.Errors()).To(HaveLen(2))
+		})
+
+		It("should reject creation of a resource with an invalid ConfigMap patch annotation", func() {
+			cr.Annotations = map[string]string{common.MergePatchStorageConfigAnnotationName: `{"metadata": {"name": "other"}}`}
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid mergePatch in the %s annotation", common.MergePatchStorageConfigAnnotationName))
+		})
+
+		It("should accept creation of a resource with valid merge and strategic patch annotations", func() {
+			cr.Annotations = map[string]string{
+				common.MergePatchSSPAnnotationName:          `{"spec": {"templateValidator": {"replicas": 3}}}`,
+				common.StrategicPatchKVConfigAnnotationName: `{"data": {"debug.useEmulation": "true"}}`,
+			}
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should dry-run the creation of the operand CRs", func() {
+			cli := commonTestUtils.InitClient(nil)
+			var created []client.Object
+			cli.InitiateCreateErrors(func(obj client.Object) error {
+				created = append(created, obj)
+				return nil
+			})
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(ConsistOf(
+				BeAssignableToTypeOf(&kubevirtv1.KubeVirt{}),
+				BeAssignableToTypeOf(&cdiv1beta1.CDI{}),
+				BeAssignableToTypeOf(&networkaddonsv1.NetworkAddonsConfig{}),
+				BeAssignableToTypeOf(&vmimportv1beta1.VMImportConfig{}),
+				BeAssignableToTypeOf(&sspv1beta1.SSP{}),
+			))
+
+			By("Validate that no operand CR was created, as it a dry-run creation")
+			Expect(util.GetRuntimeObject(context.TODO(), cli, operands.NewKubeVirtWithNameOnly(cr), logger)).ToNot(Succeed())
+		})
+
+		It("should reject the creation if the dry-run creation of the operand CRs failed, and return all the errors", func() {
+			cli := commonTestUtils.InitClient(nil)
+			cli.InitiateCreateErrors(func(obj client.Object) error {
+				switch obj.(type) {
+				case *kubevirtv1.KubeVirt:
+					return ErrFakeKvError
+				case *sspv1beta1.SSP:
+					return ErrFakeSspError
+				}
+				return nil
+			})
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(ErrFakeKvError.Error()))
+			Expect(err.Error()).To(ContainSubstring(ErrFakeSspError.Error()))
+		})
+
+		It("should ignore the operand CRs that already exist", func() {
+			cli := commonTestUtils.InitClient(nil)
+			cli.InitiateCreateErrors(func(obj client.Object) error {
+				return apierrors.NewAlreadyExists(schema.GroupResource{}, obj.GetName())
+			})
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+		})
 	})
 
 	Context("validate update validation webhook", func() {
@@ -190,6 +280,8 @@ var _ = Describe("webhooks handler", func() {
 			wh.Init(logger, cli, HcoValidNamespace, true)
 
 			newHco := &v1beta1.HyperConverged{
+				// the same name, so only the deleted CR is missing
+				ObjectMeta: metav1.ObjectMeta{Name: hco.Name},
 				Spec: v1beta1.HyperConvergedSpec{
 					Infra: v1beta1.HyperConvergedConfig{
 						NodePlacement: newHyperConvergedConfig(),
@@ -200,7 +292,7 @@ var _ = Describe("webhooks handler", func() {
 				},
 			}
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).To(HaveOccurred())
 			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
@@ -227,11 +319,47 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(err).Should(Equal(ErrFakeKvError))
 		})
 
+		It("should return the errors of all the failed dry-run updates", func() {
+			hco := &v1beta1.HyperConverged{
+				Spec: v1beta1.HyperConvergedSpec{
+					Infra: v1beta1.HyperConvergedConfig{
+						NodePlacement: newHyperConvergedConfig(),
+					},
+					Workloads: v1beta1.HyperConvergedConfig{
+						NodePlacement: newHyperConvergedConfig(),
+					},
+				},
+			}
+			cli := getFakeClient(hco)
+			kvFailure := getUpdateError(kvUpdateFailure)
+			cdiFailure := getUpdateError(cdiUpdateFailure)
+			cli.InitiateUpdateErrors(func(obj client.Object) error {
+				if err := kvFailure(obj); err != nil {
+					return err
+				}
+				return cdiFailure(obj)
+			})
+
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			newHco := &v1beta1.HyperConverged{}
+			hco.DeepCopyInto(newHco)
+			// change something in workloads to trigger dry-run update
+			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
+
+			_, err := wh.ValidateUpdate(newHco, hco)
+			Expect(err).To(HaveOccurred())
+			aggregate, ok := err.(utilerrors.Aggregate)
+			Expect(ok).To(BeTrue())
+			Expect(aggregate.Errors()).To(ConsistOf(ErrFakeKvError, ErrFakeCdiError))
+		})
+
 		It("should return error if CDI CR is missing", func() {
 			hco := &v1beta1.HyperConverged{}
 			ctx := context.TODO()
@@ -243,6 +371,8 @@ var _ = Describe("webhooks handler", func() {
 			wh.Init(logger, cli, HcoValidNamespace, true)
 
 			newHco := &v1beta1.HyperConverged{
+				// the same name, so only the deleted CR is missing
+				ObjectMeta: metav1.ObjectMeta{Name: hco.Name},
 				Spec: v1beta1.HyperConvergedSpec{
 					Infra: v1beta1.HyperConvergedConfig{
 						NodePlacement: newHyperConvergedConfig(),
@@ -253,7 +383,7 @@ var _ = Describe("webhooks handler", func() {
 				},
 			}
 
-			err = wh.ValidateUpdate(newHco, hco)
+			_, err = wh.ValidateUpdate(newHco, hco)
 			Expect(err).To(HaveOccurred())
 			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
@@ -279,7 +409,7 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(err).Should(Equal(ErrFakeCdiError))
 		})
@@ -306,7 +436,7 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).To(BeNil())
 		})
 
@@ -321,6 +451,8 @@ var _ = Describe("webhooks handler", func() {
 			wh.Init(logger, cli, HcoValidNamespace, true)
 
 			newHco := &v1beta1.HyperConverged{
+				// the same name, so only the deleted CR is missing
+				ObjectMeta: metav1.ObjectMeta{Name: hco.Name},
 				Spec: v1beta1.HyperConvergedSpec{
 					Infra: v1beta1.HyperConvergedConfig{
 						NodePlacement: newHyperConvergedConfig(),
@@ -331,7 +463,7 @@ var _ = Describe("webhooks handler", func() {
 				},
 			}
 
-			err = wh.ValidateUpdate(newHco, hco)
+			_, err = wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
@@ -358,7 +490,7 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(err).Should(Equal(ErrFakeNetworkError))
 		})
@@ -367,11 +499,13 @@ var _ = Describe("webhooks handler", func() {
 			hco := &v1beta1.HyperConverged{}
 			ctx := context.TODO()
 			cli := getFakeClient(hco)
-			Expect(cli.Delete(ctx, operands.NewSSP(hco))).To(BeNil())
+			Expect(cli.Delete(ctx, operands.NewSSPWithNameOnly(hco))).To(BeNil())
 			wh := &WebhookHandler{}
 			wh.Init(logger, cli, HcoValidNamespace, true)
 
 			newHco := &v1beta1.HyperConverged{
+				// the same name, so only the deleted CR is missing
+				ObjectMeta: metav1.ObjectMeta{Name: hco.Name},
 				Spec: v1beta1.HyperConvergedSpec{
 					Infra: v1beta1.HyperConvergedConfig{
 						NodePlacement: newHyperConvergedConfig(),
@@ -382,7 +516,7 @@ var _ = Describe("webhooks handler", func() {
 				},
 			}
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
@@ -408,7 +542,7 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(err).Should(Equal(ErrFakeSspError))
 
@@ -418,11 +552,13 @@ var _ = Describe("webhooks handler", func() {
 			hco := &v1beta1.HyperConverged{}
 			ctx := context.TODO()
 			cli := getFakeClient(hco)
-			Expect(cli.Delete(ctx, operands.NewVMImportForCR(hco))).To(BeNil())
+			Expect(cli.Delete(ctx, operands.NewVMImportWithNameOnly(hco))).To(BeNil())
 			wh := &WebhookHandler{}
 			wh.Init(logger, cli, HcoValidNamespace, true)
 
 			newHco := &v1beta1.HyperConverged{
+				// the same name, so only the deleted CR is missing
+				ObjectMeta: metav1.ObjectMeta{Name: hco.Name},
 				Spec: v1beta1.HyperConvergedSpec{
 					Infra: v1beta1.HyperConvergedConfig{
 						NodePlacement: newHyperConvergedConfig(),
@@ -433,7 +569,7 @@ var _ = Describe("webhooks handler", func() {
 				},
 			}
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
@@ -460,7 +596,7 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).NotTo(BeNil())
 			Expect(err).Should(Equal(ErrFakeVMImportError))
 		})
@@ -487,7 +623,7 @@ var _ = Describe("webhooks handler", func() {
 			// change something in workloads to trigger dry-run update
 			newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-			err := wh.ValidateUpdate(newHco, hco)
+			_, err := wh.ValidateUpdate(newHco, hco)
 			Expect(err).To(HaveOccurred())
 			Expect(err).Should(Equal(context.DeadlineExceeded))
 		})
@@ -512,7 +648,8 @@ var _ = Describe("webhooks handler", func() {
 			newHco := &v1beta1.HyperConverged{}
 			hco.DeepCopyInto(newHco)
 
-			Expect(wh.ValidateUpdate(newHco, hco)).ToNot(HaveOccurred())
+			_, err := wh.ValidateUpdate(newHco, hco)
+			Expect(err).ToNot(HaveOccurred())
 		})
 
 		Context("plain-k8s tests", func() {
@@ -527,6 +664,8 @@ var _ = Describe("webhooks handler", func() {
 				wh.Init(logger, cli, HcoValidNamespace, false)
 
 				newHco := &v1beta1.HyperConverged{
+					// the same name, so only the deleted CR is missing
+					ObjectMeta: metav1.ObjectMeta{Name: hco.Name},
 					Spec: v1beta1.HyperConvergedSpec{
 						Infra: v1beta1.HyperConvergedConfig{
 							NodePlacement: newHyperConvergedConfig(),
@@ -537,7 +676,7 @@ var _ = Describe("webhooks handler", func() {
 					},
 				}
 
-				err = wh.ValidateUpdate(newHco, hco)
+				_, err = wh.ValidateUpdate(newHco, hco)
 				Expect(err).NotTo(BeNil())
 				Expect(apierrors.IsNotFound(err)).To(BeTrue())
 			})
@@ -564,7 +703,7 @@ var _ = Describe("webhooks handler", func() {
 				// change something in workloads to trigger dry-run update
 				newHco.Spec.Workloads.NodePlacement.NodeSelector["a change"] = "Something else"
 
-				err := wh.ValidateUpdate(newHco, hco)
+				_, err := wh.ValidateUpdate(newHco, hco)
 				Expect(err).NotTo(BeNil())
 				Expect(err).Should(Equal(ErrFakeVMImportError))
 			})
@@ -704,6 +843,64 @@ var _ = Describe("webhooks handler", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err).Should(Equal(ErrFakeCdiError))
 		})
+
+		It("should dry-run the deletion of all the operand resources", func() {
+			hco := &v1beta1.HyperConverged{}
+			cli := getFakeClient(hco)
+			ctx := context.TODO()
+
+			qs := &consolev1.ConsoleQuickStart{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-quick-start",
+					Labels: operands.NewKubeVirtWithNameOnly(hco).Labels,
+				},
+			}
+			Expect(cli.Create(ctx, qs)).To(Succeed())
+			Expect(cli.Create(ctx, operands.NewConsoleCLIDownload(hco))).To(Succeed())
+			Expect(cli.Create(ctx, operands.NewHostPathProvisionerWithNameOnly(hco))).To(Succeed())
+			Expect(cli.Create(ctx, operands.NewHppStorageClass(hco))).To(Succeed())
+
+			var deleted []string
+			cli.InitiateDeleteErrors(func(obj client.Object) error {
+				deleted = append(deleted, obj.GetObjectKind().GroupVersionKind().Kind)
+				return nil
+			})
+
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			Expect(wh.ValidateDelete(hco)).To(Succeed())
+			Expect(deleted).To(ConsistOf("KubeVirt", "CDI", "NetworkAddonsConfig", "SSP", "ConsoleCLIDownload", "VMImportConfig",
+				"HostPathProvisioner", "StorageClass", "ConsoleQuickStart"))
+
+			By("Validate that the quick start still exists, as it a dry-run deletion")
+			Expect(util.GetRuntimeObject(ctx, cli, qs, logger)).To(Succeed())
+		})
+
+		It("should return the errors of all the failed deletions", func() {
+			hco := &v1beta1.HyperConverged{}
+			cli := getFakeClient(hco)
+
+			cli.InitiateDeleteErrors(func(obj client.Object) error {
+				switch obj.GetObjectKind().GroupVersionKind().Kind {
+				case "NetworkAddonsConfig":
+					return ErrFakeNetworkError
+				case "VMImportConfig":
+					return ErrFakeVMImportError
+				}
+				return nil
+			})
+
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			err := wh.ValidateDelete(hco)
+			Expect(err).To(HaveOccurred())
+
+			aggregated, ok := err.(utilerrors.Aggregate)
+			Expect(ok).To(BeTrue())
+			Expect(aggregated.Errors()).To(ConsistOf(ErrFakeNetworkError, ErrFakeVMImportError))
+		})
 	})
 
 	Context("unsupported annotation", func() {
@@ -729,7 +926,7 @@ var _ = Describe("webhooks handler", func() {
 				hco.DeepCopyInto(newHco)
 				hco.Annotations = map[string]string{annotationName: annotation}
 
-				err := wh.ValidateUpdate(newHco, hco)
+				_, err := wh.ValidateUpdate(newHco, hco)
 				Expect(err).ToNot(HaveOccurred())
 			},
 			Entry("should accept if kv annotation is valid", common.JSONPatchKVAnnotationName, validKvAnnotation),
@@ -749,7 +946,7 @@ var _ = Describe("webhooks handler", func() {
 				hco.DeepCopyInto(newHco)
 				newHco.Annotations = map[string]string{annotationName: annotation}
 
-				err := wh.ValidateUpdate(newHco, hco)
+				_, err := wh.ValidateUpdate(newHco, hco)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("invalid jsonPatch in the %s", annotationName))
 				fmt.Fprintf(GinkgoWriter, "Expected error: %v\n", err)
@@ -758,7 +955,198 @@ var _ = Describe("webhooks handler", func() {
 			Entry("should reject if kv annotation is invalid", common.JSONPatchKVAnnotationName, invalidKvAnnotation),
 			Entry("should reject if cdi annotation is invalid", common.JSONPatchCDIAnnotationName, invalidCdiAnnotation),
 			Entry("should reject if cna annotation is invalid", common.JSONPatchCNAOAnnotationName, invalidCnaAnnotation),
+			Entry("should reject if ssp annotation is invalid", common.JSONPatchSSPAnnotationName, `[{"op": "add", "path": "/status/phase", "value": "Deployed"}]`),
+			Entry("should reject if kubevirt-config annotation is invalid", common.JSONPatchKVConfigAnnotationName, `[{"op": "remove", "path": "/data/not-a-key"}]`),
+		)
+	})
+
+	Context("validate the CPU models of the workload nodes", func() {
+		enabled := true
+
+		newNode := func(name string, labels map[string]string, models ...string) *corev1.Node {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{},
+				},
+			}
+			for key, value := range labels {
+				node.Labels[key] = value
+			}
+			for _, model := range models {
+				node.Labels[util.CPUModelLabelPrefix+model] = "true"
+			}
+			return node
+		}
+
+		var (
+			cr    *v1beta1.HyperConverged
+			nodes []runtime.Object
 		)
+
+		BeforeEach(func() {
+			Expect(os.Setenv("OPERATOR_NAMESPACE", HcoValidNamespace)).To(BeNil())
+			cr = &v1beta1.HyperConverged{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ResourceName,
+					Namespace: HcoValidNamespace,
+				},
+				Spec: v1beta1.HyperConvergedSpec{
+					FeatureGates: &v1beta1.HyperConvergedFeatureGates{
+						WithHostPassthroughCPU: &enabled,
+					},
+				},
+			}
+			nodes = []runtime.Object{
+				newNode("node1", map[string]string{"cpu": "new"}, "Haswell", "IvyBridge"),
+				newNode("node2", map[string]string{"cpu": "new"}, "Haswell", "IvyBridge"),
+				newNode("node3", map[string]string{"cpu": "old"}, "IvyBridge"),
+				// not labelled yet
+				newNode("node4", nil),
+			}
+		})
+
+		It("should reject the creation with heterogeneous workload nodes", func() {
+			wh := &WebhookHandler{}
+			wh.Init(logger, commonTestUtils.InitClient(nodes), HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("2 distinct sets of CPU models"))
+			Expect(err.Error()).To(ContainSubstring("[Haswell,IvyBridge] on nodes node1, node2"))
+			Expect(err.Error()).To(ContainSubstring("[IvyBridge] on nodes node3"))
+		})
+
+		It("should accept the creation if the feature gate is disabled", func() {
+			cr.Spec.FeatureGates.WithHostPassthroughCPU = nil
+			wh := &WebhookHandler{}
+			wh.Init(logger, commonTestUtils.InitClient(nodes), HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only check the nodes that are selected by the workloads node placement", func() {
+			cr.Spec.Workloads.NodePlacement = &sdkapi.NodePlacement{
+				NodeSelector: map[string]string{"cpu": "new"},
+			}
+			wh := &WebhookHandler{}
+			wh.Init(logger, commonTestUtils.InitClient(nodes), HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should check the required node affinity and the taints", func() {
+			tainted := newNode("node5", nil, "Skylake")
+			tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule}}
+			nodes = append(nodes, tainted)
+
+			cr.Spec.Workloads.NodePlacement = &sdkapi.NodePlacement{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "cpu", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"old"}}}},
+							},
+						},
+					},
+				},
+			}
+			wh := &WebhookHandler{}
+			wh.Init(logger, commonTestUtils.InitClient(nodes), HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+
+			cr.Spec.Workloads.NodePlacement.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+			_, err = wh.ValidateCreate(cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("[Skylake] on nodes node5"))
+		})
+
+		It("should reject enabling the feature gate with heterogeneous workload nodes", func() {
+			exists := cr.DeepCopy()
+			exists.Spec.FeatureGates.WithHostPassthroughCPU = nil
+			cli := getFakeClient(exists)
+			for _, node := range nodes {
+				Expect(cli.Create(context.TODO(), node.(client.Object))).To(Succeed())
+			}
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			_, err := wh.ValidateUpdate(cr, exists)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("distinct sets of CPU models"))
+		})
+
+		It("should not reject other updates if the feature gate is already enabled", func() {
+			cli := getFakeClient(cr.DeepCopy())
+			for _, node := range nodes {
+				Expect(cli.Create(context.TODO(), node.(client.Object))).To(Succeed())
+			}
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			exists := cr.DeepCopy()
+			cr.Spec.FeatureGates.HotplugVolumes = &enabled
+			_, err := wh.ValidateUpdate(cr, exists)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("validate the HostPath Provisioner storage pool", func() {
+		var cr *v1beta1.HyperConverged
+
+		BeforeEach(func() {
+			Expect(os.Setenv("OPERATOR_NAMESPACE", HcoValidNamespace)).To(BeNil())
+			cr = &v1beta1.HyperConverged{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ResourceName,
+					Namespace: HcoValidNamespace,
+				},
+				Spec: v1beta1.HyperConvergedSpec{
+					HostPathProvisioner: &v1beta1.HostPathProvisionerConfig{Path: "/var/hpvolumes", StoragePool: "local"},
+				},
+			}
+		})
+
+		It("should reject the creation if a StorageClass that was not created by HCO has the storage pool name", func() {
+			sc := operands.NewHppStorageClass(cr)
+			sc.Labels = nil
+			wh := &WebhookHandler{}
+			wh.Init(logger, commonTestUtils.InitClient([]runtime.Object{sc}), HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the local StorageClass already exists, and was not created by HCO"))
+		})
+
+		It("should accept the creation if the StorageClass was created by HCO", func() {
+			wh := &WebhookHandler{}
+			wh.Init(logger, commonTestUtils.InitClient([]runtime.Object{operands.NewHppStorageClass(cr)}), HcoValidNamespace, true)
+
+			_, err := wh.ValidateCreate(cr)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject renaming the storage pool after a StorageClass that was not created by HCO", func() {
+			exists := cr.DeepCopy()
+			cli := getFakeClient(exists)
+			Expect(cli.Create(context.TODO(), operands.NewHppStorageClass(exists))).To(Succeed())
+
+			cr.Spec.HostPathProvisioner.StoragePool = "standard"
+			sc := operands.NewHppStorageClass(cr)
+			sc.Labels = map[string]string{util.AppLabel: ResourceName}
+			Expect(cli.Create(context.TODO(), sc)).To(Succeed())
+
+			wh := &WebhookHandler{}
+			wh.Init(logger, cli, HcoValidNamespace, true)
+
+			_, err := wh.ValidateUpdate(cr, exists)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the standard StorageClass already exists, and was not created by HCO"))
+		})
 	})
 
 	Context("Check mutating webhook for namespace deletion", func() {
@@ -880,7 +1268,13 @@ func getFakeClient(hco *v1beta1.HyperConverged) *commonTestUtils.HcoTestClient {
 	cna, err := operands.NewNetworkAddons(hco)
 	Expect(err).ToNot(HaveOccurred())
 
-	return commonTestUtils.InitClient([]runtime.Object{hco, kv, cdi, cna, operands.NewSSP(hco), operands.NewVMImportForCR(hco)})
+	ssp, err := operands.NewSSP(hco)
+	Expect(err).ToNot(HaveOccurred())
+
+	vmImport, err := operands.NewVMImportForCR(hco)
+	Expect(err).ToNot(HaveOccurred())
+
+	return commonTestUtils.InitClient([]runtime.Object{hco, kv, cdi, cna, ssp, vmImport})
 }
 
 type fakeFailure int
@@ -130,7 +130,7 @@ func main() {
 	}
 
 	serviceAccounts := map[string]v1.ServiceAccount{
-		"hyperconverged-cluster-operator": components.GetServiceAccount(*operatorNamespace),
+		hcoutil.HCOServiceAccountName: components.GetServiceAccount(*operatorNamespace),
 	}
 	permissions := make([]rbacv1.Role, 0)
 	roleBindings := make([]rbacv1.RoleBinding, 0)